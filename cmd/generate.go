@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/generate"
+)
+
+var (
+	rulesFile     string
+	dashboardFile string
+)
+
+// generateCmd writes Prometheus alerting rules and a Grafana dashboard for the config.
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate Prometheus alerting rules and a Grafana dashboard from the config",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := initCommon()
+
+		if err := cfg.Validate(); err != nil {
+			log.WithError(err).Fatal("invalid config")
+		}
+
+		if rulesFile != "" {
+			rules, err := generate.MarshalRules(cfg)
+			if err != nil {
+				log.WithError(err).Fatal("failed to generate rules")
+			}
+
+			if err := os.WriteFile(rulesFile, rules, 0o600); err != nil {
+				log.WithError(err).Fatal("failed to write rules")
+			}
+
+			log.WithField("file", rulesFile).Info("Wrote Prometheus rules")
+		}
+
+		if dashboardFile != "" {
+			dashboard, err := generate.MarshalDashboard(cfg)
+			if err != nil {
+				log.WithError(err).Fatal("failed to generate dashboard")
+			}
+
+			if err := os.WriteFile(dashboardFile, dashboard, 0o600); err != nil {
+				log.WithError(err).Fatal("failed to write dashboard")
+			}
+
+			log.WithField("file", dashboardFile).Info("Wrote Grafana dashboard")
+		}
+	},
+}
+
+func init() {
+	generateCmd.Flags().StringVar(&rulesFile, "rules-file", "rules.yaml", "file to write the Prometheus rules to, empty to skip")
+	generateCmd.Flags().StringVar(&dashboardFile, "dashboard-file", "dashboard.json", "file to write the Grafana dashboard to, empty to skip")
+
+	rootCmd.AddCommand(generateCmd)
+}
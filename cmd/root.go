@@ -18,7 +18,7 @@ var rootCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := initCommon()
 
-		export := exporter.NewExporter(log, cfg)
+		export := exporter.NewExporter(log, cfg, nil, nil)
 		if err := export.Start(cmd.Context()); err != nil {
 			log.WithError(err).Fatal("failed to init")
 		}
@@ -33,10 +33,16 @@ var (
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
 		os.Exit(1)
 	}
+
+	// Only the root command keeps running until it is signalled, subcommands
+	// such as generate are done once they return.
+	if cmd != rootCmd {
+		os.Exit(0)
+	}
 }
 
 func init() {
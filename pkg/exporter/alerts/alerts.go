@@ -0,0 +1,293 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+
+	resultSent   = "sent"
+	resultFailed = "failed"
+
+	labelWebhook = "webhook"
+	labelStatus  = "status"
+	labelResult  = "result"
+)
+
+// Target identifies the value of an address alerts are evaluated on.
+type Target struct {
+	Job       string
+	Name      string
+	Execution string
+}
+
+// Manager evaluates alert rules against observed values and notifies webhooks
+// when an alert fires or resolves. A nil Manager ignores every call.
+type Manager struct {
+	log           logrus.FieldLogger
+	webhooks      map[string]*webhook
+	notifications prometheus.CounterVec
+
+	mu     sync.Mutex
+	series map[Target]*series
+}
+
+type webhook struct {
+	config   *Webhook
+	template *template.Template
+	client   *http.Client
+}
+
+type series struct {
+	rules    []*Rule
+	samples  []sample
+	lastSeen time.Time
+	states   map[string]*state
+}
+
+type sample struct {
+	at    time.Time
+	value float64
+}
+
+type state struct {
+	pendingSince time.Time
+	firing       bool
+	startsAt     time.Time
+
+	// notification is the last firing or resolved notification, sent again on
+	// each evaluation to the webhooks in undelivered until they accept it.
+	notification *Notification
+	undelivered  []string
+}
+
+// NewManager returns a new Manager sending notifications to the configured webhooks.
+func NewManager(log logrus.FieldLogger, config *Config, namespace string, constLabels map[string]string, registerer prometheus.Registerer) (*Manager, error) {
+	m := &Manager{
+		log:      log.WithField("component", "alerts"),
+		webhooks: make(map[string]*webhook, len(config.Webhooks)),
+		series:   make(map[Target]*series),
+		notifications: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace + "_alerts",
+				Name:        "notifications_total",
+				Help:        "The total alert notifications sent to a webhook.",
+				ConstLabels: constLabels,
+			},
+			[]string{labelWebhook, labelStatus, labelResult},
+		),
+	}
+
+	for _, config := range config.Webhooks {
+		tmpl, err := parseTemplate(config)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: %w", config.Name, err)
+		}
+
+		timeout := config.Timeout
+		if timeout == 0 {
+			timeout = defaultWebhookTimeout
+		}
+
+		m.webhooks[config.Name] = &webhook{
+			config:   config,
+			template: tmpl,
+			client:   &http.Client{Timeout: timeout},
+		}
+	}
+
+	registerer.MustRegister(m.notifications)
+
+	return m, nil
+}
+
+// Observe records a successfully updated value of a target.
+func (m *Manager) Observe(target Target, rules []*Rule, value float64, now time.Time) {
+	if m == nil || len(rules) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.series[target]
+	if !ok {
+		s = &series{states: make(map[string]*state)}
+		m.series[target] = s
+	}
+
+	s.rules = rules
+	s.lastSeen = now
+	s.samples = append(s.samples, sample{at: now, value: value})
+
+	window := time.Duration(0)
+	for _, rule := range rules {
+		window = max(window, rule.changeWindow())
+	}
+
+	first := 0
+	for first < len(s.samples)-1 && now.Sub(s.samples[first].at) > window {
+		first++
+	}
+
+	s.samples = s.samples[first:]
+}
+
+// Evaluate evaluates the rules of every target of a job, typically after each
+// of its ticks, and sends the resulting firing and resolved notifications.
+// Webhooks that fail to accept a notification are retried on the next
+// evaluation, until they accept it or the alert changes state.
+func (m *Manager) Evaluate(ctx context.Context, job string, now time.Time) {
+	if m == nil {
+		return
+	}
+
+	var deliveries []*delivery
+
+	m.mu.Lock()
+
+	for target, s := range m.series {
+		if target.Job != job {
+			continue
+		}
+
+		for _, rule := range s.rules {
+			notification := s.evaluate(target, rule, now)
+			st := s.states[rule.Name]
+
+			if notification != nil {
+				webhooks := m.webhookNames(rule)
+
+				// Only the webhooks that were told an alert fired are told it resolved.
+				if notification.Status == StatusResolved {
+					webhooks = slices.DeleteFunc(webhooks, func(name string) bool {
+						return slices.Contains(st.undelivered, name)
+					})
+				}
+
+				st.notification = notification
+				st.undelivered = webhooks
+			}
+
+			if len(st.undelivered) > 0 {
+				deliveries = append(deliveries, &delivery{state: st, notification: st.notification, webhooks: st.undelivered})
+			}
+		}
+	}
+
+	m.mu.Unlock()
+
+	for _, d := range deliveries {
+		d.failed = m.notify(ctx, d.notification, d.webhooks)
+	}
+
+	m.mu.Lock()
+
+	for _, d := range deliveries {
+		if d.state.notification == d.notification {
+			d.state.undelivered = d.failed
+		}
+	}
+
+	m.mu.Unlock()
+}
+
+// delivery is a notification to send to the webhooks that have not accepted
+// it yet.
+type delivery struct {
+	state        *state
+	notification *Notification
+	webhooks     []string
+	failed       []string
+}
+
+// evaluate advances the state of a rule, returning a notification when the
+// alert starts firing after its For duration or resolves.
+func (s *series) evaluate(target Target, rule *Rule, now time.Time) *Notification {
+	st, ok := s.states[rule.Name]
+	if !ok {
+		st = &state{}
+		s.states[rule.Name] = st
+	}
+
+	reasons := s.check(rule, now)
+	value := s.samples[len(s.samples)-1].value
+
+	if len(reasons) == 0 {
+		st.pendingSince = time.Time{}
+
+		if !st.firing {
+			return nil
+		}
+
+		st.firing = false
+
+		return newNotification(StatusResolved, target, rule, value, "", st.startsAt, now)
+	}
+
+	if st.pendingSince.IsZero() {
+		st.pendingSince = now
+	}
+
+	if st.firing || now.Sub(st.pendingSince) < rule.For {
+		return nil
+	}
+
+	st.firing = true
+	st.startsAt = now
+
+	return newNotification(StatusFiring, target, rule, value, strings.Join(reasons, ", "), now, time.Time{})
+}
+
+// check returns the reasons the conditions of a rule hold, if any.
+func (s *series) check(rule *Rule, now time.Time) []string {
+	var reasons []string
+
+	if rule.Stale > 0 && now.Sub(s.lastSeen) > rule.Stale {
+		reasons = append(reasons, fmt.Sprintf("not updated for %s", now.Sub(s.lastSeen).Round(time.Second)))
+	}
+
+	value := s.samples[len(s.samples)-1].value
+
+	if rule.Min != nil && value < *rule.Min {
+		reasons = append(reasons, fmt.Sprintf("value %g is below the minimum %g", value, *rule.Min))
+	}
+
+	if rule.Max != nil && value > *rule.Max {
+		reasons = append(reasons, fmt.Sprintf("value %g is above the maximum %g", value, *rule.Max))
+	}
+
+	if rule.ChangePercent != nil {
+		window := rule.changeWindow()
+
+		for _, base := range s.samples {
+			if now.Sub(base.at) > window {
+				continue
+			}
+
+			if base.value != 0 {
+				change := math.Abs(value-base.value) / math.Abs(base.value) * 100
+				if change >= *rule.ChangePercent {
+					reasons = append(reasons, fmt.Sprintf("value changed by %.2f%% within %s", change, window))
+				}
+			}
+
+			break
+		}
+	}
+
+	return reasons
+}
@@ -0,0 +1,329 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testManagerCounter atomic.Int64
+
+type receiver struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+	status int
+}
+
+func newReceiver(t *testing.T) (*receiver, *httptest.Server) {
+	t.Helper()
+
+	r := &receiver{status: http.StatusOK}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		decoded := map[string]any{}
+		assert.NoError(t, json.Unmarshal(body, &decoded))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+		r.mu.Lock()
+		r.bodies = append(r.bodies, decoded)
+		status := r.status
+		r.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return r, server
+}
+
+func newTestManager(t *testing.T, webhooks ...*Webhook) *Manager {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	namespace := "alerts_test_" + strconv.FormatInt(testManagerCounter.Add(1), 10)
+
+	m, err := NewManager(log, &Config{Webhooks: webhooks}, namespace, map[string]string{}, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	return m
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func TestManager_MinFiresAfterForAndResolves(t *testing.T) {
+	r, server := newReceiver(t)
+	m := newTestManager(t, &Webhook{Name: "ops", URL: server.URL})
+
+	target := Target{Job: "account", Name: "relayer", Execution: "geth"}
+	rules := []*Rule{{Name: "low balance", Min: float64Ptr(10), For: time.Minute}}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	m.Observe(target, rules, 5, start)
+	m.Evaluate(context.Background(), "account", start)
+	assert.Empty(t, r.bodies)
+
+	// Another job's evaluation does not evaluate the targets of this job.
+	m.Evaluate(context.Background(), "erc20", start.Add(2*time.Minute))
+	assert.Empty(t, r.bodies)
+
+	m.Observe(target, rules, 4, start.Add(time.Minute))
+	m.Evaluate(context.Background(), "account", start.Add(time.Minute))
+	require.Len(t, r.bodies, 1)
+	assert.Equal(t, StatusFiring, r.bodies[0]["status"])
+	assert.Equal(t, "low balance", r.bodies[0]["alert"])
+	assert.Equal(t, "relayer", r.bodies[0]["name"])
+	assert.Equal(t, "geth", r.bodies[0]["execution"])
+	assert.InDelta(t, 4, r.bodies[0]["value"], 0)
+	assert.Equal(t, "value 4 is below the minimum 10", r.bodies[0]["reason"])
+
+	// A firing alert is not sent again while it keeps firing.
+	m.Observe(target, rules, 3, start.Add(2*time.Minute))
+	m.Evaluate(context.Background(), "account", start.Add(2*time.Minute))
+	require.Len(t, r.bodies, 1)
+
+	m.Observe(target, rules, 20, start.Add(3*time.Minute))
+	m.Evaluate(context.Background(), "account", start.Add(3*time.Minute))
+	require.Len(t, r.bodies, 2)
+	assert.Equal(t, StatusResolved, r.bodies[1]["status"])
+	assert.Contains(t, r.bodies[1], "endsAt")
+
+	assert.InDelta(t, 1, testutil.ToFloat64(m.notifications.WithLabelValues("ops", StatusFiring, resultSent)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.notifications.WithLabelValues("ops", StatusResolved, resultSent)), 0)
+}
+
+func TestManager_ChangePercentAndStale(t *testing.T) {
+	r, server := newReceiver(t)
+	m := newTestManager(t, &Webhook{Name: "ops", URL: server.URL})
+
+	target := Target{Job: "erc20", Name: "hot wallet", Execution: "geth"}
+	rules := []*Rule{
+		{Name: "drain", ChangePercent: float64Ptr(50), ChangeWindow: 10 * time.Minute},
+		{Name: "stale", Stale: 5 * time.Minute},
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	m.Observe(target, rules, 100, start)
+	m.Observe(target, rules, 60, start.Add(5*time.Minute))
+	m.Evaluate(context.Background(), "erc20", start.Add(5*time.Minute))
+	assert.Empty(t, r.bodies)
+
+	m.Observe(target, rules, 40, start.Add(8*time.Minute))
+	m.Evaluate(context.Background(), "erc20", start.Add(8*time.Minute))
+	require.Len(t, r.bodies, 1)
+	assert.Equal(t, "drain", r.bodies[0]["alert"])
+	assert.Equal(t, "value changed by 60.00% within 10m0s", r.bodies[0]["reason"])
+
+	// No update within the stale duration fires the staleness alert, while the
+	// change within the window resolves once the older samples fall out of it.
+	m.Evaluate(context.Background(), "erc20", start.Add(14*time.Minute))
+	require.Len(t, r.bodies, 3)
+
+	for _, body := range r.bodies[1:] {
+		switch body["alert"] {
+		case "drain":
+			assert.Equal(t, StatusResolved, body["status"])
+		case "stale":
+			assert.Equal(t, StatusFiring, body["status"])
+			assert.Equal(t, "not updated for 6m0s", body["reason"])
+		default:
+			t.Fatalf("unexpected alert %v", body["alert"])
+		}
+	}
+}
+
+func TestManager_TemplateAndFailedNotifications(t *testing.T) {
+	r, server := newReceiver(t)
+	r.status = http.StatusInternalServerError
+
+	m := newTestManager(t,
+		&Webhook{Name: "slack", URL: server.URL, Template: `{"text":{{json (printf "%s %s: %s" .Status .Name .Reason)}}}`},
+		&Webhook{Name: "unused", URL: server.URL},
+	)
+
+	target := Target{Job: "account", Name: "relayer", Execution: "geth"}
+	rules := []*Rule{{Name: "high balance", Max: float64Ptr(1), Webhooks: []string{"slack"}}}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	m.Observe(target, rules, 2, now)
+	m.Evaluate(context.Background(), "account", now)
+
+	require.Len(t, r.bodies, 1)
+	assert.Equal(t, "firing relayer: value 2 is above the maximum 1", r.bodies[0]["text"])
+	assert.InDelta(t, 1, testutil.ToFloat64(m.notifications.WithLabelValues("slack", StatusFiring, resultFailed)), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(&m.notifications))
+}
+
+func TestManager_RetriesFailedNotifications(t *testing.T) {
+	ops, opsServer := newReceiver(t)
+	pager, pagerServer := newReceiver(t)
+	pager.status = http.StatusBadGateway
+
+	m := newTestManager(t, &Webhook{Name: "ops", URL: opsServer.URL}, &Webhook{Name: "pager", URL: pagerServer.URL})
+
+	target := Target{Job: "account", Name: "relayer", Execution: "geth"}
+	rules := []*Rule{{Name: "low balance", Min: float64Ptr(10)}}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	m.Observe(target, rules, 5, start)
+	m.Evaluate(context.Background(), "account", start)
+	require.Len(t, ops.bodies, 1)
+	require.Len(t, pager.bodies, 1)
+
+	// Only the webhook that failed receives the firing notification again.
+	m.Evaluate(context.Background(), "account", start.Add(time.Minute))
+	require.Len(t, ops.bodies, 1)
+	require.Len(t, pager.bodies, 2)
+
+	pager.mu.Lock()
+	pager.status = http.StatusOK
+	pager.mu.Unlock()
+
+	m.Evaluate(context.Background(), "account", start.Add(2*time.Minute))
+	require.Len(t, pager.bodies, 3)
+	assert.Equal(t, StatusFiring, pager.bodies[2]["status"])
+
+	m.Evaluate(context.Background(), "account", start.Add(3*time.Minute))
+	require.Len(t, pager.bodies, 3)
+
+	m.Observe(target, rules, 20, start.Add(4*time.Minute))
+	m.Evaluate(context.Background(), "account", start.Add(4*time.Minute))
+	require.Len(t, ops.bodies, 2)
+	require.Len(t, pager.bodies, 4)
+	assert.Equal(t, StatusResolved, pager.bodies[3]["status"])
+
+	assert.InDelta(t, 2, testutil.ToFloat64(m.notifications.WithLabelValues("pager", StatusFiring, resultFailed)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.notifications.WithLabelValues("pager", StatusFiring, resultSent)), 0)
+}
+
+func TestManager_ResolvedOnlyAfterFiringDelivered(t *testing.T) {
+	r, server := newReceiver(t)
+	r.status = http.StatusInternalServerError
+
+	m := newTestManager(t, &Webhook{Name: "ops", URL: server.URL})
+
+	target := Target{Job: "account", Name: "relayer", Execution: "geth"}
+	rules := []*Rule{{Name: "low balance", Min: float64Ptr(10)}}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	m.Observe(target, rules, 5, start)
+	m.Evaluate(context.Background(), "account", start)
+	require.Len(t, r.bodies, 1)
+
+	r.mu.Lock()
+	r.status = http.StatusOK
+	r.mu.Unlock()
+
+	// The webhook never accepted the firing notification, so it is not told
+	// the alert resolved.
+	m.Observe(target, rules, 20, start.Add(time.Minute))
+	m.Evaluate(context.Background(), "account", start.Add(time.Minute))
+	m.Evaluate(context.Background(), "account", start.Add(2*time.Minute))
+	require.Len(t, r.bodies, 1)
+}
+
+func TestManager_Nil(t *testing.T) {
+	var m *Manager
+
+	m.Observe(Target{}, []*Rule{{Name: "noop"}}, 1, time.Now())
+	m.Evaluate(context.Background(), "account", time.Now())
+}
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{value: 1.5, expected: `1.5`},
+		{value: `a"b`, expected: `"a\"b"`},
+		{value: math.Inf(1), expected: `"+Inf"`},
+	}
+
+	for _, tt := range tests {
+		encoded, err := toJSON(tt.value)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, encoded)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  *Config
+		rules   []*Rule
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: &Config{Webhooks: []*Webhook{{Name: "ops", URL: "https://hooks.example.com/ops"}}},
+			rules:  []*Rule{{Name: "low", Min: float64Ptr(1), Webhooks: []string{"ops"}}},
+		},
+		{
+			name:    "invalid url",
+			config:  &Config{Webhooks: []*Webhook{{Name: "ops", URL: "hooks"}}},
+			wantErr: "invalid url",
+		},
+		{
+			name:    "invalid template",
+			config:  &Config{Webhooks: []*Webhook{{Name: "ops", URL: "https://hooks.example.com", Template: "{{.Status"}}},
+			wantErr: "invalid template",
+		},
+		{
+			name:    "rule without condition",
+			config:  &Config{Webhooks: []*Webhook{{Name: "ops", URL: "https://hooks.example.com"}}},
+			rules:   []*Rule{{Name: "noop"}},
+			wantErr: "must set min, max, changePercent or stale",
+		},
+		{
+			name:    "rule with unknown webhook",
+			config:  &Config{Webhooks: []*Webhook{{Name: "ops", URL: "https://hooks.example.com"}}},
+			rules:   []*Rule{{Name: "low", Min: float64Ptr(1), Webhooks: []string{"pager"}}},
+			wantErr: "references unknown webhook pager",
+		},
+		{
+			name:    "rule without webhooks",
+			config:  &Config{},
+			rules:   []*Rule{{Name: "low", Min: float64Ptr(1)}},
+			wantErr: "without any alerting webhooks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.config.Validate()
+			if err == nil {
+				err = tt.config.ValidateRules("account relayer", tt.rules)
+			}
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
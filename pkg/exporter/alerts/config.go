@@ -0,0 +1,136 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"text/template"
+	"time"
+)
+
+const (
+	// defaultChangeWindow is the change percentage window used when none is configured.
+	defaultChangeWindow = time.Hour
+	// defaultWebhookTimeout is the webhook request timeout used when none is configured.
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// Config holds the webhooks alert notifications are sent to.
+type Config struct {
+	Webhooks []*Webhook `yaml:"webhooks"`
+}
+
+// Webhook is an HTTP endpoint receiving alert notifications as JSON.
+type Webhook struct {
+	Name    string            `yaml:"name"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout time.Duration     `yaml:"timeout"`
+	// Template is a text/template rendering the JSON body of a Notification, defaults to defaultTemplate.
+	Template string `yaml:"template"`
+}
+
+// Rule is an alert evaluated against the value of an address. The alert is
+// active while any of its conditions holds.
+type Rule struct {
+	Name string `yaml:"name"`
+	// Min and Max bound the value, in the unit of the metric it is evaluated on.
+	Min *float64 `yaml:"min"`
+	Max *float64 `yaml:"max"`
+	// ChangePercent is the absolute change of the value within ChangeWindow that activates the alert.
+	ChangePercent *float64      `yaml:"changePercent"`
+	ChangeWindow  time.Duration `yaml:"changeWindow"`
+	// Stale is how long the value may go without a successful update.
+	Stale time.Duration `yaml:"stale"`
+	// For is how long a condition must hold before the alert fires.
+	For time.Duration `yaml:"for"`
+	// Webhooks are the names of the webhooks to notify, defaults to every webhook.
+	Webhooks []string `yaml:"webhooks"`
+}
+
+// Validate checks the webhooks are named, reachable by URL and have a valid template.
+func (c *Config) Validate() error {
+	names := make(map[string]struct{}, len(c.Webhooks))
+
+	for i, webhook := range c.Webhooks {
+		if webhook.Name == "" {
+			return fmt.Errorf("alerting webhook at index %d must have a name", i)
+		}
+
+		if _, ok := names[webhook.Name]; ok {
+			return fmt.Errorf("duplicate alerting webhook with the same name: %s", webhook.Name)
+		}
+
+		names[webhook.Name] = struct{}{}
+
+		if parsed, err := url.Parse(webhook.URL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("alerting webhook %s: invalid url %q", webhook.Name, webhook.URL)
+		}
+
+		if _, err := parseTemplate(webhook); err != nil {
+			return fmt.Errorf("alerting webhook %s: invalid template: %w", webhook.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRules checks each rule has a unique name, at least one condition
+// and only references configured webhooks.
+func (c *Config) ValidateRules(owner string, rules []*Rule) error {
+	names := make(map[string]struct{}, len(rules))
+
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return fmt.Errorf("%s: alert must have a name", owner)
+		}
+
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("%s: duplicate alert with the same name: %s", owner, rule.Name)
+		}
+
+		names[rule.Name] = struct{}{}
+
+		if rule.Min == nil && rule.Max == nil && rule.ChangePercent == nil && rule.Stale == 0 {
+			return fmt.Errorf("%s: alert %s must set min, max, changePercent or stale", owner, rule.Name)
+		}
+
+		if rule.ChangePercent != nil && *rule.ChangePercent <= 0 {
+			return fmt.Errorf("%s: alert %s changePercent must be positive", owner, rule.Name)
+		}
+
+		if rule.ChangeWindow < 0 || rule.Stale < 0 || rule.For < 0 {
+			return fmt.Errorf("%s: alert %s durations must not be negative", owner, rule.Name)
+		}
+
+		if len(c.Webhooks) == 0 {
+			return errors.New("alerts are configured without any alerting webhooks")
+		}
+
+		for _, name := range rule.Webhooks {
+			if !slices.ContainsFunc(c.Webhooks, func(webhook *Webhook) bool { return webhook.Name == name }) {
+				return fmt.Errorf("%s: alert %s references unknown webhook %s", owner, rule.Name, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) changeWindow() time.Duration {
+	if r.ChangeWindow == 0 {
+		return defaultChangeWindow
+	}
+
+	return r.ChangeWindow
+}
+
+func parseTemplate(webhook *Webhook) (*template.Template, error) {
+	text := webhook.Template
+	if text == "" {
+		text = defaultTemplate
+	}
+
+	return template.New(webhook.Name).Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+}
@@ -0,0 +1,143 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultTemplate renders a Notification as a flat JSON object.
+const defaultTemplate = `{"status":{{json .Status}},"alert":{{json .Alert}},"job":{{json .Job}},"name":{{json .Name}},"execution":{{json .Execution}},"value":{{json .Value}},"reason":{{json .Reason}},"startsAt":{{json .StartsAt}}{{if not .EndsAt.IsZero}},"endsAt":{{json .EndsAt}}{{end}}}`
+
+// Notification is the data webhook templates are rendered with.
+type Notification struct {
+	Status    string
+	Alert     string
+	Job       string
+	Name      string
+	Execution string
+	Value     float64
+	Reason    string
+	StartsAt  time.Time
+	EndsAt    time.Time
+}
+
+func newNotification(status string, target Target, rule *Rule, value float64, reason string, startsAt, endsAt time.Time) *Notification {
+	return &Notification{
+		Status:    status,
+		Alert:     rule.Name,
+		Job:       target.Job,
+		Name:      target.Name,
+		Execution: target.Execution,
+		Value:     value,
+		Reason:    reason,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+	}
+}
+
+// webhookNames returns the webhooks of a rule, or every webhook when the rule
+// does not name any.
+func (m *Manager) webhookNames(rule *Rule) []string {
+	if len(rule.Webhooks) > 0 {
+		return slices.Clone(rule.Webhooks)
+	}
+
+	names := make([]string, 0, len(m.webhooks))
+	for name := range m.webhooks {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// notify sends a notification to the named webhooks, returning the names of
+// the webhooks that did not accept it.
+func (m *Manager) notify(ctx context.Context, notification *Notification, names []string) []string {
+	var failed []string
+
+	for _, name := range names {
+		webhook, ok := m.webhooks[name]
+		if !ok {
+			continue
+		}
+
+		result := resultSent
+
+		if err := webhook.send(ctx, notification); err != nil {
+			result = resultFailed
+			failed = append(failed, name)
+
+			m.log.WithError(err).WithFields(logrus.Fields{
+				labelWebhook: name,
+				"alert":      notification.Alert,
+				"name":       notification.Name,
+			}).Error("Failed to send alert notification")
+		}
+
+		m.notifications.WithLabelValues(name, notification.Status, result).Inc()
+	}
+
+	return failed
+}
+
+func (w *webhook) send(ctx context.Context, notification *Notification) error {
+	var body bytes.Buffer
+
+	if err := w.template.Execute(&body, notification); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	if !json.Valid(body.Bytes()) {
+		return errors.New("template rendered invalid json")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, &body)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range w.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("status code: %d", rsp.StatusCode)
+	}
+
+	return nil
+}
+
+// toJSON encodes a template value as JSON, writing non finite numbers as strings.
+func toJSON(value any) (string, error) {
+	if f, ok := value.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+		value = strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
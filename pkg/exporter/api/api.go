@@ -22,6 +22,28 @@ type ExecutionClient interface {
 	ETHCall(ctx context.Context, transaction *ETHCallTransaction, block string) (string, error)
 	// ETHGetBalance returns the balance of the account of given address.
 	ETHGetBalance(ctx context.Context, address string, block string) (string, error)
+	// ETHGetTransactionCount returns the number of transactions sent from an address.
+	ETHGetTransactionCount(ctx context.Context, address string, block string) (string, error)
+	// ETHGetCode returns the code at a given address.
+	ETHGetCode(ctx context.Context, address string, block string) (string, error)
+	// ETHGetStorageAt returns the value from a storage position at a given address.
+	ETHGetStorageAt(ctx context.Context, address string, slot string, block string) (string, error)
+	// ETHBlockNumber returns the number of the most recent block.
+	ETHBlockNumber(ctx context.Context) (string, error)
+	// ETHGetLogs returns the logs matching the given filter.
+	ETHGetLogs(ctx context.Context, filter *ETHGetLogsFilter) ([]*ETHLog, error)
+	// ETHGetBlockByNumber returns the block with the given number, including full transaction objects when requested.
+	ETHGetBlockByNumber(ctx context.Context, block string, fullTransactions bool) (*ETHBlock, error)
+	// ETHGetTransactionReceipt returns the receipt of the transaction with the given hash.
+	ETHGetTransactionReceipt(ctx context.Context, hash string) (*ETHTransactionReceipt, error)
+	// ETHChainID returns the chain ID used for signing replay-protected transactions.
+	ETHChainID(ctx context.Context) (string, error)
+	// ETHGasPrice returns the current gas price in wei.
+	ETHGasPrice(ctx context.Context) (string, error)
+	// ETHMaxPriorityFeePerGas returns the suggested priority fee per gas in wei.
+	ETHMaxPriorityFeePerGas(ctx context.Context) (string, error)
+	// ETHFeeHistory returns the fee market history of the given number of blocks up to the newest block.
+	ETHFeeHistory(ctx context.Context, blockCount uint64, newestBlock string, rewardPercentiles []float64) (*ETHFeeHistory, error)
 }
 
 // ETHCallTransaction represents an eth_call transaction object.
@@ -34,6 +56,64 @@ type ETHCallTransaction struct {
 	Data     *string `json:"data"`
 }
 
+// ETHGetLogsFilter represents an eth_getLogs filter object. A nil entry in
+// Topics matches any topic in that position.
+type ETHGetLogsFilter struct {
+	FromBlock string     `json:"fromBlock"`
+	ToBlock   string     `json:"toBlock"`
+	Address   []string   `json:"address,omitempty"`
+	Topics    [][]string `json:"topics,omitempty"`
+}
+
+// ETHLog represents a log returned by eth_getLogs.
+type ETHLog struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     string   `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+	LogIndex        string   `json:"logIndex"`
+	Removed         bool     `json:"removed"`
+}
+
+// ETHBlock represents a block returned by eth_getBlockByNumber. Transactions
+// are only populated when full transaction objects are requested.
+type ETHBlock struct {
+	Number       string            `json:"number"`
+	Hash         string            `json:"hash"`
+	Timestamp    string            `json:"timestamp"`
+	Transactions []*ETHTransaction `json:"transactions"`
+}
+
+// ETHTransaction represents a full transaction object of a block.
+type ETHTransaction struct {
+	Hash  string `json:"hash"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+}
+
+// ETHFeeHistory represents the result of eth_feeHistory. The base fees hold
+// one more entry than the gas used ratios, the base fee of the next block.
+type ETHFeeHistory struct {
+	OldestBlock       string     `json:"oldestBlock"`
+	BaseFeePerGas     []string   `json:"baseFeePerGas"`
+	GasUsedRatio      []float64  `json:"gasUsedRatio"`
+	BaseFeePerBlobGas []string   `json:"baseFeePerBlobGas"`
+	BlobGasUsedRatio  []float64  `json:"blobGasUsedRatio"`
+	Reward            [][]string `json:"reward"`
+}
+
+// ETHTransactionReceipt represents a receipt returned by eth_getTransactionReceipt.
+type ETHTransactionReceipt struct {
+	TransactionHash   string `json:"transactionHash"`
+	Status            string `json:"status"`
+	GasUsed           string `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
+	BlobGasUsed       string `json:"blobGasUsed"`
+	BlobGasPrice      string `json:"blobGasPrice"`
+}
+
 type executionClient struct {
 	name    string
 	url     string
@@ -149,42 +229,167 @@ func (e *executionClient) post(ctx context.Context, method string, params any, i
 	return resp.Result, nil
 }
 
+// postString executes an RPC call whose result is a single JSON string.
+func (e *executionClient) postString(ctx context.Context, method string, params []any) (string, error) {
+	rsp, err := e.post(ctx, method, params, 1)
+	if err != nil {
+		return "", err
+	}
+
+	result := ""
+
+	if unmarshalErr := json.Unmarshal(rsp, &result); unmarshalErr != nil {
+		return "", unmarshalErr
+	}
+
+	return result, nil
+}
+
 func (e *executionClient) ETHCall(ctx context.Context, transaction *ETHCallTransaction, block string) (string, error) {
 	params := []any{
 		transaction,
 		block,
 	}
 
-	rsp, err := e.post(ctx, "eth_call", params, 1)
-	if err != nil {
-		return "", err
+	return e.postString(ctx, "eth_call", params)
+}
+
+func (e *executionClient) ETHGetBalance(ctx context.Context, address, block string) (string, error) {
+	params := []any{
+		address,
+		block,
 	}
 
-	ethCall := ""
+	return e.postString(ctx, "eth_getBalance", params)
+}
 
-	if unmarshalErr := json.Unmarshal(rsp, &ethCall); unmarshalErr != nil {
-		return "", unmarshalErr
+func (e *executionClient) ETHGetTransactionCount(ctx context.Context, address, block string) (string, error) {
+	params := []any{
+		address,
+		block,
 	}
 
-	return ethCall, nil
+	return e.postString(ctx, "eth_getTransactionCount", params)
 }
 
-func (e *executionClient) ETHGetBalance(ctx context.Context, address, block string) (string, error) {
+func (e *executionClient) ETHGetCode(ctx context.Context, address, block string) (string, error) {
+	params := []any{
+		address,
+		block,
+	}
+
+	return e.postString(ctx, "eth_getCode", params)
+}
+
+func (e *executionClient) ETHGetStorageAt(ctx context.Context, address, slot, block string) (string, error) {
 	params := []any{
 		address,
+		slot,
 		block,
 	}
 
-	rsp, err := e.post(ctx, "eth_getBalance", params, 1)
+	return e.postString(ctx, "eth_getStorageAt", params)
+}
+
+func (e *executionClient) ETHBlockNumber(ctx context.Context) (string, error) {
+	return e.postString(ctx, "eth_blockNumber", []any{})
+}
+
+func (e *executionClient) ETHGetLogs(ctx context.Context, filter *ETHGetLogsFilter) ([]*ETHLog, error) {
+	params := []any{
+		filter,
+	}
+
+	rsp, err := e.post(ctx, "eth_getLogs", params, 1)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	ethGetBalance := ""
+	logs := make([]*ETHLog, 0)
 
-	if unmarshalErr := json.Unmarshal(rsp, &ethGetBalance); unmarshalErr != nil {
-		return "", unmarshalErr
+	if unmarshalErr := json.Unmarshal(rsp, &logs); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	return logs, nil
+}
+
+func (e *executionClient) ETHGetBlockByNumber(ctx context.Context, block string, fullTransactions bool) (*ETHBlock, error) {
+	params := []any{
+		block,
+		fullTransactions,
+	}
+
+	rsp, err := e.post(ctx, "eth_getBlockByNumber", params, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *ETHBlock
+
+	if unmarshalErr := json.Unmarshal(rsp, &result); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("block %s not found", block)
+	}
+
+	return result, nil
+}
+
+func (e *executionClient) ETHGetTransactionReceipt(ctx context.Context, hash string) (*ETHTransactionReceipt, error) {
+	params := []any{
+		hash,
+	}
+
+	rsp, err := e.post(ctx, "eth_getTransactionReceipt", params, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *ETHTransactionReceipt
+
+	if unmarshalErr := json.Unmarshal(rsp, &result); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("receipt of transaction %s not found", hash)
+	}
+
+	return result, nil
+}
+
+func (e *executionClient) ETHChainID(ctx context.Context) (string, error) {
+	return e.postString(ctx, "eth_chainId", []any{})
+}
+
+func (e *executionClient) ETHGasPrice(ctx context.Context) (string, error) {
+	return e.postString(ctx, "eth_gasPrice", []any{})
+}
+
+func (e *executionClient) ETHMaxPriorityFeePerGas(ctx context.Context) (string, error) {
+	return e.postString(ctx, "eth_maxPriorityFeePerGas", []any{})
+}
+
+func (e *executionClient) ETHFeeHistory(ctx context.Context, blockCount uint64, newestBlock string, rewardPercentiles []float64) (*ETHFeeHistory, error) {
+	params := []any{
+		"0x" + strconv.FormatUint(blockCount, 16),
+		newestBlock,
+		rewardPercentiles,
+	}
+
+	rsp, err := e.post(ctx, "eth_feeHistory", params, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(ETHFeeHistory)
+
+	if unmarshalErr := json.Unmarshal(rsp, result); unmarshalErr != nil {
+		return nil, unmarshalErr
 	}
 
-	return ethGetBalance, nil
+	return result, nil
 }
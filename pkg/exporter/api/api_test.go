@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -44,7 +45,7 @@ func newTestClient(t *testing.T, name, url string) ExecutionClient {
 
 	namespace := "test_" + strconv.FormatInt(n, 10)
 
-	return NewExecutionClient(log, NewMetrics(namespace), name, url, nil, 5*time.Second)
+	return NewExecutionClient(log, NewMetrics(namespace, prometheus.NewRegistry()), name, url, nil, 5*time.Second)
 }
 
 func TestExecutionClient_Name(t *testing.T) {
@@ -204,7 +205,7 @@ func TestExecutionClient_CustomHeaders(t *testing.T) {
 		"Authorization": "Bearer test-token",
 	}
 
-	client := NewExecutionClient(log, NewMetrics(namespace), "node-1", server.URL, headers, 5*time.Second)
+	client := NewExecutionClient(log, NewMetrics(namespace, prometheus.NewRegistry()), "node-1", server.URL, headers, 5*time.Second)
 
 	_, err := client.ETHGetBalance(context.Background(), "0x1234567890123456789012345678901234567890", "latest")
 	require.NoError(t, err)
@@ -227,7 +228,7 @@ func TestExecutionClient_Timeout(t *testing.T) {
 	n := testClientCounter.Add(1)
 	namespace := "test_timeout_" + strconv.FormatInt(n, 10)
 
-	client := NewExecutionClient(log, NewMetrics(namespace), "node-1", server.URL, nil, 50*time.Millisecond)
+	client := NewExecutionClient(log, NewMetrics(namespace, prometheus.NewRegistry()), "node-1", server.URL, nil, 50*time.Millisecond)
 
 	_, err := client.ETHGetBalance(context.Background(), "0x1234567890123456789012345678901234567890", "latest")
 	assert.Error(t, err)
@@ -241,3 +242,257 @@ func TestExecutionClient_ConnectionRefused(t *testing.T) {
 	_, err := client.ETHGetBalance(context.Background(), "0x1234567890123456789012345678901234567890", "latest")
 	assert.Error(t, err)
 }
+
+func TestExecutionClient_AccountMethods(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		wantMethod string
+		wantParams int
+		call       func(client ExecutionClient) (string, error)
+		result     string
+	}{
+		{
+			name:       "eth_getTransactionCount",
+			wantMethod: "eth_getTransactionCount",
+			wantParams: 2,
+			call: func(client ExecutionClient) (string, error) {
+				return client.ETHGetTransactionCount(context.Background(), "0x1234567890123456789012345678901234567890", "pending")
+			},
+			result: "0x2a",
+		},
+		{
+			name:       "eth_getCode",
+			wantMethod: "eth_getCode",
+			wantParams: 2,
+			call: func(client ExecutionClient) (string, error) {
+				return client.ETHGetCode(context.Background(), "0x1234567890123456789012345678901234567890", "latest")
+			},
+			result: "0xef01001111111111111111111111111111111111111111",
+		},
+		{
+			name:       "eth_getStorageAt",
+			wantMethod: "eth_getStorageAt",
+			wantParams: 3,
+			call: func(client ExecutionClient) (string, error) {
+				return client.ETHGetStorageAt(context.Background(), "0x1234567890123456789012345678901234567890", "0x0", "latest")
+			},
+			result: "0x0000000000000000000000000000000000000000000000000000000000000001",
+		},
+		{
+			name:       "eth_blockNumber",
+			wantMethod: "eth_blockNumber",
+			wantParams: 0,
+			call: func(client ExecutionClient) (string, error) {
+				return client.ETHBlockNumber(context.Background())
+			},
+			result: "0x1312d00",
+		},
+		{
+			name:       "eth_chainId",
+			wantMethod: "eth_chainId",
+			wantParams: 0,
+			call: func(client ExecutionClient) (string, error) {
+				return client.ETHChainID(context.Background())
+			},
+			result: "0x1",
+		},
+		{
+			name:       "eth_gasPrice",
+			wantMethod: "eth_gasPrice",
+			wantParams: 0,
+			call: func(client ExecutionClient) (string, error) {
+				return client.ETHGasPrice(context.Background())
+			},
+			result: "0x3b9aca00",
+		},
+		{
+			name:       "eth_maxPriorityFeePerGas",
+			wantMethod: "eth_maxPriorityFeePerGas",
+			wantParams: 0,
+			call: func(client ExecutionClient) (string, error) {
+				return client.ETHMaxPriorityFeePerGas(context.Background())
+			},
+			result: "0x5f5e100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				var req rpcRequest
+				if err := json.Unmarshal(body, &req); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				assert.Equal(t, tt.wantMethod, req.Method)
+				assert.Len(t, req.Params, tt.wantParams)
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"` + tt.result + `"}`))
+			})
+			client := newTestClient(t, "test-node", server.URL)
+
+			result, err := tt.call(client)
+			require.NoError(t, err)
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
+
+func TestExecutionClient_ETHGetLogs(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		assert.Equal(t, "eth_getLogs", req.Method)
+		require.Len(t, req.Params, 1)
+
+		filter, ok := req.Params[0].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "0x10", filter["fromBlock"])
+		assert.Equal(t, "0x20", filter["toBlock"])
+		assert.Equal(t, []any{[]any{"0xddf2"}, nil, []any{"0xbeef"}}, filter["topics"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":[{"address":"0x1234","topics":["0xddf2"],"data":"0x01","blockNumber":"0x11","transactionHash":"0xabcd","logIndex":"0x0","removed":false}]}`))
+	})
+	client := newTestClient(t, "test-node", server.URL)
+
+	logs, err := client.ETHGetLogs(context.Background(), &ETHGetLogsFilter{
+		FromBlock: "0x10",
+		ToBlock:   "0x20",
+		Topics:    [][]string{{"0xddf2"}, nil, {"0xbeef"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "0x1234", logs[0].Address)
+	assert.Equal(t, "0x11", logs[0].BlockNumber)
+	assert.Equal(t, []string{"0xddf2"}, logs[0].Topics)
+}
+
+func TestExecutionClient_ETHGetBlockByNumber(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		assert.Equal(t, "eth_getBlockByNumber", req.Method)
+		assert.Equal(t, []any{"0x10", true}, req.Params)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"number":"0x10","hash":"0xb10c","timestamp":"0x64","transactions":[{"hash":"0xabcd","from":"0x1111","to":"0x2222","value":"0x1"}]}}`))
+	})
+	client := newTestClient(t, "test-node", server.URL)
+
+	block, err := client.ETHGetBlockByNumber(context.Background(), "0x10", true)
+	require.NoError(t, err)
+	assert.Equal(t, "0x10", block.Number)
+	require.Len(t, block.Transactions, 1)
+	assert.Equal(t, "0x1111", block.Transactions[0].From)
+	assert.Equal(t, "0x2222", block.Transactions[0].To)
+	assert.Equal(t, "0x1", block.Transactions[0].Value)
+}
+
+func TestExecutionClient_ETHGetTransactionReceipt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		result    string
+		wantError bool
+	}{
+		{
+			name:   "receipt",
+			result: `{"transactionHash":"0xabcd","status":"0x1","gasUsed":"0x5208","effectiveGasPrice":"0x3b9aca00"}`,
+		},
+		{
+			name:      "not found",
+			result:    `null`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				var req rpcRequest
+				if err := json.Unmarshal(body, &req); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				assert.Equal(t, "eth_getTransactionReceipt", req.Method)
+				assert.Equal(t, []any{"0xabcd"}, req.Params)
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + tt.result + `}`))
+			})
+			client := newTestClient(t, "test-node", server.URL)
+
+			receipt, err := client.ETHGetTransactionReceipt(context.Background(), "0xabcd")
+
+			if tt.wantError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "0x1", receipt.Status)
+			assert.Equal(t, "0x5208", receipt.GasUsed)
+			assert.Equal(t, "0x3b9aca00", receipt.EffectiveGasPrice)
+		})
+	}
+}
+
+func TestExecutionClient_ETHFeeHistory(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		assert.Equal(t, "eth_feeHistory", req.Method)
+		assert.Equal(t, []any{"0x2", "latest", []any{25.0, 75.0}}, req.Params)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"oldestBlock":"0x10","baseFeePerGas":["0x1","0x2","0x3"],"gasUsedRatio":[0.5,0.25],"baseFeePerBlobGas":["0x1","0x1","0x1"],"blobGasUsedRatio":[0,1],"reward":[["0x1","0x2"],["0x3","0x4"]]}}`))
+	})
+	client := newTestClient(t, "test-node", server.URL)
+
+	history, err := client.ETHFeeHistory(context.Background(), 2, "latest", []float64{25, 75})
+	require.NoError(t, err)
+	assert.Equal(t, "0x10", history.OldestBlock)
+	assert.Equal(t, []string{"0x1", "0x2", "0x3"}, history.BaseFeePerGas)
+	assert.Equal(t, []float64{0.5, 0.25}, history.GasUsedRatio)
+	assert.Equal(t, [][]string{{"0x1", "0x2"}, {"0x3", "0x4"}}, history.Reward)
+}
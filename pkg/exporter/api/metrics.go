@@ -20,7 +20,9 @@ type Metrics struct {
 	requestDuration *prometheus.HistogramVec
 }
 
-func NewMetrics(namespace string) Metrics {
+// NewMetrics creates the request metrics of the execution clients and
+// registers them with registerer.
+func NewMetrics(namespace string, registerer prometheus.Registerer) Metrics {
 	m := Metrics{
 		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: namespace,
@@ -40,9 +42,9 @@ func NewMetrics(namespace string) Metrics {
 		}, []string{labelMethod, labelPath, labelAPIMethod, labelCode, labelExecution}),
 	}
 
-	prometheus.MustRegister(m.requests)
-	prometheus.MustRegister(m.responses)
-	prometheus.MustRegister(m.requestDuration)
+	registerer.MustRegister(m.requests)
+	registerer.MustRegister(m.responses)
+	registerer.MustRegister(m.requestDuration)
 
 	return m
 }
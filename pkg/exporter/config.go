@@ -3,8 +3,11 @@ package exporter
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
 )
 
@@ -15,6 +18,12 @@ type Config struct {
 	Execution []*ExecutionNode `yaml:"execution"`
 	// Addresses is the list of addresses to monitor.
 	Addresses Addresses `yaml:"addresses"`
+	// Network configures the gas and fee market metrics of the execution nodes.
+	Network jobs.NetworkConfig `yaml:"network"`
+	// Alerting holds the webhooks notified by the alerts of addresses.
+	Alerting alerts.Config `yaml:"alerting"`
+	// Probe configures the /probe endpoint that queries addresses on demand.
+	Probe jobs.ProbeConfig `yaml:"probe"`
 }
 
 // GlobalConfig holds global configuration settings.
@@ -24,6 +33,7 @@ type GlobalConfig struct {
 	Namespace     string            `yaml:"namespace" default:"eth_address"`
 	CheckInterval time.Duration     `yaml:"checkInterval" default:"15s"`
 	Labels        map[string]string `yaml:"labels"`
+	StateDir      string            `yaml:"stateDir"`
 }
 
 // ExecutionNode represents a single ethereum execution client.
@@ -36,16 +46,68 @@ type ExecutionNode struct {
 
 // Addresses holds all address types to monitor.
 type Addresses struct {
-	Account []*jobs.AddressAccount `yaml:"account"`
-	ERC20   []*jobs.AddressERC20   `yaml:"erc20"`
-	ERC721  []*jobs.AddressERC721  `yaml:"erc721"`
-	ERC1155 []*jobs.AddressERC1155 `yaml:"erc1155"`
-	ERC4626 []*jobs.AddressERC4626 `yaml:"erc4626"`
+	Account        []*jobs.AddressAccount        `yaml:"account"`
+	ERC20          []*jobs.AddressERC20          `yaml:"erc20"`
+	ERC20Allowance []*jobs.AddressERC20Allowance `yaml:"erc20Allowance"`
+	ERC721         []*jobs.AddressERC721         `yaml:"erc721"`
+	ERC1155        []*jobs.AddressERC1155        `yaml:"erc1155"`
+	ERC4626        []*jobs.AddressERC4626        `yaml:"erc4626"`
 	//nolint:tagliatelle // Preserve ERC721 casing in the public YAML key to match existing ERC naming.
 	LidoWithdrawalQueueERC721 []*jobs.AddressLidoWithdrawalQueueERC721 `yaml:"lidoWithdrawalQueueERC721"`
 	UniswapPair               []*jobs.AddressUniswapPair               `yaml:"uniswapPair"`
+	UniswapV3Pool             []*jobs.AddressUniswapV3Pool             `yaml:"uniswapV3Pool"`
 	ChainlinkDataFeed         []*jobs.AddressChainlinkDataFeed         `yaml:"chainlinkDataFeed"`
 	ERC4337                   []*jobs.AddressERC4337                   `yaml:"erc4337"`
+	ContractCall              []*jobs.AddressContractCall              `yaml:"contractCall"`
+	StorageSlot               []*jobs.AddressStorageSlot               `yaml:"storageSlot"`
+	Proxy                     []*jobs.AddressProxy                     `yaml:"proxy"`
+	Safe                      []*jobs.AddressSafe                      `yaml:"safe"`
+	LSTRate                   []*jobs.AddressLSTRate                   `yaml:"lstRate"`
+	LendingPosition           []*jobs.AddressLendingPosition           `yaml:"lendingPosition"`
+	Logs                      []*jobs.AddressLogs                      `yaml:"logs"`
+
+	// unsupportedAlerts lists the entries of address types that do not
+	// evaluate alerts but set them, as "<type> <name>".
+	unsupportedAlerts []string
+}
+
+// alertingAddressTypes are the address types that evaluate their alerts.
+var alertingAddressTypes = map[string]bool{
+	"account": true,
+	"erc20":   true,
+}
+
+// UnmarshalYAML records the entries that set alerts on an address type that
+// does not evaluate them, which would otherwise be dropped silently.
+func (a *Addresses) UnmarshalYAML(unmarshal func(any) error) error {
+	type plain Addresses
+
+	if err := unmarshal((*plain)(a)); err != nil {
+		return err
+	}
+
+	raw := map[string][]map[string]any{}
+	if err := unmarshal(&raw); err != nil {
+		return nil //nolint:nilerr // The typed decoding above already accepted the addresses.
+	}
+
+	a.unsupportedAlerts = nil
+
+	for kind, entries := range raw {
+		if alertingAddressTypes[kind] {
+			continue
+		}
+
+		for _, entry := range entries {
+			if _, ok := entry["alerts"]; ok {
+				a.unsupportedAlerts = append(a.unsupportedAlerts, fmt.Sprintf("%s %v", kind, entry["name"]))
+			}
+		}
+	}
+
+	slices.Sort(a.unsupportedAlerts)
+
+	return nil
 }
 
 // named is implemented by address types that have a Name field.
@@ -69,6 +131,22 @@ func checkDuplicateNames[T named](items []T, typeName string) error {
 	return nil
 }
 
+// validatable is implemented by address types with type specific validation.
+type validatable interface {
+	Validate() error
+}
+
+// validateAddresses runs the type specific validation of every entry in a slice.
+func validateAddresses[T validatable](items []T) error {
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (c *Config) Validate() error {
 	if len(c.Execution) == 0 {
 		return errors.New("at least one execution node must be configured")
@@ -92,14 +170,34 @@ func (c *Config) Validate() error {
 		err error
 	}{
 		{checkDuplicateNames(c.Addresses.Account, "account")},
+		{validateAddresses(c.Addresses.Account)},
 		{checkDuplicateNames(c.Addresses.ERC20, "erc20")},
+		{checkDuplicateNames(c.Addresses.ERC20Allowance, "erc20 allowance")},
 		{checkDuplicateNames(c.Addresses.ERC721, "erc721")},
 		{checkDuplicateNames(c.Addresses.ERC1155, "erc1155")},
+		{validateAddresses(c.Addresses.ERC1155)},
 		{checkDuplicateNames(c.Addresses.ERC4626, "erc4626")},
 		{checkDuplicateNames(c.Addresses.LidoWithdrawalQueueERC721, "lido withdrawal queue erc721")},
 		{checkDuplicateNames(c.Addresses.UniswapPair, "uniswap pair")},
+		{checkDuplicateNames(c.Addresses.UniswapV3Pool, "uniswap v3 pool")},
+		{validateAddresses(c.Addresses.UniswapV3Pool)},
 		{checkDuplicateNames(c.Addresses.ChainlinkDataFeed, "chainlink data feed")},
 		{checkDuplicateNames(c.Addresses.ERC4337, "erc4337")},
+		{validateAddresses(c.Addresses.ERC4337)},
+		{checkDuplicateNames(c.Addresses.ContractCall, "contract call")},
+		{validateAddresses(c.Addresses.ContractCall)},
+		{checkDuplicateNames(c.Addresses.StorageSlot, "storage slot")},
+		{validateAddresses(c.Addresses.StorageSlot)},
+		{checkDuplicateNames(c.Addresses.Proxy, "proxy")},
+		{checkDuplicateNames(c.Addresses.Safe, "safe")},
+		{checkDuplicateNames(c.Addresses.LSTRate, "lst rate")},
+		{validateAddresses(c.Addresses.LSTRate)},
+		{checkDuplicateNames(c.Addresses.LendingPosition, "lending position")},
+		{checkDuplicateNames(c.Addresses.Logs, "logs")},
+		{validateAddresses(c.Addresses.Logs)},
+		{c.Network.Validate()},
+		{c.Alerting.Validate()},
+		{c.Probe.Validate()},
 	}
 
 	for _, check := range checks {
@@ -108,5 +206,21 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if len(c.Addresses.unsupportedAlerts) > 0 {
+		return fmt.Errorf("alerts are only evaluated on account and erc20 addresses, not on %s", strings.Join(c.Addresses.unsupportedAlerts, ", "))
+	}
+
+	for _, address := range c.Addresses.Account {
+		if err := c.Alerting.ValidateRules("account "+address.Name, address.Alerts); err != nil {
+			return err
+		}
+	}
+
+	for _, address := range c.Addresses.ERC20 {
+		if err := c.Alerting.ValidateRules("erc20 "+address.Name, address.Alerts); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
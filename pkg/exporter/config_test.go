@@ -1,12 +1,15 @@
 package exporter
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
 )
 
@@ -180,3 +183,121 @@ func TestConfig_Validate_DuplicateAddressNames(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_Validate_AddressEntries(t *testing.T) {
+	t.Parallel()
+
+	validExecution := []*ExecutionNode{
+		{Name: testNodeName1, URL: testNodeURL},
+	}
+
+	tests := []struct {
+		name      string
+		addresses Addresses
+		wantErr   string
+	}{
+		{
+			name: "valid uniswap v3 pool",
+			addresses: Addresses{
+				UniswapV3Pool: []*jobs.AddressUniswapV3Pool{
+					{Name: "pool", Contract: testHolder1Address},
+				},
+			},
+		},
+		{
+			name: "uniswap v4 pool without tokens",
+			addresses: Addresses{
+				UniswapV3Pool: []*jobs.AddressUniswapV3Pool{
+					{Name: "pool", Contract: testHolder1Address, PoolID: "0x01"},
+				},
+			},
+			wantErr: "uniswap v3 pool pool: token0 and token1 must be set when poolId is configured",
+		},
+		{
+			name: "contract call without outputs",
+			addresses: Addresses{
+				ContractCall: []*jobs.AddressContractCall{
+					{Name: "call", Contract: testHolder1Address, Signature: "totalSupply()(uint256)"},
+				},
+			},
+			wantErr: "contract call call: at least one output must be configured",
+		},
+		{
+			name: "lst rate with unknown adapter",
+			addresses: Addresses{
+				LSTRate: []*jobs.AddressLSTRate{
+					{Name: "reth", Contract: testHolder1Address, Adapter: "pricePerShare"},
+				},
+			},
+			wantErr: "lst rate reth: unsupported adapter",
+		},
+		{
+			name: "account alert without webhooks",
+			addresses: Addresses{
+				Account: []*jobs.AddressAccount{
+					{Name: "relayer", Address: testHolder1Address, Alerts: []*alerts.Rule{{Name: "stale", Stale: time.Minute}}},
+				},
+			},
+			wantErr: "alerts are configured without any alerting webhooks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{
+				Execution: validExecution,
+				Addresses: tt.addresses,
+			}
+
+			err := cfg.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_UnsupportedAlerts(t *testing.T) {
+	t.Parallel()
+
+	data := `
+execution:
+  - name: node-1
+    url: http://localhost:8545
+alerting:
+  webhooks:
+    - name: ops
+      url: https://hooks.example.com/ops
+addresses:
+  erc20:
+    - name: supply
+      contract: ` + testHolder1Address + `
+      alerts:
+        - name: minted
+          max: 1000
+  erc721:
+    - name: nft
+      contract: ` + testHolder1Address + `
+      alerts:
+        - name: low
+          min: 1
+`
+
+	cfg := &Config{}
+	require.NoError(t, yaml.Unmarshal([]byte(data), cfg))
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "alerts are only evaluated on account and erc20 addresses, not on erc721 nft", err.Error())
+
+	// Contract-level ERC20 entries evaluate their alerts on the total supply.
+	cfg = &Config{}
+	require.NoError(t, yaml.Unmarshal([]byte(data[:strings.Index(data, "  erc721:")]), cfg))
+	require.NoError(t, cfg.Validate())
+}
@@ -6,10 +6,15 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // Exporter defines the Ethereum Metrics Exporter interface.
@@ -18,15 +23,31 @@ type Exporter interface {
 	Start(ctx context.Context) error
 }
 
-// NewExporter returns a new Exporter instance.
-func NewExporter(log logrus.FieldLogger, conf *Config) Exporter {
+// NewExporter returns a new Exporter instance registering its metrics with
+// registerer and serving the metrics of gatherer on /metrics. When either is
+// nil the exporter uses a registry of its own, with the Go and process
+// collectors, so several exporters can run in one process.
+func NewExporter(log logrus.FieldLogger, conf *Config, registerer prometheus.Registerer, gatherer prometheus.Gatherer) Exporter {
 	if err := conf.Validate(); err != nil {
 		log.Fatalf("invalid config: %s", err)
 	}
 
+	if registerer == nil || gatherer == nil {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+
+		registerer = registry
+		gatherer = registry
+	}
+
 	return &exporter{
-		log: log.WithField("component", "exporter"),
-		Cfg: conf,
+		log:        log.WithField("component", "exporter"),
+		Cfg:        conf,
+		registerer: registerer,
+		gatherer:   gatherer,
 	}
 }
 
@@ -35,9 +56,18 @@ type exporter struct {
 	log logrus.FieldLogger
 	Cfg *Config
 
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+
 	clients []api.ExecutionClient
 	// Metrics
 	metrics Metrics
+	// snapshots holds the latest value of every address served by the JSON API.
+	snapshots *snapshot.Store
+	// probe serves on demand queries of addresses when probing is enabled.
+	probe *probeHandler
+	// status serves the liveness, readiness and status endpoints.
+	status *statusHandler
 }
 
 func (e *exporter) Start(ctx context.Context) error {
@@ -45,7 +75,7 @@ func (e *exporter) Start(ctx context.Context) error {
 
 	e.clients = make([]api.ExecutionClient, 0, len(e.Cfg.Execution))
 
-	httpMetrics := api.NewMetrics(e.Cfg.GlobalConfig.Namespace + "_http")
+	httpMetrics := api.NewMetrics(e.Cfg.GlobalConfig.Namespace+"_http", e.registerer)
 
 	for _, node := range e.Cfg.Execution {
 		client := api.NewExecutionClient(
@@ -65,6 +95,13 @@ func (e *exporter) Start(ctx context.Context) error {
 		}).Info("Configured execution node")
 	}
 
+	alerting, err := alerts.NewManager(e.log, &e.Cfg.Alerting, e.Cfg.GlobalConfig.Namespace, e.Cfg.GlobalConfig.Labels, e.registerer)
+	if err != nil {
+		return fmt.Errorf("failed to create alert manager: %w", err)
+	}
+
+	e.snapshots = snapshot.NewStore(e.log)
+
 	e.metrics = NewMetrics(
 		e.clients,
 		e.log,
@@ -72,16 +109,26 @@ func (e *exporter) Start(ctx context.Context) error {
 		e.Cfg.GlobalConfig.Namespace,
 		e.Cfg.GlobalConfig.Labels,
 		&e.Cfg.Addresses,
+		&e.Cfg.Network,
+		e.Cfg.GlobalConfig.StateDir,
+		alerting,
+		e.snapshots,
+		e.registerer,
 	)
 
-	e.log.Info(fmt.Sprintf("Starting metrics server on %v", e.Cfg.GlobalConfig.MetricsAddr))
+	e.status = newStatusHandler(e.log, e.Cfg, e.metrics, e.snapshots, e.gatherer)
+
+	if e.Cfg.Probe.Enabled {
+		e.probe = newProbeHandler(e.log, &e.Cfg.Probe, jobs.NewProber(e.clients, e.log, e.Cfg.GlobalConfig.Namespace, e.Cfg.GlobalConfig.Labels))
+	}
 
-	http.Handle("/metrics", promhttp.Handler())
+	e.log.Info(fmt.Sprintf("Starting metrics server on %v", e.Cfg.GlobalConfig.MetricsAddr))
 
 	if err := e.ServeMetrics(ctx); err != nil {
 		return err
 	}
 
+	go e.snapshots.Start(ctx, e.clients, e.Cfg.GlobalConfig.CheckInterval)
 	go e.metrics.StartAsync(ctx)
 
 	return nil
@@ -94,7 +141,22 @@ func (e *exporter) ServeMetrics(ctx context.Context) error {
 			ReadHeaderTimeout: 15 * time.Second,
 		}
 
-		server.Handler = promhttp.Handler()
+		metricsHandler := promhttp.InstrumentMetricHandler(e.registerer, promhttp.HandlerFor(e.gatherer, promhttp.HandlerOpts{}))
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metricsHandler)
+		// Metrics were served on every path before /metrics, keep the root working for existing scrape configs.
+		mux.Handle("/{$}", metricsHandler)
+		mux.Handle("/api/v1/addresses", e.snapshots)
+		mux.HandleFunc("/healthz", e.status.serveHealthz)
+		mux.HandleFunc("/readyz", e.status.serveReadyz)
+		mux.HandleFunc("/status", e.status.serveStatus)
+
+		if e.probe != nil {
+			mux.Handle("/probe", e.probe)
+		}
+
+		server.Handler = mux
 
 		e.log.Infof("Serving metrics at %s", e.Cfg.GlobalConfig.MetricsAddr)
 
@@ -0,0 +1,205 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter"
+)
+
+const (
+	dashboardSchemaVersion = 39
+	panelHeight            = 8
+	panelWidth             = 12
+	gridWidth              = 24
+)
+
+// Dashboard is a Grafana dashboard.
+type Dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          TimeRange  `json:"time"`
+	Templating    Templating `json:"templating"`
+	Panels        []*Panel   `json:"panels"`
+}
+
+// TimeRange is the default time range of a dashboard.
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Templating holds the variables of a dashboard.
+type Templating struct {
+	List []*Variable `json:"list"`
+}
+
+// Variable is a dashboard template variable.
+type Variable struct {
+	Name       string      `json:"name"`
+	Label      string      `json:"label,omitempty"`
+	Type       string      `json:"type"`
+	Query      string      `json:"query"`
+	Datasource *Datasource `json:"datasource,omitempty"`
+	Refresh    int         `json:"refresh,omitempty"`
+	IncludeAll bool        `json:"includeAll,omitempty"`
+	Multi      bool        `json:"multi,omitempty"`
+}
+
+// Datasource references the datasource of a panel or variable.
+type Datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// GridPos is the position of a panel.
+type GridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Panel is a dashboard row or time series panel.
+type Panel struct {
+	ID         int         `json:"id"`
+	Type       string      `json:"type"`
+	Title      string      `json:"title"`
+	GridPos    GridPos     `json:"gridPos"`
+	Collapsed  *bool       `json:"collapsed,omitempty"`
+	Datasource *Datasource `json:"datasource,omitempty"`
+	Targets    []*Target   `json:"targets,omitempty"`
+}
+
+// Target is a Prometheus query of a panel.
+type Target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+}
+
+// dashboardBuilder lays out panels two per line below a row per job.
+type dashboardBuilder struct {
+	dashboard *Dashboard
+	id        int
+	x         int
+	y         int
+}
+
+func (b *dashboardBuilder) row(title string) {
+	if b.x > 0 {
+		b.x = 0
+		b.y += panelHeight
+	}
+
+	collapsed := false
+
+	b.id++
+	b.dashboard.Panels = append(b.dashboard.Panels, &Panel{
+		ID:        b.id,
+		Type:      "row",
+		Title:     title,
+		GridPos:   GridPos{H: 1, W: gridWidth, X: 0, Y: b.y},
+		Collapsed: &collapsed,
+	})
+
+	b.y++
+}
+
+func (b *dashboardBuilder) timeseries(title, expr, legend string) {
+	b.id++
+	b.dashboard.Panels = append(b.dashboard.Panels, &Panel{
+		ID:         b.id,
+		Type:       "timeseries",
+		Title:      title,
+		GridPos:    GridPos{H: panelHeight, W: panelWidth, X: b.x, Y: b.y},
+		Datasource: &Datasource{Type: "prometheus", UID: "${datasource}"},
+		Targets:    []*Target{{RefID: "A", Expr: expr, LegendFormat: legend}},
+	})
+
+	b.x += panelWidth
+	if b.x >= gridWidth {
+		b.x = 0
+		b.y += panelHeight
+	}
+}
+
+// NewDashboard builds a dashboard with a row of panels per enabled job.
+func NewDashboard(cfg *exporter.Config) *Dashboard {
+	namespace := cfg.GlobalConfig.Namespace
+
+	b := &dashboardBuilder{
+		dashboard: &Dashboard{
+			UID:           namespace,
+			Title:         namespace,
+			Tags:          []string{"ethereum", namespace},
+			SchemaVersion: dashboardSchemaVersion,
+			Refresh:       "1m",
+			Time:          TimeRange{From: "now-6h", To: "now"},
+			Templating: Templating{
+				List: []*Variable{
+					{
+						Name:  "datasource",
+						Label: "Datasource",
+						Type:  "datasource",
+						Query: "prometheus",
+					},
+					{
+						Name:       labelExecution,
+						Label:      "Execution",
+						Type:       "query",
+						Query:      fmt.Sprintf("label_values(%s, %s)", prefixed(namespace, "http_request_count"), labelExecution),
+						Datasource: &Datasource{Type: "prometheus", UID: "${datasource}"},
+						Refresh:    2,
+						IncludeAll: true,
+						Multi:      true,
+					},
+				},
+			},
+		},
+	}
+
+	selector := fmt.Sprintf(`{%s=~"$%s"}`, labelExecution, labelExecution)
+
+	b.row("Execution nodes")
+	b.timeseries(
+		"Requests",
+		fmt.Sprintf("sum by (%s, %s) (rate(%s%s[5m]))", labelExecution, "api_method", prefixed(namespace, "http_request_count"), selector),
+		"{{execution}} {{api_method}}",
+	)
+	b.timeseries(
+		"Responses",
+		fmt.Sprintf("sum by (%s, %s) (rate(%s%s[5m]))", labelExecution, "code", prefixed(namespace, "http_response_count"), selector),
+		"{{execution}} {{code}}",
+	)
+
+	for _, j := range enabledJobs(cfg) {
+		b.row(j.title)
+
+		legend := "{{name}} {{execution}}"
+		if len(j.targets) == 0 {
+			legend = "{{execution}}"
+		}
+
+		for _, m := range j.metrics {
+			expr := j.fqName(namespace, m.name) + selector
+			if m.counter {
+				expr = fmt.Sprintf("rate(%s[5m])", expr)
+			}
+
+			b.timeseries(j.title+" "+m.title, expr, legend)
+		}
+
+		b.timeseries(j.title+" errors", fmt.Sprintf("rate(%s%s[5m])", j.fqName(namespace, metricErrorsTotal), selector), legend)
+	}
+
+	return b.dashboard
+}
+
+// MarshalDashboard renders the dashboard as indented JSON.
+func MarshalDashboard(cfg *exporter.Config) ([]byte, error) {
+	return json.MarshalIndent(NewDashboard(cfg), "", "  ")
+}
@@ -0,0 +1,350 @@
+// Package generate builds Prometheus alerting rules and a Grafana dashboard
+// from the exporter config, using the namespace and metric names the jobs
+// register.
+package generate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+)
+
+const (
+	metricErrorsTotal = "errors_total"
+	labelName         = "name"
+	labelExecution    = "execution"
+)
+
+// metric is a gauge exported by a job.
+type metric struct {
+	name  string
+	title string
+	// divergence enables the cross-node divergence alert of the metric.
+	divergence bool
+	// tolerance is the difference between nodes that is not considered divergent.
+	tolerance float64
+	// counter graphs the rate of the metric instead of its value.
+	counter bool
+}
+
+// target is a configured address of a job and the metric that reports it.
+type target struct {
+	name   string
+	metric string
+}
+
+// job describes the metrics of an enabled job.
+type job struct {
+	name    string
+	title   string
+	metrics []metric
+	targets []target
+}
+
+// fqName returns the fully qualified name of a metric of the job.
+func (j *job) fqName(namespace, name string) string {
+	return prometheus.BuildFQName(namespace, j.name, name)
+}
+
+// named is implemented by address types that have a Name field.
+type named interface {
+	GetName() string
+}
+
+func targets[T named](items []T, metricName string) []target {
+	result := make([]target, 0, len(items))
+
+	for _, item := range items {
+		result = append(result, target{name: item.GetName(), metric: metricName})
+	}
+
+	return result
+}
+
+// enabledJobs returns the jobs enabled by the config, in the order the
+// exporter starts them.
+//
+//nolint:gocyclo // One branch per job type.
+func enabledJobs(cfg *exporter.Config) []*job {
+	addresses := &cfg.Addresses
+
+	result := make([]*job, 0, 19)
+
+	if len(addresses.Account) > 0 {
+		metrics := []metric{
+			{name: "balance", title: "Balance", divergence: true},
+			{name: "nonce", title: "Nonce", divergence: true},
+			{name: "pending_nonce_gap", title: "Pending nonce gap"},
+		}
+
+		for _, address := range addresses.Account {
+			if address.SpendRate != nil {
+				metrics = append(metrics,
+					metric{name: "spend_rate_per_hour", title: "Spend rate per hour"},
+					metric{name: "time_to_empty_seconds", title: "Time to empty"},
+				)
+
+				break
+			}
+		}
+
+		result = append(result, &job{
+			name:    jobs.NameAccount,
+			title:   "Account",
+			metrics: metrics,
+			targets: targets(addresses.Account, "balance"),
+		})
+	}
+
+	if len(addresses.ERC20) > 0 {
+		erc20 := &job{
+			name:  jobs.NameERC20,
+			title: "ERC20",
+			metrics: []metric{
+				{name: "balance", title: "Balance", divergence: true},
+				{name: "total_supply", title: "Total supply", divergence: true},
+			},
+		}
+
+		for _, address := range addresses.ERC20 {
+			// Contract-level entries export the total supply instead of a balance.
+			metricName := "balance"
+			if address.Address == "" {
+				metricName = "total_supply"
+			}
+
+			erc20.targets = append(erc20.targets, target{name: address.Name, metric: metricName})
+		}
+
+		result = append(result, erc20)
+	}
+
+	if len(addresses.ERC20Allowance) > 0 {
+		result = append(result, &job{
+			name:  jobs.NameERC20Allowance,
+			title: "ERC20 allowance",
+			metrics: []metric{
+				{name: "allowance", title: "Allowance", divergence: true},
+				{name: "unlimited", title: "Unlimited"},
+			},
+			targets: targets(addresses.ERC20Allowance, "allowance"),
+		})
+	}
+
+	if len(addresses.ERC721) > 0 {
+		result = append(result, &job{
+			name:    jobs.NameERC721,
+			title:   "ERC721",
+			metrics: []metric{{name: "balance", title: "Balance", divergence: true}},
+			targets: targets(addresses.ERC721, "balance"),
+		})
+	}
+
+	if len(addresses.ERC1155) > 0 {
+		result = append(result, &job{
+			name:    jobs.NameERC1155,
+			title:   "ERC1155",
+			metrics: []metric{{name: "balance", title: "Balance", divergence: true}},
+			targets: targets(addresses.ERC1155, "balance"),
+		})
+	}
+
+	if len(addresses.ERC4626) > 0 {
+		result = append(result, &job{
+			name:  jobs.NameERC4626,
+			title: "ERC4626",
+			metrics: []metric{
+				{name: "assets", title: "Assets", divergence: true},
+				{name: "total_assets", title: "Total assets", divergence: true},
+				{name: "price_per_share", title: "Price per share", divergence: true},
+			},
+			targets: targets(addresses.ERC4626, "total_assets"),
+		})
+	}
+
+	if len(addresses.LidoWithdrawalQueueERC721) > 0 {
+		result = append(result, &job{
+			name:  jobs.NameLidoWithdrawalQueueERC721,
+			title: "Lido withdrawal queue",
+			metrics: []metric{
+				{name: "request_count", title: "Requests", divergence: true},
+				{name: "pending", title: "Pending"},
+				{name: "claimable", title: "Claimable"},
+				{name: "unfinalized_steth", title: "Unfinalized stETH"},
+			},
+			targets: targets(addresses.LidoWithdrawalQueueERC721, "request_count"),
+		})
+	}
+
+	if len(addresses.UniswapPair) > 0 {
+		result = append(result, &job{
+			name:    jobs.NameUniswapPair,
+			title:   "Uniswap pair",
+			metrics: []metric{{name: "balance", title: "Price", divergence: true}},
+			targets: targets(addresses.UniswapPair, "balance"),
+		})
+	}
+
+	if len(addresses.UniswapV3Pool) > 0 {
+		result = append(result, &job{
+			name:  jobs.NameUniswapV3Pool,
+			title: "Uniswap v3 pool",
+			metrics: []metric{
+				{name: "price", title: "Price", divergence: true},
+				{name: "liquidity", title: "Liquidity"},
+				{name: "position_amount", title: "Position amount"},
+			},
+			targets: targets(addresses.UniswapV3Pool, "price"),
+		})
+	}
+
+	if len(addresses.ChainlinkDataFeed) > 0 {
+		result = append(result, &job{
+			name:    jobs.NameChainlinkDataFeed,
+			title:   "Chainlink data feed",
+			metrics: []metric{{name: "balance", title: "Answer", divergence: true}},
+			targets: targets(addresses.ChainlinkDataFeed, "balance"),
+		})
+	}
+
+	if len(addresses.ERC4337) > 0 {
+		result = append(result, &job{
+			name:  jobs.NameERC4337,
+			title: "ERC4337",
+			metrics: []metric{
+				{name: "balance", title: "Balance", divergence: true},
+				{name: "stake", title: "Stake"},
+			},
+			targets: targets(addresses.ERC4337, "balance"),
+		})
+	}
+
+	if len(addresses.ContractCall) > 0 {
+		contractCall := &job{
+			name:  jobs.NameContractCall,
+			title: "Contract call",
+		}
+
+		seen := make(map[string]bool)
+
+		for _, address := range addresses.ContractCall {
+			for _, output := range address.Outputs {
+				if !seen[output.Metric] {
+					seen[output.Metric] = true
+
+					contractCall.metrics = append(contractCall.metrics, metric{name: output.Metric, title: output.Metric, divergence: true})
+				}
+			}
+
+			if len(address.Outputs) > 0 {
+				contractCall.targets = append(contractCall.targets, target{name: address.Name, metric: address.Outputs[0].Metric})
+			}
+		}
+
+		result = append(result, contractCall)
+	}
+
+	if len(addresses.StorageSlot) > 0 {
+		result = append(result, &job{
+			name:    jobs.NameStorageSlot,
+			title:   "Storage slot",
+			metrics: []metric{{name: "value", title: "Value", divergence: true}},
+			targets: targets(addresses.StorageSlot, "value"),
+		})
+	}
+
+	if len(addresses.Proxy) > 0 {
+		result = append(result, &job{
+			name:    jobs.NameProxy,
+			title:   "Proxy",
+			metrics: []metric{{name: "info", title: "Implementation"}},
+			targets: targets(addresses.Proxy, "info"),
+		})
+	}
+
+	if len(addresses.Safe) > 0 {
+		result = append(result, &job{
+			name:  jobs.NameSafe,
+			title: "Safe",
+			metrics: []metric{
+				{name: "threshold", title: "Threshold", divergence: true},
+				{name: "owners", title: "Owners", divergence: true},
+				{name: "nonce", title: "Nonce"},
+			},
+			targets: targets(addresses.Safe, "threshold"),
+		})
+	}
+
+	if len(addresses.LSTRate) > 0 {
+		result = append(result, &job{
+			name:    jobs.NameLSTRate,
+			title:   "LST rate",
+			metrics: []metric{{name: "rate", title: "Rate", divergence: true}},
+			targets: targets(addresses.LSTRate, "rate"),
+		})
+	}
+
+	if len(addresses.LendingPosition) > 0 {
+		result = append(result, &job{
+			name:  jobs.NameLendingPosition,
+			title: "Lending position",
+			metrics: []metric{
+				{name: "health_factor", title: "Health factor", divergence: true},
+				{name: "total_collateral", title: "Total collateral"},
+				{name: "total_debt", title: "Total debt"},
+			},
+			targets: targets(addresses.LendingPosition, "health_factor"),
+		})
+	}
+
+	if len(addresses.Logs) > 0 {
+		result = append(result, &job{
+			name:  jobs.NameLogs,
+			title: "Logs",
+			metrics: []metric{
+				{name: "events_total", title: "Events", counter: true},
+				{name: "cursor_block", title: "Cursor block"},
+			},
+			targets: targets(addresses.Logs, "cursor_block"),
+		})
+	}
+
+	if cfg.Network.Enabled {
+		result = append(result, &job{
+			name:  jobs.NameNetwork,
+			title: "Network",
+			metrics: []metric{
+				{name: "block_number", title: "Block number", divergence: true, tolerance: 3},
+				{name: "base_fee_per_gas", title: "Base fee per gas"},
+				{name: "priority_fee_per_gas", title: "Priority fee per gas"},
+				{name: "gas_used_ratio", title: "Gas used ratio"},
+			},
+		})
+	}
+
+	return result
+}
+
+// formatDuration formats a duration in the Prometheus duration syntax.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "0s"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", (d+time.Second-1)/time.Second)
+	}
+}
+
+// quote escapes a value for use in a PromQL label matcher.
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,320 @@
+package generate
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+)
+
+const (
+	testNamespace = "generate_test"
+	testAddress   = "0x1111111111111111111111111111111111111111"
+	testContract  = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+)
+
+// testConfig enables every job type.
+func testConfig() *exporter.Config {
+	minBalance := 1e18
+	maxSupply := 1e24
+
+	return &exporter.Config{
+		GlobalConfig: exporter.GlobalConfig{Namespace: testNamespace},
+		Execution: []*exporter.ExecutionNode{
+			{Name: "geth", URL: "http://localhost:8545"},
+			{Name: "nethermind", URL: "http://localhost:8546"},
+		},
+		Addresses: exporter.Addresses{
+			Account: []*jobs.AddressAccount{
+				{
+					Name:      "relayer",
+					Address:   testAddress,
+					SpendRate: &jobs.AccountSpendRate{MinBalance: 5e17},
+					Alerts:    []*alerts.Rule{{Name: "low", Min: &minBalance, For: 2 * time.Minute}},
+				},
+			},
+			ERC20: []*jobs.AddressERC20{
+				{Name: "holder", Address: testAddress, Contract: testContract},
+				{Name: "supply", Contract: testContract, Alerts: []*alerts.Rule{{Name: "minted", Max: &maxSupply}}},
+			},
+			ERC20Allowance:            []*jobs.AddressERC20Allowance{{Name: "allowance", Contract: testContract}},
+			ERC721:                    []*jobs.AddressERC721{{Name: "nft", Contract: testContract}},
+			ERC1155:                   []*jobs.AddressERC1155{{Name: "items", Contract: testContract}},
+			ERC4626:                   []*jobs.AddressERC4626{{Name: "vault", Contract: testContract}},
+			LidoWithdrawalQueueERC721: []*jobs.AddressLidoWithdrawalQueueERC721{{Name: "queue", Contract: testContract}},
+			UniswapPair:               []*jobs.AddressUniswapPair{{Name: "pair", Contract: testContract}},
+			UniswapV3Pool:             []*jobs.AddressUniswapV3Pool{{Name: "pool", Contract: testContract}},
+			ChainlinkDataFeed:         []*jobs.AddressChainlinkDataFeed{{Name: "feed", Contract: testContract}},
+			ERC4337:                   []*jobs.AddressERC4337{{Name: "paymaster", Contract: testContract}},
+			ContractCall: []*jobs.AddressContractCall{
+				{
+					Name:      "supply",
+					Contract:  testContract,
+					Signature: "totalSupply()(uint256)",
+					Outputs:   []*jobs.ContractCallOutput{{Metric: "total_supply"}},
+				},
+			},
+			StorageSlot:     []*jobs.AddressStorageSlot{{Name: "slot", Contract: testContract}},
+			Proxy:           []*jobs.AddressProxy{{Name: "proxy", Contract: testContract}},
+			Safe:            []*jobs.AddressSafe{{Name: "safe", Contract: testContract}},
+			LSTRate:         []*jobs.AddressLSTRate{{Name: "reth", Contract: testContract}},
+			LendingPosition: []*jobs.AddressLendingPosition{{Name: "position", Contract: testContract}},
+			Logs:            []*jobs.AddressLogs{{Name: "transfers", Contract: testContract}},
+		},
+		Network: jobs.NetworkConfig{Enabled: true},
+	}
+}
+
+func findRule(file *RuleFile, alert, expr string) *Rule {
+	for _, group := range file.Groups {
+		for _, rule := range group.Rules {
+			if rule.Alert == alert && rule.Expr == expr {
+				return rule
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestNewRules(t *testing.T) {
+	t.Parallel()
+
+	file := NewRules(testConfig())
+
+	tests := []struct {
+		alert   string
+		expr    string
+		wantFor string
+	}{
+		{
+			alert:   "GenerateTestHttpNoSuccessfulResponses",
+			expr:    `sum by (execution) (rate(generate_test_http_response_count{code="200"}[5m])) == 0`,
+			wantFor: "10m",
+		},
+		{
+			alert: "GenerateTestAccountErrors",
+			expr:  "increase(generate_test_account_errors_total[15m]) > 0",
+		},
+		{
+			alert:   "GenerateTestAccountStale",
+			expr:    `absent(generate_test_account_balance{name="relayer"}) or increase(generate_test_account_errors_total{name="relayer"}[5m]) > 0`,
+			wantFor: "10m",
+		},
+		{
+			alert:   "GenerateTestErc20Stale",
+			expr:    `absent(generate_test_erc20_total_supply{name="supply"}) or increase(generate_test_erc20_errors_total{name="supply"}[5m]) > 0`,
+			wantFor: "10m",
+		},
+		{
+			alert:   "GenerateTestContractCallStale",
+			expr:    `absent(generate_test_contract_call_total_supply{name="supply"}) or increase(generate_test_contract_call_errors_total{name="supply"}[5m]) > 0`,
+			wantFor: "10m",
+		},
+		{
+			alert:   "GenerateTestAccountBalanceDivergence",
+			expr:    "max without (execution) (generate_test_account_balance) - min without (execution) (generate_test_account_balance) > 0",
+			wantFor: "10m",
+		},
+		{
+			alert:   "GenerateTestNetworkBlockNumberDivergence",
+			expr:    "max without (execution) (generate_test_network_block_number) - min without (execution) (generate_test_network_block_number) > 3",
+			wantFor: "10m",
+		},
+		{
+			alert:   "GenerateTestNetworkBlockNumberStalled",
+			expr:    "changes(generate_test_network_block_number[5m]) == 0",
+			wantFor: "10m",
+		},
+		{
+			alert:   "GenerateTestAccountBelowMin",
+			expr:    `generate_test_account_balance{name="relayer"} < 1e+18`,
+			wantFor: "2m",
+		},
+		{
+			alert:   "GenerateTestErc20AboveMax",
+			expr:    `generate_test_erc20_total_supply{name="supply"} > 1e+24`,
+			wantFor: "5m",
+		},
+		{
+			alert: "GenerateTestAccountBelowThreshold",
+			expr:  `generate_test_account_below_threshold{name="relayer"} == 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alert, func(t *testing.T) {
+			t.Parallel()
+
+			rule := findRule(file, tt.alert, tt.expr)
+			require.NotNil(t, rule, "missing rule %s: %s", tt.alert, tt.expr)
+			assert.Equal(t, tt.wantFor, rule.For)
+		})
+	}
+
+	// Contract-level ERC20 entries bound their total supply, they have no balance.
+	for _, group := range file.Groups {
+		for _, rule := range group.Rules {
+			assert.NotContains(t, rule.Expr, `generate_test_erc20_balance{name="supply"}`)
+		}
+	}
+
+	data, err := yaml.Marshal(file)
+	require.NoError(t, err)
+
+	parsed := &RuleFile{}
+	require.NoError(t, yaml.Unmarshal(data, parsed))
+	assert.Equal(t, file, parsed)
+}
+
+func TestNewRules_SingleExecutionNode(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig()
+	cfg.Execution = cfg.Execution[:1]
+
+	for _, group := range NewRules(cfg).Groups {
+		for _, rule := range group.Rules {
+			assert.NotContains(t, rule.Alert, "Divergence")
+		}
+	}
+}
+
+func TestNewDashboard(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig()
+	dashboard := NewDashboard(cfg)
+
+	rows := make(map[string]bool)
+	ids := make(map[int]bool)
+	exprs := make(map[string]bool)
+
+	for _, panel := range dashboard.Panels {
+		assert.False(t, ids[panel.ID], "duplicate panel id %d", panel.ID)
+		ids[panel.ID] = true
+
+		assert.LessOrEqual(t, panel.GridPos.X+panel.GridPos.W, gridWidth)
+
+		if panel.Type == "row" {
+			rows[panel.Title] = true
+
+			continue
+		}
+
+		require.Len(t, panel.Targets, 1)
+		exprs[panel.Targets[0].Expr] = true
+	}
+
+	assert.Len(t, rows, len(enabledJobs(cfg))+1)
+	assert.True(t, exprs[`generate_test_account_balance{execution=~"$execution"}`])
+	assert.True(t, exprs[`rate(generate_test_logs_events_total{execution=~"$execution"}[5m])`])
+	assert.True(t, exprs[`rate(generate_test_network_errors_total{execution=~"$execution"}[5m])`])
+	assert.True(t, exprs[`generate_test_contract_call_total_supply{execution=~"$execution"}`])
+
+	data, err := MarshalDashboard(cfg)
+	require.NoError(t, err)
+
+	parsed := map[string]any{}
+	require.NoError(t, json.Unmarshal(data, &parsed))
+	assert.Equal(t, testNamespace, parsed["uid"])
+}
+
+// generatedMetricName matches the metrics referenced by the expressions of the
+// test config.
+var generatedMetricName = regexp.MustCompile(testNamespace + `_[a-z0-9_]+`)
+
+// generatedMetricNames returns every metric referenced by the generated rules
+// and dashboard.
+func generatedMetricNames(cfg *exporter.Config) []string {
+	exprs := make([]string, 0)
+
+	for _, group := range NewRules(cfg).Groups {
+		for _, rule := range group.Rules {
+			exprs = append(exprs, rule.Expr)
+		}
+	}
+
+	dashboard := NewDashboard(cfg)
+
+	for _, variable := range dashboard.Templating.List {
+		exprs = append(exprs, variable.Query)
+	}
+
+	for _, panel := range dashboard.Panels {
+		for _, target := range panel.Targets {
+			exprs = append(exprs, target.Expr)
+		}
+	}
+
+	names := make([]string, 0)
+
+	for _, expr := range exprs {
+		for _, name := range generatedMetricName.FindAllString(expr, -1) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// TestMetricNamesRegistered checks every metric the rules and dashboard
+// reference is registered by the jobs of the exporter.
+func TestMetricNamesRegistered(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig()
+
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	registry := prometheus.NewRegistry()
+
+	api.NewMetrics(testNamespace+"_http", registry)
+	exporter.NewMetrics(nil, log, time.Minute, testNamespace, nil, &cfg.Addresses, &cfg.Network, "", nil, nil, registry)
+
+	names := generatedMetricNames(cfg)
+
+	// Every enabled job is referenced by at least its errors alert.
+	require.GreaterOrEqual(t, len(names), len(enabledJobs(cfg)))
+	assert.Contains(t, names, prometheus.BuildFQName(testNamespace, jobs.NameAccount, "below_threshold"))
+
+	// The metrics described for each job, whether or not the test config
+	// references them.
+	names = append(names, testNamespace+"_http_request_count")
+
+	for _, j := range enabledJobs(cfg) {
+		for _, m := range j.metrics {
+			names = append(names, j.fqName(testNamespace, m.name))
+		}
+
+		for _, tt := range j.targets {
+			names = append(names, j.fqName(testNamespace, tt.metric))
+		}
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	for _, name := range names {
+		// Registering a collector under a name the jobs already use fails.
+		probe := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: "probe"})
+		assert.Error(t, registry.Register(probe), "metric %s is not registered", name)
+	}
+}
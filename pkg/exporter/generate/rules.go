@@ -0,0 +1,273 @@
+package generate
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+)
+
+const (
+	severityWarning  = "warning"
+	severityCritical = "critical"
+
+	// staleFor is how long a series may be missing, or its address keep failing, before it is reported as stale.
+	staleFor = 10 * time.Minute
+	// staleErrorsWindow is the range in which an address must fail throughout staleFor to be reported as stale.
+	staleErrorsWindow = 5 * time.Minute
+	// errorsWindow is the range the error counters are evaluated over.
+	errorsWindow = 15 * time.Minute
+	// divergenceFor is how long nodes may disagree before it is reported.
+	divergenceFor = 10 * time.Minute
+	// thresholdFor is how long a threshold must be crossed when the alert of an address does not set a duration.
+	thresholdFor = 5 * time.Minute
+)
+
+// RuleFile is a Prometheus rules file.
+type RuleFile struct {
+	Groups []*RuleGroup `yaml:"groups"`
+}
+
+// RuleGroup is a named group of alerting rules.
+type RuleGroup struct {
+	Name  string  `yaml:"name"`
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Rule is a Prometheus alerting rule.
+type Rule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// NewRules builds the alerting rules for the jobs and addresses of the config.
+func NewRules(cfg *exporter.Config) *RuleFile {
+	namespace := cfg.GlobalConfig.Namespace
+	enabled := enabledJobs(cfg)
+
+	file := &RuleFile{
+		Groups: []*RuleGroup{exporterRules(cfg, enabled)},
+	}
+
+	for _, j := range enabled {
+		group := &RuleGroup{Name: prefixed(namespace, j.name)}
+
+		for _, t := range j.targets {
+			// A failing address keeps exporting its last value, so it is
+			// stale when it was never exported or keeps erroring.
+			group.Rules = append(group.Rules, &Rule{
+				Alert: alertName(namespace, j.name, "stale"),
+				Expr: fmt.Sprintf("absent(%s{%s=%s}) or increase(%s{%[2]s=%[3]s}[%[5]s]) > 0",
+					j.fqName(namespace, t.metric), labelName, quote(t.name),
+					j.fqName(namespace, metricErrorsTotal), formatDuration(staleErrorsWindow)),
+				For:    formatDuration(staleFor),
+				Labels: map[string]string{"severity": severityWarning, labelName: t.name},
+				Annotations: map[string]string{
+					"summary": fmt.Sprintf("No fresh %s data for %s", j.title, t.name),
+				},
+			})
+		}
+
+		if len(cfg.Execution) > 1 {
+			for _, m := range j.metrics {
+				if !m.divergence {
+					continue
+				}
+
+				fqName := j.fqName(namespace, m.name)
+
+				group.Rules = append(group.Rules, &Rule{
+					Alert:  alertName(namespace, j.name, m.name+"_divergence"),
+					Expr:   fmt.Sprintf("max without (%[1]s) (%[2]s) - min without (%[1]s) (%[2]s) > %[3]s", labelExecution, fqName, formatFloat(m.tolerance)),
+					For:    formatDuration(divergenceFor),
+					Labels: map[string]string{"severity": severityWarning},
+					Annotations: map[string]string{
+						"summary": fmt.Sprintf("Execution nodes disagree on %s %s", j.title, m.title),
+					},
+				})
+			}
+		}
+
+		group.Rules = append(group.Rules, thresholdRules(cfg, j)...)
+
+		if len(group.Rules) > 0 {
+			file.Groups = append(file.Groups, group)
+		}
+	}
+
+	return file
+}
+
+// MarshalRules renders the rules file as YAML.
+func MarshalRules(cfg *exporter.Config) ([]byte, error) {
+	return yaml.Marshal(NewRules(cfg))
+}
+
+// exporterRules reports job errors and execution nodes without successful responses.
+func exporterRules(cfg *exporter.Config, enabled []*job) *RuleGroup {
+	namespace := cfg.GlobalConfig.Namespace
+
+	group := &RuleGroup{
+		Name: prefixed(namespace, "exporter"),
+		Rules: []*Rule{
+			{
+				Alert:  alertName(namespace, "http", "no_successful_responses"),
+				Expr:   fmt.Sprintf(`sum by (%s) (rate(%s{code="200"}[5m])) == 0`, labelExecution, prefixed(namespace, "http_response_count")),
+				For:    formatDuration(staleFor),
+				Labels: map[string]string{"severity": severityCritical},
+				Annotations: map[string]string{
+					"summary": "Execution node {{ $labels.execution }} has not answered any request successfully",
+				},
+			},
+		},
+	}
+
+	for _, j := range enabled {
+		group.Rules = append(group.Rules, &Rule{
+			Alert:  alertName(namespace, j.name, "errors"),
+			Expr:   fmt.Sprintf("increase(%s[%s]) > 0", j.fqName(namespace, metricErrorsTotal), formatDuration(errorsWindow)),
+			Labels: map[string]string{"severity": severityWarning},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s job errors for {{ $labels.name }} on {{ $labels.execution }}", j.title),
+			},
+		})
+	}
+
+	if cfg.Network.Enabled {
+		group.Rules = append(group.Rules, &Rule{
+			Alert:  alertName(namespace, jobs.NameNetwork, "block_number_stalled"),
+			Expr:   fmt.Sprintf("changes(%s[5m]) == 0", prefixed(namespace, jobs.NameNetwork+"_block_number")),
+			For:    formatDuration(staleFor),
+			Labels: map[string]string{"severity": severityCritical},
+			Annotations: map[string]string{
+				"summary": "Execution node {{ $labels.execution }} is not following the chain head",
+			},
+		})
+	}
+
+	return group
+}
+
+// thresholdRules translates the min and max bounds of address alerts and the
+// minimum balance of account spend rates.
+func thresholdRules(cfg *exporter.Config, j *job) []*Rule {
+	namespace := cfg.GlobalConfig.Namespace
+
+	result := make([]*Rule, 0)
+
+	switch j.name {
+	case jobs.NameAccount:
+		for _, address := range cfg.Addresses.Account {
+			result = append(result, boundRules(namespace, j, address.Name, "balance", address.Alerts)...)
+
+			if address.SpendRate != nil && address.SpendRate.MinBalance > 0 {
+				result = append(result, &Rule{
+					Alert:  alertName(namespace, j.name, "below_threshold"),
+					Expr:   fmt.Sprintf("%s{%s=%s} == 1", j.fqName(namespace, "below_threshold"), labelName, quote(address.Name)),
+					Labels: map[string]string{"severity": severityCritical, labelName: address.Name},
+					Annotations: map[string]string{
+						"summary": fmt.Sprintf("%s balance is below its minimum balance", address.Name),
+					},
+				})
+			}
+		}
+	case jobs.NameERC20:
+		for _, address := range cfg.Addresses.ERC20 {
+			metricName := "balance"
+			if address.Address == "" {
+				metricName = "total_supply"
+			}
+
+			result = append(result, boundRules(namespace, j, address.Name, metricName, address.Alerts)...)
+		}
+	}
+
+	return result
+}
+
+func boundRules(namespace string, j *job, name, metricName string, rules []*alerts.Rule) []*Rule {
+	result := make([]*Rule, 0)
+
+	selector := fmt.Sprintf("%s{%s=%s}", j.fqName(namespace, metricName), labelName, quote(name))
+
+	for _, rule := range rules {
+		duration := rule.For
+		if duration == 0 {
+			duration = thresholdFor
+		}
+
+		if rule.Min != nil {
+			result = append(result, &Rule{
+				Alert:  alertName(namespace, j.name, "below_min"),
+				Expr:   fmt.Sprintf("%s < %s", selector, formatFloat(*rule.Min)),
+				For:    formatDuration(duration),
+				Labels: map[string]string{"severity": severityCritical, labelName: name, "rule": rule.Name},
+				Annotations: map[string]string{
+					"summary": fmt.Sprintf("%s %s is below %s", name, metricName, formatFloat(*rule.Min)),
+				},
+			})
+		}
+
+		if rule.Max != nil {
+			result = append(result, &Rule{
+				Alert:  alertName(namespace, j.name, "above_max"),
+				Expr:   fmt.Sprintf("%s > %s", selector, formatFloat(*rule.Max)),
+				For:    formatDuration(duration),
+				Labels: map[string]string{"severity": severityCritical, labelName: name, "rule": rule.Name},
+				Annotations: map[string]string{
+					"summary": fmt.Sprintf("%s %s is above %s", name, metricName, formatFloat(*rule.Max)),
+				},
+			})
+		}
+	}
+
+	return result
+}
+
+func prefixed(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + "_" + name
+}
+
+// alertName builds a CamelCase alert name from the namespace, job and condition.
+func alertName(parts ...string) string {
+	name := make([]byte, 0, 64)
+
+	for _, part := range parts {
+		upper := true
+
+		for i := 0; i < len(part); i++ {
+			c := part[i]
+			if c == '_' {
+				upper = true
+
+				continue
+			}
+
+			if upper && c >= 'a' && c <= 'z' {
+				c -= 'a' - 'A'
+			}
+
+			upper = false
+
+			name = append(name, c)
+		}
+	}
+
+	return string(name)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,637 @@
+package jobs
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// abiWordModulus is 2^256, the modulus of a single ABI word.
+var abiWordModulus = new(big.Int).Lsh(big.NewInt(1), 256)
+
+func encodeAddressCall(selector, address string) (string, error) {
+	encodedAddress, err := encodeAddressArgument(address)
+	if err != nil {
+		return "", err
+	}
+
+	return selector + encodedAddress, nil
+}
+
+func encodeAddressArgument(address string) (string, error) {
+	cleanedAddress := strings.TrimPrefix(strings.ToLower(address), "0x")
+	if len(cleanedAddress) != 40 {
+		return "", fmt.Errorf("invalid ethereum address length: %s", address)
+	}
+
+	if _, err := hex.DecodeString(cleanedAddress); err != nil {
+		return "", fmt.Errorf("invalid ethereum address: %w", err)
+	}
+
+	return strings.Repeat("0", 24) + cleanedAddress, nil
+}
+
+func encodeUint256ArrayCall(selector string, values []*big.Int) string {
+	var data strings.Builder
+
+	data.WriteString(selector)
+	data.WriteString(formatABIWord(big.NewInt(32)))
+	data.WriteString(formatABIWord(big.NewInt(int64(len(values)))))
+
+	for _, value := range values {
+		data.WriteString(formatABIWord(value))
+	}
+
+	return data.String()
+}
+
+func formatABIWord(value *big.Int) string {
+	return fmt.Sprintf("%064x", value)
+}
+
+func decodeABIAddress(hexStr string) (string, error) {
+	data, err := decodeHexBytes(hexStr)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < 32 {
+		return "", fmt.Errorf("ABI address response too short: %d bytes", len(data))
+	}
+
+	return "0x" + hex.EncodeToString(data[12:32]), nil
+}
+
+func decodeABIUint256(hexStr string) (*big.Int, error) {
+	data, err := decodeHexBytes(hexStr)
+	if err != nil {
+		return nil, err
+	}
+
+	word, err := abiWord(data, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(word), nil
+}
+
+func decodeABIUint256Array(hexStr string) ([]*big.Int, error) {
+	data, offset, length, err := decodeDynamicABIArrayHeader(hexStr)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]*big.Int, length)
+
+	for i := range length {
+		word, wordErr := abiWord(data, offset+32+i*32)
+		if wordErr != nil {
+			return nil, wordErr
+		}
+
+		values[i] = new(big.Int).SetBytes(word)
+	}
+
+	return values, nil
+}
+
+func decodeABIAddressArray(hexStr string) ([]string, error) {
+	data, offset, length, err := decodeDynamicABIArrayHeader(hexStr)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, length)
+
+	for i := range length {
+		word, wordErr := abiWord(data, offset+32+i*32)
+		if wordErr != nil {
+			return nil, wordErr
+		}
+
+		values[i] = "0x" + hex.EncodeToString(word[12:])
+	}
+
+	return values, nil
+}
+
+func decodeDynamicABIArrayHeader(hexStr string) ([]byte, int, int, error) {
+	data, err := decodeHexBytes(hexStr)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	offset, err := decodeABIWordAsInt(data, 0)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	length, err := decodeABIWordAsInt(data, offset)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return data, offset, length, nil
+}
+
+func decodeABIWordAsBigInt(data []byte, offset int) (*big.Int, error) {
+	word, err := abiWord(data, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(word), nil
+}
+
+func decodeABIWordAsBool(data []byte, offset int) (bool, error) {
+	value, err := decodeABIWordAsBigInt(data, offset)
+	if err != nil {
+		return false, err
+	}
+
+	return value.Sign() != 0, nil
+}
+
+func decodeABIWordAsInt(data []byte, offset int) (int, error) {
+	value, err := decodeABIWordAsBigInt(data, offset)
+	if err != nil {
+		return 0, err
+	}
+
+	if !value.IsInt64() {
+		return 0, fmt.Errorf("ABI value does not fit in int64: %s", value.String())
+	}
+
+	int64Value := value.Int64()
+	if int64Value < 0 || int64Value > int64(^uint(0)>>1) {
+		return 0, fmt.Errorf("ABI value does not fit in int: %s", value.String())
+	}
+
+	return int(int64Value), nil
+}
+
+func abiWord(data []byte, offset int) ([]byte, error) {
+	if offset < 0 {
+		return nil, errors.New("negative ABI word offset")
+	}
+
+	if len(data) < offset+32 {
+		return nil, fmt.Errorf("ABI word at offset %d exceeds %d bytes", offset, len(data))
+	}
+
+	return data[offset : offset+32], nil
+}
+
+func decodeHexBytes(hexStr string) ([]byte, error) {
+	cleaned := strings.TrimPrefix(hexStr, "0x")
+	if cleaned == "" {
+		return nil, errors.New("empty hex string")
+	}
+
+	if len(cleaned)%2 != 0 {
+		cleaned = "0" + cleaned
+	}
+
+	return hex.DecodeString(cleaned)
+}
+
+// decodeABIWordAsSignedBigInt decodes a two's complement signed ABI word, e.g. int24 or int256.
+func decodeABIWordAsSignedBigInt(data []byte, offset int) (*big.Int, error) {
+	value, err := decodeABIWordAsBigInt(data, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.Bit(255) == 1 {
+		value.Sub(value, abiWordModulus)
+	}
+
+	return value, nil
+}
+
+// formatABISignedWord encodes a signed integer as a two's complement ABI word.
+func formatABISignedWord(value *big.Int) string {
+	if value.Sign() < 0 {
+		return formatABIWord(new(big.Int).Add(value, abiWordModulus))
+	}
+
+	return formatABIWord(value)
+}
+
+// subABIWords subtracts b from a with uint256 wrapping semantics, as used by
+// Uniswap fee growth accumulators.
+func subABIWords(a, b *big.Int) *big.Int {
+	result := new(big.Int).Sub(a, b)
+
+	return result.Mod(result, abiWordModulus)
+}
+
+const (
+	abiKindAddress = "address"
+	abiKindBool    = "bool"
+	abiKindUint    = "uint"
+	abiKindInt     = "int"
+	abiKindBytes   = "bytes"
+	abiKindString  = "string"
+	abiKindTuple   = "tuple"
+	abiKindArray   = "array"
+)
+
+// abiType is a parsed solidity ABI type, e.g. uint256, (address,bool) or bytes32[].
+type abiType struct {
+	kind string
+	// size is the bit size of uint/int types, the byte size of fixed bytes types and 0 otherwise.
+	size       int
+	components []abiType
+}
+
+// abiFunction is a parsed function signature such as
+// getPoolData(address)(uint256,uint256,bool).
+type abiFunction struct {
+	name     string
+	inputs   []abiType
+	outputs  []abiType
+	selector string
+}
+
+// parseABIFunction parses a human readable function signature with its return
+// types, e.g. balanceOf(address)(uint256).
+func parseABIFunction(signature string) (*abiFunction, error) {
+	signature = strings.ReplaceAll(signature, " ", "")
+
+	open := strings.Index(signature, "(")
+	if open <= 0 {
+		return nil, fmt.Errorf("invalid function signature %q: missing function name or arguments", signature)
+	}
+
+	inputsEnd, err := matchingParenthesis(signature, open)
+	if err != nil {
+		return nil, fmt.Errorf("invalid function signature %q: %w", signature, err)
+	}
+
+	inputs, err := parseABITupleComponents(signature[open+1 : inputsEnd])
+	if err != nil {
+		return nil, fmt.Errorf("invalid function signature %q: %w", signature, err)
+	}
+
+	rest := signature[inputsEnd+1:]
+	if rest == "" || rest[0] != '(' {
+		return nil, fmt.Errorf("invalid function signature %q: missing return types", signature)
+	}
+
+	outputsEnd, err := matchingParenthesis(rest, 0)
+	if err != nil || outputsEnd != len(rest)-1 {
+		return nil, fmt.Errorf("invalid function signature %q: malformed return types", signature)
+	}
+
+	outputs, err := parseABITupleComponents(rest[1:outputsEnd])
+	if err != nil {
+		return nil, fmt.Errorf("invalid function signature %q: %w", signature, err)
+	}
+
+	function := &abiFunction{
+		name:    signature[:open],
+		inputs:  inputs,
+		outputs: outputs,
+	}
+
+	function.selector = abiSelector(function.name + "(" + canonicalABITypes(inputs) + ")")
+
+	return function, nil
+}
+
+// abiSelector returns the 4 byte function selector of a canonical signature.
+func abiSelector(canonicalSignature string) string {
+	return "0x" + hex.EncodeToString(keccak256([]byte(canonicalSignature))[:4])
+}
+
+// encodeCall ABI encodes a call to the function with the given string arguments.
+// Only static input types are supported.
+func (f *abiFunction) encodeCall(args []string) (string, error) {
+	if len(args) != len(f.inputs) {
+		return "", fmt.Errorf("function %s expects %d arguments, got %d", f.name, len(f.inputs), len(args))
+	}
+
+	var data strings.Builder
+
+	data.WriteString(f.selector)
+
+	for i, input := range f.inputs {
+		encoded, err := encodeABIArgument(input, args[i])
+		if err != nil {
+			return "", fmt.Errorf("argument %d: %w", i, err)
+		}
+
+		data.WriteString(encoded)
+	}
+
+	return data.String(), nil
+}
+
+// outputType returns the type of the return value at path, where each element
+// indexes into the return values and then into nested tuples.
+func (f *abiFunction) outputType(path []int) (abiType, error) {
+	types := f.outputs
+
+	for depth, index := range path {
+		if index < 0 || index >= len(types) {
+			return abiType{}, fmt.Errorf("return value path %v out of range", path)
+		}
+
+		if depth == len(path)-1 {
+			return types[index], nil
+		}
+
+		if types[index].kind != abiKindTuple {
+			return abiType{}, fmt.Errorf("return value path %v indexes into non-tuple type", path)
+		}
+
+		types = types[index].components
+	}
+
+	return abiType{}, errors.New("empty return value path")
+}
+
+// decodeNumericOutput decodes the numeric or bool return value at path.
+func (f *abiFunction) decodeNumericOutput(data []byte, path []int) (*big.Int, error) {
+	valueType, err := f.outputType(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !valueType.isNumeric() {
+		return nil, fmt.Errorf("return value at path %v has non numeric type %s", path, valueType.canonical())
+	}
+
+	base, head, types := 0, 0, f.outputs
+
+	for _, index := range path {
+		for _, previous := range types[:index] {
+			head += previous.headSize()
+		}
+
+		selected := types[index]
+
+		if selected.kind == abiKindTuple && selected.isDynamic() {
+			offset, offsetErr := decodeABIWordAsInt(data, head)
+			if offsetErr != nil {
+				return nil, offsetErr
+			}
+
+			base += offset
+			head = base
+		}
+
+		types = selected.components
+	}
+
+	if valueType.kind == abiKindInt {
+		return decodeABIWordAsSignedBigInt(data, head)
+	}
+
+	return decodeABIWordAsBigInt(data, head)
+}
+
+func (t abiType) isNumeric() bool {
+	return t.kind == abiKindUint || t.kind == abiKindInt || t.kind == abiKindBool
+}
+
+// fits reports whether an integer lies within the range of a uint or int type,
+// [0, 2^size) and [-2^(size-1), 2^(size-1)) respectively.
+func (t abiType) fits(value *big.Int) bool {
+	if t.kind == abiKindUint {
+		return value.Sign() >= 0 && value.BitLen() <= t.size
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.size-1))
+
+	return value.Cmp(new(big.Int).Neg(limit)) >= 0 && value.Cmp(limit) < 0
+}
+
+func (t abiType) isDynamic() bool {
+	switch t.kind {
+	case abiKindString, abiKindArray:
+		return true
+	case abiKindBytes:
+		return t.size == 0
+	case abiKindTuple:
+		for _, component := range t.components {
+			if component.isDynamic() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// headSize returns the number of bytes the type occupies in the head of an encoding.
+func (t abiType) headSize() int {
+	if t.kind == abiKindTuple && !t.isDynamic() {
+		size := 0
+		for _, component := range t.components {
+			size += component.headSize()
+		}
+
+		return size
+	}
+
+	return 32
+}
+
+func (t abiType) canonical() string {
+	switch t.kind {
+	case abiKindUint, abiKindInt:
+		return fmt.Sprintf("%s%d", t.kind, t.size)
+	case abiKindBytes:
+		if t.size == 0 {
+			return abiKindBytes
+		}
+
+		return fmt.Sprintf("%s%d", t.kind, t.size)
+	case abiKindTuple:
+		return "(" + canonicalABITypes(t.components) + ")"
+	case abiKindArray:
+		return t.components[0].canonical() + "[]"
+	default:
+		return t.kind
+	}
+}
+
+func canonicalABITypes(types []abiType) string {
+	canonical := make([]string, len(types))
+	for i, t := range types {
+		canonical[i] = t.canonical()
+	}
+
+	return strings.Join(canonical, ",")
+}
+
+func parseABITupleComponents(list string) ([]abiType, error) {
+	if list == "" {
+		return []abiType{}, nil
+	}
+
+	parts := make([]string, 0)
+	depth, start := 0, 0
+
+	for i, char := range list {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	parts = append(parts, list[start:])
+
+	types := make([]abiType, len(parts))
+
+	for i, part := range parts {
+		parsed, err := parseABIType(part)
+		if err != nil {
+			return nil, err
+		}
+
+		types[i] = parsed
+	}
+
+	return types, nil
+}
+
+func parseABIType(typeStr string) (abiType, error) {
+	if strings.HasSuffix(typeStr, "[]") {
+		element, err := parseABIType(strings.TrimSuffix(typeStr, "[]"))
+		if err != nil {
+			return abiType{}, err
+		}
+
+		return abiType{kind: abiKindArray, components: []abiType{element}}, nil
+	}
+
+	if strings.HasPrefix(typeStr, "(") {
+		end, err := matchingParenthesis(typeStr, 0)
+		if err != nil || end != len(typeStr)-1 {
+			return abiType{}, fmt.Errorf("malformed tuple type %q", typeStr)
+		}
+
+		components, err := parseABITupleComponents(typeStr[1:end])
+		if err != nil {
+			return abiType{}, err
+		}
+
+		return abiType{kind: abiKindTuple, components: components}, nil
+	}
+
+	switch {
+	case typeStr == abiKindAddress, typeStr == abiKindBool, typeStr == abiKindString:
+		return abiType{kind: typeStr}, nil
+	case typeStr == abiKindBytes:
+		return abiType{kind: abiKindBytes}, nil
+	case strings.HasPrefix(typeStr, abiKindBytes):
+		return parseSizedABIType(abiKindBytes, strings.TrimPrefix(typeStr, abiKindBytes), 1, 32, 1)
+	case strings.HasPrefix(typeStr, abiKindUint):
+		return parseSizedABIType(abiKindUint, strings.TrimPrefix(typeStr, abiKindUint), 8, 256, 8)
+	case strings.HasPrefix(typeStr, abiKindInt):
+		return parseSizedABIType(abiKindInt, strings.TrimPrefix(typeStr, abiKindInt), 8, 256, 8)
+	}
+
+	return abiType{}, fmt.Errorf("unsupported ABI type %q", typeStr)
+}
+
+func parseSizedABIType(kind, sizeStr string, minSize, maxSize, step int) (abiType, error) {
+	if sizeStr == "" {
+		if kind == abiKindBytes {
+			return abiType{kind: abiKindBytes}, nil
+		}
+
+		return abiType{kind: kind, size: 256}, nil
+	}
+
+	size := 0
+	if _, err := fmt.Sscanf(sizeStr, "%d", &size); err != nil || fmt.Sprint(size) != sizeStr {
+		return abiType{}, fmt.Errorf("invalid ABI type size %q", kind+sizeStr)
+	}
+
+	if size < minSize || size > maxSize || size%step != 0 {
+		return abiType{}, fmt.Errorf("invalid ABI type size %q", kind+sizeStr)
+	}
+
+	return abiType{kind: kind, size: size}, nil
+}
+
+func matchingParenthesis(s string, open int) (int, error) {
+	depth := 0
+
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, errors.New("unbalanced parentheses")
+}
+
+// encodeABIArgument encodes a single static argument from its string representation.
+func encodeABIArgument(t abiType, value string) (string, error) {
+	switch t.kind {
+	case abiKindAddress:
+		return encodeAddressArgument(value)
+	case abiKindBool:
+		switch strings.ToLower(value) {
+		case "true", "1":
+			return formatABIWord(big.NewInt(1)), nil
+		case "false", "0":
+			return formatABIWord(big.NewInt(0)), nil
+		}
+
+		return "", fmt.Errorf("invalid bool %q", value)
+	case abiKindUint, abiKindInt:
+		parsed, ok := new(big.Int).SetString(value, 0)
+		if !ok {
+			return "", fmt.Errorf("invalid integer %q", value)
+		}
+
+		if t.kind == abiKindUint && parsed.Sign() < 0 {
+			return "", fmt.Errorf("negative value %q for %s", value, t.canonical())
+		}
+
+		if !t.fits(parsed) {
+			return "", fmt.Errorf("value %q out of range for %s", value, t.canonical())
+		}
+
+		return formatABISignedWord(parsed), nil
+	case abiKindBytes:
+		if t.size == 0 {
+			break
+		}
+
+		cleaned := strings.TrimPrefix(strings.ToLower(value), "0x")
+		if len(cleaned) != t.size*2 {
+			return "", fmt.Errorf("invalid %s length %q", t.canonical(), value)
+		}
+
+		if _, err := hex.DecodeString(cleaned); err != nil {
+			return "", fmt.Errorf("invalid %s %q: %w", t.canonical(), value, err)
+		}
+
+		return cleaned + strings.Repeat("0", 64-len(cleaned)), nil
+	}
+
+	return "", fmt.Errorf("unsupported argument type %s", t.canonical())
+}
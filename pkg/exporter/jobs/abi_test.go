@@ -0,0 +1,233 @@
+package jobs
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseABIFunction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		signature string
+		selector  string
+		outputs   string
+		wantErr   bool
+	}{
+		{
+			name:      "no arguments",
+			signature: "getReserves()(uint112,uint112,uint32)",
+			selector:  "0x0902f1ac",
+			outputs:   "uint112,uint112,uint32",
+		},
+		{
+			name:      "address argument",
+			signature: "balanceOf(address)(uint)",
+			selector:  "0x70a08231",
+			outputs:   "uint256",
+		},
+		{
+			name:      "multiple arguments with spaces",
+			signature: "allowance(address, address)(uint256)",
+			selector:  "0xdd62ed3e",
+			outputs:   "uint256",
+		},
+		{
+			name:      "signed argument",
+			signature: "ticks(int24)(uint128,int128,uint256,uint256,int56,uint160,uint32,bool)",
+			selector:  "0xf30dba93",
+			outputs:   "uint128,int128,uint256,uint256,int56,uint160,uint32,bool",
+		},
+		{
+			name:      "tuple output",
+			signature: "getSlot0(bytes32)((uint160,int24),string,uint256[])",
+			selector:  "0xc815641c",
+			outputs:   "(uint160,int24),string,uint256[]",
+		},
+		{
+			name:      "missing return types",
+			signature: "balanceOf(address)",
+			wantErr:   true,
+		},
+		{
+			name:      "unbalanced parentheses",
+			signature: "balanceOf(address(uint256)",
+			wantErr:   true,
+		},
+		{
+			name:      "invalid type size",
+			signature: "foo(uint7)(uint256)",
+			wantErr:   true,
+		},
+		{
+			name:      "unsupported type",
+			signature: "foo(fixed128x18)(uint256)",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			function, err := parseABIFunction(tt.signature)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.selector, function.selector)
+			assert.Equal(t, tt.outputs, canonicalABITypes(function.outputs))
+		})
+	}
+}
+
+func TestABIFunction_encodeCall(t *testing.T) {
+	t.Parallel()
+
+	function, err := parseABIFunction("foo(address,uint256,int8,bool,bytes4)(uint256)")
+	require.NoError(t, err)
+
+	data, err := function.encodeCall([]string{testContractAAddress, "0x10", "-1", "true", "0xdeadbeef"})
+	require.NoError(t, err)
+
+	address, err := encodeAddressArgument(testContractAAddress)
+	require.NoError(t, err)
+
+	expected := function.selector +
+		address +
+		formatABIWord(big.NewInt(16)) +
+		formatABISignedWord(big.NewInt(-1)) +
+		formatABIWord(big.NewInt(1)) +
+		"deadbeef" + formatABIWord(big.NewInt(0))[8:]
+
+	assert.Equal(t, expected, data)
+
+	_, err = function.encodeCall([]string{testContractAAddress})
+	require.ErrorContains(t, err, "expects 5 arguments")
+
+	_, err = function.encodeCall([]string{testContractAAddress, "-1", "0", "false", "0xdeadbeef"})
+	require.ErrorContains(t, err, "negative value")
+
+	_, err = function.encodeCall([]string{testContractAAddress, "1", "0", "maybe", "0xdeadbeef"})
+	require.ErrorContains(t, err, "invalid bool")
+
+	dynamic, err := parseABIFunction("foo(string)(uint256)")
+	require.NoError(t, err)
+
+	_, err = dynamic.encodeCall([]string{"bar"})
+	require.ErrorContains(t, err, "unsupported argument type")
+}
+
+func TestEncodeABIArgument_IntegerRange(t *testing.T) {
+	t.Parallel()
+
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	tests := []struct {
+		typ     string
+		value   string
+		wantErr string
+	}{
+		{typ: "uint8", value: "0"},
+		{typ: "uint8", value: "255"},
+		{typ: "uint8", value: "256", wantErr: "out of range"},
+		{typ: "uint8", value: "-1", wantErr: "negative value"},
+		{typ: "uint32", value: "0xffffffff"},
+		{typ: "uint32", value: "0x100000000", wantErr: "out of range"},
+		{typ: "uint256", value: maxUint256.String()},
+		{typ: "uint256", value: new(big.Int).Add(maxUint256, big.NewInt(1)).String(), wantErr: "out of range"},
+		{typ: "int8", value: "127"},
+		{typ: "int8", value: "-128"},
+		{typ: "int8", value: "128", wantErr: "out of range"},
+		{typ: "int8", value: "-129", wantErr: "out of range"},
+		{typ: "int256", value: new(big.Int).Rsh(maxUint256, 1).String()},
+		{typ: "int256", value: new(big.Int).Add(new(big.Int).Rsh(maxUint256, 1), big.NewInt(1)).String(), wantErr: "out of range"},
+		{typ: "int256", value: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255)).String()},
+		{typ: "int256", value: new(big.Int).Sub(new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255)), big.NewInt(1)).String(), wantErr: "out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			typ, err := parseABIType(tt.typ)
+			require.NoError(t, err)
+
+			word, err := encodeABIArgument(typ, tt.value)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, word, 64)
+		})
+	}
+}
+
+func TestABIFunction_decodeNumericOutput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("static values", func(t *testing.T) {
+		t.Parallel()
+
+		function, err := parseABIFunction("getPoolData(address)(uint256,(int256,bool),uint256)")
+		require.NoError(t, err)
+
+		data, err := decodeHexBytes(encodeABIWordsReturn(big.NewInt(7), big.NewInt(-3), big.NewInt(1), big.NewInt(9)))
+		require.NoError(t, err)
+
+		tests := []struct {
+			path     []int
+			expected int64
+		}{
+			{path: []int{0}, expected: 7},
+			{path: []int{1, 0}, expected: -3},
+			{path: []int{1, 1}, expected: 1},
+			{path: []int{2}, expected: 9},
+		}
+
+		for _, tt := range tests {
+			value, err := function.decodeNumericOutput(data, tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value.Int64(), "path %v", tt.path)
+		}
+
+		_, err = function.decodeNumericOutput(data, []int{1})
+		require.ErrorContains(t, err, "non numeric type")
+
+		_, err = function.decodeNumericOutput(data, []int{3})
+		require.ErrorContains(t, err, "out of range")
+	})
+
+	t.Run("dynamic tuple", func(t *testing.T) {
+		t.Parallel()
+
+		function, err := parseABIFunction("foo()(uint256,(uint256,string))")
+		require.NoError(t, err)
+
+		// value, offset of the tuple, tuple: value, offset of the string, string length, string data.
+		data, err := decodeHexBytes(encodeABIWordsReturn(
+			big.NewInt(5), big.NewInt(64),
+			big.NewInt(11), big.NewInt(64),
+			big.NewInt(0), big.NewInt(0),
+		))
+		require.NoError(t, err)
+
+		value, err := function.decodeNumericOutput(data, []int{1, 0})
+		require.NoError(t, err)
+		assert.Equal(t, int64(11), value.Int64())
+
+		value, err = function.decodeNumericOutput(data, []int{0})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), value.Int64())
+	})
+}
@@ -2,36 +2,117 @@ package jobs
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // Account exposes metrics for account addresses.
 type Account struct {
-	clients        []api.ExecutionClient
-	log            logrus.FieldLogger
-	AccountBalance prometheus.GaugeVec
-	AccountError   prometheus.CounterVec
-	checkInterval  time.Duration
-	addresses      []*AddressAccount
-	labelsMap      map[string]int
+	*tickTracker
+
+	clients                []api.ExecutionClient
+	log                    logrus.FieldLogger
+	AccountBalance         prometheus.GaugeVec
+	AccountNonce           prometheus.GaugeVec
+	AccountPendingNonceGap prometheus.GaugeVec
+	AccountHasCode         prometheus.GaugeVec
+	AccountDelegated       prometheus.GaugeVec
+	AccountError           prometheus.CounterVec
+	AccountETHSent         prometheus.CounterVec
+	AccountETHReceived     prometheus.CounterVec
+	AccountGasSpent        prometheus.CounterVec
+	AccountTxsSent         prometheus.CounterVec
+	AccountTxsFailed       prometheus.CounterVec
+	AccountSpendRate       prometheus.GaugeVec
+	AccountTimeToEmpty     prometheus.GaugeVec
+	AccountBelowThreshold  prometheus.GaugeVec
+	checkInterval          time.Duration
+	addresses              []*AddressAccount
+	labelsMap              map[string]int
+	// flowBlocks holds the next block to scan for native ETH flows per execution client.
+	flowBlocks map[string]uint64
+	// spendSamples holds the balance samples within the spend rate window per execution client and address name.
+	spendSamples map[accountStateKey][]accountBalanceSample
+	alerts       *alerts.Manager
+	snapshots    *snapshot.Store
 }
 
 type AddressAccount struct {
-	Address string            `yaml:"address"`
-	Name    string            `yaml:"name"`
-	Labels  map[string]string `yaml:"labels"`
+	Address string `yaml:"address"`
+	// Flows scans the transactions of each new block for native ETH sent and received by the address.
+	Flows bool `yaml:"flows"`
+	// State reads the nonce, pending nonce and code of the address on each check.
+	State     bool              `yaml:"state"`
+	SpendRate *AccountSpendRate `yaml:"spendRate"`
+	// Alerts are evaluated on the balance of the address.
+	Alerts []*alerts.Rule    `yaml:"alerts"`
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// AccountSpendRate estimates how fast an account address spends its balance.
+type AccountSpendRate struct {
+	// Window is how far back balance samples are kept to estimate the spend
+	// rate, 0 selects the default of 1h.
+	Window time.Duration `yaml:"window"`
+	// MinBalance is the balance in wei below which the address is reported as below threshold.
+	MinBalance float64 `yaml:"minBalance"`
+}
+
+type accountStateKey struct {
+	execution string
+	name      string
+}
+
+type accountBalanceSample struct {
+	at      time.Time
+	balance float64
 }
 
 // GetName returns the configured name of this address.
 func (a *AddressAccount) GetName() string { return a.Name }
 
+// Validate checks the spend rate window and minimum balance.
+func (a *AddressAccount) Validate() error {
+	if a.SpendRate == nil {
+		return nil
+	}
+
+	if a.SpendRate.Window < 0 {
+		return fmt.Errorf("account %s: spend rate window must not be negative", a.Name)
+	}
+
+	if a.SpendRate.MinBalance < 0 {
+		return fmt.Errorf("account %s: spend rate minBalance must not be negative", a.Name)
+	}
+
+	return nil
+}
+
 const (
 	NameAccount = "account"
+
+	// eip7702DelegationPrefix prefixes the code of an EOA that has delegated to a contract via EIP-7702.
+	eip7702DelegationPrefix = "0xef0100"
+
+	// accountFlowsMaxBlocks bounds the blocks scanned for native ETH flows per tick,
+	// the remaining blocks are scanned on the following ticks.
+	accountFlowsMaxBlocks = 64
+	// accountReceiptStatusSuccess is the status of a receipt of a transaction that did not revert.
+	accountReceiptStatusSuccess = "0x1"
+	// accountSpendRateDefaultWindow is the spend rate window used when none is configured.
+	accountSpendRateDefaultWindow = time.Hour
 )
 
 func (n *Account) Name() string {
@@ -39,7 +120,7 @@ func (n *Account) Name() string {
 }
 
 // NewAccount returns a new Account instance.
-func NewAccount(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressAccount) Account {
+func NewAccount(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressAccount, alerting *alerts.Manager, snapshots *snapshot.Store, registerer prometheus.Registerer) Account {
 	namespace += "_" + NameAccount
 
 	labelsMap := make(map[string]int, 3)
@@ -63,9 +144,14 @@ func NewAccount(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 	instance := Account{
 		clients:       clients,
 		log:           log.WithField("module", NameAccount),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
+		flowBlocks:    make(map[string]uint64),
+		spendSamples:  make(map[accountStateKey][]accountBalanceSample),
+		alerts:        alerting,
 		AccountBalance: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace:   namespace,
@@ -75,6 +161,42 @@ func NewAccount(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 			},
 			labels,
 		),
+		AccountNonce: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "nonce",
+				Help:        "The nonce of a account address at the latest block.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountPendingNonceGap: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "pending_nonce_gap",
+				Help:        "The number of pending transactions of a account address, the pending minus latest nonce.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountHasCode: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "has_code",
+				Help:        "Whether a account address has code, including EIP-7702 delegation designators.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountDelegated: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "eip7702_delegated",
+				Help:        "Whether a account address code is an EIP-7702 delegation designator.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
 		AccountError: *prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace:   namespace,
@@ -84,16 +206,101 @@ func NewAccount(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 			},
 			labels,
 		),
+		AccountETHSent: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "eth_sent_total",
+				Help:        "The total ETH in wei sent by successful transactions of a account address.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountETHReceived: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "eth_received_total",
+				Help:        "The total ETH in wei received by a account address from successful transactions sent to it.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountGasSpent: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "gas_spent_total",
+				Help:        "The total transaction fees in wei paid by a account address, including blob fees.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountTxsSent: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "transactions_sent_total",
+				Help:        "The total transactions sent by a account address.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountTxsFailed: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "transactions_failed_total",
+				Help:        "The total transactions sent by a account address that reverted.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountSpendRate: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "spend_rate_per_hour",
+				Help:        "The estimated wei spent per hour by a account address, from its balance decreases within the spend rate window.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountTimeToEmpty: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "time_to_empty_seconds",
+				Help:        "The projected seconds until a account address balance runs out at its spend rate, +Inf when not spending.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		AccountBelowThreshold: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "below_threshold",
+				Help:        "Whether a account address balance is below its configured minimum balance.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
 	}
 
-	prometheus.MustRegister(instance.AccountBalance)
-	prometheus.MustRegister(instance.AccountError)
+	registerer.MustRegister(instance.AccountBalance)
+	registerer.MustRegister(instance.AccountNonce)
+	registerer.MustRegister(instance.AccountPendingNonceGap)
+	registerer.MustRegister(instance.AccountHasCode)
+	registerer.MustRegister(instance.AccountDelegated)
+	registerer.MustRegister(instance.AccountError)
+	registerer.MustRegister(instance.AccountETHSent)
+	registerer.MustRegister(instance.AccountETHReceived)
+	registerer.MustRegister(instance.AccountGasSpent)
+	registerer.MustRegister(instance.AccountTxsSent)
+	registerer.MustRegister(instance.AccountTxsFailed)
+	registerer.MustRegister(instance.AccountSpendRate)
+	registerer.MustRegister(instance.AccountTimeToEmpty)
+	registerer.MustRegister(instance.AccountBelowThreshold)
 
 	return instance
 }
 
 func (n *Account) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -102,6 +309,7 @@ func (n *Account) Start(ctx context.Context) {
 		case <-time.After(n.checkInterval):
 			n.log.WithField("asd", n.checkInterval).Debug("Tick")
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
@@ -116,8 +324,24 @@ func (n *Account) tick(ctx context.Context) {
 					LabelExecution: client.Name(),
 				}).Error("Failed to get Account balance")
 			}
+
+			if address.State {
+				err = n.getState(ctx, client, address)
+				if err != nil {
+					n.log.WithError(err).WithFields(logrus.Fields{
+						LabelAddress:   address,
+						LabelExecution: client.Name(),
+					}).Error("Failed to get Account nonce and code")
+				}
+			}
+		}
+
+		if err := n.getFlows(ctx, client); err != nil {
+			n.log.WithError(err).WithField(LabelExecution, client.Name()).Error("Failed to get Account ETH flows")
 		}
 	}
+
+	n.alerts.Evaluate(ctx, NameAccount, time.Now())
 }
 
 func (n *Account) getLabelValues(address *AddressAccount, executionName string) []string {
@@ -149,6 +373,7 @@ func (n *Account) getBalance(ctx context.Context, client api.ExecutionClient, ad
 	defer func() {
 		if err != nil {
 			n.AccountError.WithLabelValues(n.getLabelValues(address, client.Name())...).Inc()
+			n.snapshots.SetError(NameAccount, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
@@ -159,6 +384,280 @@ func (n *Account) getBalance(ctx context.Context, client api.ExecutionClient, ad
 
 	balanceFloat64 := hexStringToFloat64(balance)
 	n.AccountBalance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(balanceFloat64)
+	n.alerts.Observe(alerts.Target{Job: NameAccount, Name: address.Name, Execution: client.Name()}, address.Alerts, balanceFloat64, time.Now())
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameAccount,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Value:     balanceFloat64,
+	}, time.Now())
+
+	if address.SpendRate != nil {
+		n.setSpendRate(address, client.Name(), balanceFloat64, time.Now())
+	}
 
 	return nil
 }
+
+// setSpendRate adds a balance sample to the sliding window and exports the
+// spend rate, estimated from the balance decreases within the window so that
+// top ups do not offset spending, and the projected time until empty.
+func (n *Account) setSpendRate(address *AddressAccount, executionName string, balance float64, now time.Time) {
+	window := address.SpendRate.Window
+	if window == 0 {
+		window = accountSpendRateDefaultWindow
+	}
+
+	key := accountStateKey{execution: executionName, name: address.Name}
+
+	samples := append(n.spendSamples[key], accountBalanceSample{at: now, balance: balance})
+
+	first := 0
+	for first < len(samples)-1 && now.Sub(samples[first].at) > window {
+		first++
+	}
+
+	samples = samples[first:]
+	n.spendSamples[key] = samples
+
+	labels := n.getLabelValues(address, executionName)
+
+	belowThreshold := 0.0
+	if balance < address.SpendRate.MinBalance {
+		belowThreshold = 1
+	}
+
+	n.AccountBelowThreshold.WithLabelValues(labels...).Set(belowThreshold)
+
+	elapsed := now.Sub(samples[0].at)
+	if elapsed <= 0 {
+		return
+	}
+
+	spent := 0.0
+
+	for i := 1; i < len(samples); i++ {
+		if decrease := samples[i-1].balance - samples[i].balance; decrease > 0 {
+			spent += decrease
+		}
+	}
+
+	ratePerHour := spent / elapsed.Hours()
+
+	timeToEmpty := math.Inf(1)
+	if ratePerHour > 0 {
+		timeToEmpty = balance / ratePerHour * time.Hour.Seconds()
+	}
+
+	n.AccountSpendRate.WithLabelValues(labels...).Set(ratePerHour)
+	n.AccountTimeToEmpty.WithLabelValues(labels...).Set(timeToEmpty)
+}
+
+func (n *Account) getState(ctx context.Context, client api.ExecutionClient, address *AddressAccount) error {
+	var err error
+
+	defer func() {
+		if err != nil {
+			n.AccountError.WithLabelValues(n.getLabelValues(address, client.Name())...).Inc()
+			n.snapshots.SetError(NameAccount, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	latest, err := client.ETHGetTransactionCount(ctx, address.Address, "latest")
+	if err != nil {
+		return err
+	}
+
+	pending, err := client.ETHGetTransactionCount(ctx, address.Address, "pending")
+	if err != nil {
+		return err
+	}
+
+	code, err := client.ETHGetCode(ctx, address.Address, "latest")
+	if err != nil {
+		return err
+	}
+
+	labels := n.getLabelValues(address, client.Name())
+
+	latestNonce := hexStringToFloat64(latest)
+	n.AccountNonce.WithLabelValues(labels...).Set(latestNonce)
+	n.AccountPendingNonceGap.WithLabelValues(labels...).Set(hexStringToFloat64(pending) - latestNonce)
+
+	hasCode, delegated := 0.0, 0.0
+
+	if code != "" && code != "0x" {
+		hasCode = 1
+	}
+
+	if strings.HasPrefix(strings.ToLower(code), eip7702DelegationPrefix) {
+		delegated = 1
+	}
+
+	n.AccountHasCode.WithLabelValues(labels...).Set(hasCode)
+	n.AccountDelegated.WithLabelValues(labels...).Set(delegated)
+
+	return nil
+}
+
+// accountFlows holds the native ETH flows of an address within a block.
+type accountFlows struct {
+	sent      *big.Int
+	received  *big.Int
+	gasSpent  *big.Int
+	txsSent   int
+	txsFailed int
+}
+
+// getFlows scans the blocks since the last tick for transactions sent from or
+// to the addresses with flows enabled. Only top level transactions are seen,
+// ETH moved by internal calls of contracts is not counted.
+func (n *Account) getFlows(ctx context.Context, client api.ExecutionClient) error {
+	tracked := make(map[string][]*AddressAccount)
+
+	for _, address := range n.addresses {
+		if address.Flows {
+			key := strings.ToLower(address.Address)
+			tracked[key] = append(tracked[key], address)
+		}
+	}
+
+	if len(tracked) == 0 {
+		return nil
+	}
+
+	var err error
+
+	defer func() {
+		if err != nil {
+			for _, addresses := range tracked {
+				for _, address := range addresses {
+					n.AccountError.WithLabelValues(n.getLabelValues(address, client.Name())...).Inc()
+				}
+			}
+		}
+	}()
+
+	head, err := getBlockNumber(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	next, ok := n.flowBlocks[client.Name()]
+	if !ok {
+		next = head
+	}
+
+	last := min(head, next+accountFlowsMaxBlocks-1)
+
+	for number := next; number <= last; number++ {
+		var flows map[string]*accountFlows
+
+		flows, err = n.getBlockFlows(ctx, client, number, tracked)
+		if err != nil {
+			return fmt.Errorf("failed to scan block %d: %w", number, err)
+		}
+
+		for key, flow := range flows {
+			for _, address := range tracked[key] {
+				labels := n.getLabelValues(address, client.Name())
+
+				n.AccountETHSent.WithLabelValues(labels...).Add(bigIntToFloat64(flow.sent))
+				n.AccountETHReceived.WithLabelValues(labels...).Add(bigIntToFloat64(flow.received))
+				n.AccountGasSpent.WithLabelValues(labels...).Add(bigIntToFloat64(flow.gasSpent))
+				n.AccountTxsSent.WithLabelValues(labels...).Add(float64(flow.txsSent))
+				n.AccountTxsFailed.WithLabelValues(labels...).Add(float64(flow.txsFailed))
+			}
+		}
+
+		n.flowBlocks[client.Name()] = number + 1
+	}
+
+	return nil
+}
+
+// getBlockFlows returns the flows of the tracked addresses in a block, keyed
+// by lower case address. Receipts are only fetched for matching transactions.
+func (n *Account) getBlockFlows(ctx context.Context, client api.ExecutionClient, number uint64, tracked map[string][]*AddressAccount) (map[string]*accountFlows, error) {
+	block, err := client.ETHGetBlockByNumber(ctx, "0x"+strconv.FormatUint(number, 16), true)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make(map[string]*accountFlows)
+
+	getFlow := func(key string) *accountFlows {
+		if _, ok := flows[key]; !ok {
+			flows[key] = &accountFlows{sent: new(big.Int), received: new(big.Int), gasSpent: new(big.Int)}
+		}
+
+		return flows[key]
+	}
+
+	for _, tx := range block.Transactions {
+		from := strings.ToLower(tx.From)
+		to := strings.ToLower(tx.To)
+
+		_, isSender := tracked[from]
+		_, isReceiver := tracked[to]
+
+		if !isSender && !isReceiver {
+			continue
+		}
+
+		receipt, err := client.ETHGetTransactionReceipt(ctx, tx.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := hexStringToBigInt(tx.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		success := receipt.Status == accountReceiptStatusSuccess
+
+		if isSender {
+			fee, err := transactionFee(receipt)
+			if err != nil {
+				return nil, err
+			}
+
+			flow := getFlow(from)
+			flow.txsSent++
+			flow.gasSpent.Add(flow.gasSpent, fee)
+
+			if success {
+				flow.sent.Add(flow.sent, value)
+			} else {
+				flow.txsFailed++
+			}
+		}
+
+		if isReceiver && success {
+			flow := getFlow(to)
+			flow.received.Add(flow.received, value)
+		}
+	}
+
+	return flows, nil
+}
+
+// transactionFee returns the execution and blob fees paid for a transaction.
+func transactionFee(receipt *api.ETHTransactionReceipt) (*big.Int, error) {
+	values := make([]*big.Int, 4)
+
+	for i, hexStr := range []string{receipt.GasUsed, receipt.EffectiveGasPrice, receipt.BlobGasUsed, receipt.BlobGasPrice} {
+		value, err := hexStringToBigInt(hexStr)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	fee := new(big.Int).Mul(values[0], values[1])
+
+	return fee.Add(fee, new(big.Int).Mul(values[2], values[3])), nil
+}
@@ -2,11 +2,23 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 func TestAccount_getBalance(t *testing.T) {
@@ -72,6 +84,9 @@ func TestAccount_getBalance(t *testing.T) {
 				namespace,
 				map[string]string{},
 				[]*AddressAccount{tt.address},
+				nil,
+				nil,
+				testRegistry(),
 			)
 
 			err := account.getBalance(context.Background(), mockClient, tt.address)
@@ -111,6 +126,9 @@ func TestAccount_tick(t *testing.T) {
 		"test_account_tick",
 		map[string]string{},
 		addresses,
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
@@ -121,6 +139,52 @@ func TestAccount_tick(t *testing.T) {
 	if mockClient.ethGetBalanceCalls != len(addresses) {
 		t.Errorf("Expected %d ETHGetBalance calls, got %d", len(addresses), mockClient.ethGetBalanceCalls)
 	}
+
+	// State is not enabled on either address, so no nonce or code is read.
+	if mockClient.ethGetTransactionCountCalls != 0 || mockClient.ethGetCodeCalls != 0 {
+		t.Errorf("Expected no state calls, got %d eth_getTransactionCount and %d eth_getCode calls",
+			mockClient.ethGetTransactionCountCalls, mockClient.ethGetCodeCalls)
+	}
+}
+
+func TestAccount_tick_StateError(t *testing.T) {
+	mockClient := &mockExecutionClient{
+		ethGetBalanceResponse: "0x0de0b6b3a7640000",
+		ethGetCodeError:       errors.New("connection refused"),
+	}
+
+	address := &AddressAccount{
+		Name:    testNameTestAccount,
+		Address: testHolder1Address,
+		State:   true,
+		Labels:  map[string]string{},
+	}
+
+	snapshots := snapshot.NewStore(testLogger())
+
+	account := NewAccount(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"account_state_error",
+		map[string]string{},
+		[]*AddressAccount{address},
+		nil,
+		snapshots,
+		testRegistry(),
+	)
+
+	account.tick(context.Background())
+
+	assert.Equal(t, 2, mockClient.ethGetTransactionCountCalls)
+	assert.Equal(t, 1, mockClient.ethGetCodeCalls)
+	assert.InDelta(t, 1.0, testutil.ToFloat64(account.AccountError.WithLabelValues(testNameTestAccount, testHolder1Address, testMockNodeName)), 0)
+
+	node := snapshots.Snapshot()[NameAccount][0].Nodes[testMockNodeName]
+	require.NotNil(t, node)
+
+	assert.Equal(t, "connection refused", node.LastError)
+	assert.NotNil(t, node.LastErrorTimestamp)
 }
 
 func TestAccount_getLabelValues(t *testing.T) {
@@ -145,6 +209,9 @@ func TestAccount_getLabelValues(t *testing.T) {
 		"test_account_labels",
 		map[string]string{},
 		addresses,
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	labels := account.getLabelValues(addresses[0], "mock-node")
@@ -181,3 +248,369 @@ func TestAccount_Name(t *testing.T) {
 		t.Errorf("Expected name %s, got %s", NameAccount, account.Name())
 	}
 }
+
+func TestAccount_getState(t *testing.T) {
+	tests := []struct {
+		name          string
+		latest        string
+		pending       string
+		code          string
+		wantNonce     float64
+		wantGap       float64
+		wantHasCode   float64
+		wantDelegated float64
+	}{
+		{
+			name:      "eoa with pending transactions",
+			latest:    "0x2a",
+			pending:   "0x2d",
+			code:      "0x",
+			wantNonce: 42,
+			wantGap:   3,
+		},
+		{
+			name:        "contract",
+			latest:      "0x1",
+			pending:     "0x1",
+			code:        "0x6080604052",
+			wantNonce:   1,
+			wantHasCode: 1,
+		},
+		{
+			name:          "eip-7702 delegated eoa",
+			latest:        "0x5",
+			pending:       "0x5",
+			code:          "0xef01001111111111111111111111111111111111111111",
+			wantNonce:     5,
+			wantHasCode:   1,
+			wantDelegated: 1,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := &AddressAccount{
+				Name:    testNameTestAccount,
+				Address: testHolder1Address,
+				Labels:  map[string]string{},
+			}
+
+			mockClient := &mockExecutionClient{
+				ethGetTransactionCountResponses: map[string]string{
+					"latest":  tt.latest,
+					"pending": tt.pending,
+				},
+				ethGetCodeResponse: tt.code,
+			}
+
+			account := NewAccount(
+				mockClients(mockClient),
+				testLogger(),
+				15*time.Second,
+				"account_state_"+strconv.Itoa(i),
+				map[string]string{},
+				[]*AddressAccount{address},
+				nil,
+				nil,
+				testRegistry(),
+			)
+
+			if err := account.getState(context.Background(), mockClient, address); err != nil {
+				t.Fatalf("getState() error = %v", err)
+			}
+
+			labels := []string{testNameTestAccount, testHolder1Address, testMockNodeName}
+
+			assertMetricValue(t, account.AccountNonce, labels, tt.wantNonce)
+			assertMetricValue(t, account.AccountPendingNonceGap, labels, tt.wantGap)
+			assertMetricValue(t, account.AccountHasCode, labels, tt.wantHasCode)
+			assertMetricValue(t, account.AccountDelegated, labels, tt.wantDelegated)
+
+			if mockClient.ethGetTransactionCountCalls != 2 {
+				t.Errorf("Expected 2 eth_getTransactionCount calls, got %d", mockClient.ethGetTransactionCountCalls)
+			}
+
+			if mockClient.ethGetCodeCalls != 1 {
+				t.Errorf("Expected 1 eth_getCode call, got %d", mockClient.ethGetCodeCalls)
+			}
+		})
+	}
+}
+
+func TestAccount_getFlows(t *testing.T) {
+	address := &AddressAccount{
+		Name:    testNameTestAccount,
+		Address: testHolder1Address,
+		Flows:   true,
+		Labels:  map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethBlockNumberResponse: "0x65",
+		ethBlocks: map[uint64]*api.ETHBlock{
+			101: {Number: "0x65", Transactions: []*api.ETHTransaction{
+				{Hash: "0x01", From: testHolder1Address, To: testHolder2Address, Value: "0x3e8"},
+				{Hash: "0x02", From: testHolder1Address, To: testContractAAddress, Value: "0x1388"},
+				{Hash: "0x03", From: testHolder2Address, To: testHolder1Address, Value: "0x7d0"},
+				{Hash: "0x04", From: testHolder2Address, To: testContractAAddress, Value: "0x1"},
+			}},
+			102: {Number: "0x66"},
+			103: {Number: "0x67", Transactions: []*api.ETHTransaction{
+				{Hash: "0x05", From: testContractAAddress, To: testHolder1Address, Value: "0xbb8"},
+			}},
+		},
+		ethReceipts: map[string]*api.ETHTransactionReceipt{
+			"0x01": {Status: "0x1", GasUsed: "0xa", EffectiveGasPrice: "0x2", BlobGasUsed: "0x3", BlobGasPrice: "0x4"},
+			"0x02": {Status: "0x0", GasUsed: "0x14", EffectiveGasPrice: "0x2"},
+			"0x03": {Status: "0x1", GasUsed: "0xa", EffectiveGasPrice: "0x2"},
+			"0x05": {Status: "0x1", GasUsed: "0xa", EffectiveGasPrice: "0x2"},
+		},
+	}
+
+	account := NewAccount(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"account_flows",
+		map[string]string{},
+		[]*AddressAccount{address},
+		nil,
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, account.getFlows(context.Background(), mockClient))
+
+	labels := account.getLabelValues(address, testMockNodeName)
+
+	assert.InDelta(t, 1000, testutil.ToFloat64(account.AccountETHSent.WithLabelValues(labels...)), 0)
+	assert.InDelta(t, 2000, testutil.ToFloat64(account.AccountETHReceived.WithLabelValues(labels...)), 0)
+	assert.InDelta(t, 72, testutil.ToFloat64(account.AccountGasSpent.WithLabelValues(labels...)), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(account.AccountTxsSent.WithLabelValues(labels...)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(account.AccountTxsFailed.WithLabelValues(labels...)), 0)
+	assert.Equal(t, []string{"0x01", "0x02", "0x03"}, mockClient.ethGetReceiptCalls)
+
+	// The next tick scans the blocks since the last scanned block only once.
+	mockClient.ethBlockNumberResponse = "0x67"
+
+	require.NoError(t, account.getFlows(context.Background(), mockClient))
+
+	assert.Equal(t, []string{"0x65", "0x66", "0x67"}, mockClient.ethGetBlockByNumberCalls)
+	assert.InDelta(t, 5000, testutil.ToFloat64(account.AccountETHReceived.WithLabelValues(labels...)), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(account.AccountTxsSent.WithLabelValues(labels...)), 0)
+}
+
+func TestAccount_getFlows_RetriesFailedBlock(t *testing.T) {
+	address := &AddressAccount{
+		Name:    testNameTestAccount,
+		Address: testHolder1Address,
+		Flows:   true,
+		Labels:  map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethBlockNumberResponse: "0x65",
+		ethBlocks: map[uint64]*api.ETHBlock{
+			101: {Number: "0x65", Transactions: []*api.ETHTransaction{
+				{Hash: "0x01", From: testHolder2Address, To: testHolder1Address, Value: "0x3e8"},
+			}},
+		},
+		ethReceipts: map[string]*api.ETHTransactionReceipt{},
+	}
+
+	account := NewAccount(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"account_flows_retry",
+		map[string]string{},
+		[]*AddressAccount{address},
+		nil,
+		nil,
+		testRegistry(),
+	)
+
+	require.Error(t, account.getFlows(context.Background(), mockClient))
+
+	labels := account.getLabelValues(address, testMockNodeName)
+
+	assert.InDelta(t, 1, testutil.ToFloat64(account.AccountError.WithLabelValues(labels...)), 0)
+	assert.Equal(t, 0, testutil.CollectAndCount(&account.AccountETHReceived))
+
+	mockClient.ethReceipts["0x01"] = &api.ETHTransactionReceipt{Status: "0x1", GasUsed: "0xa", EffectiveGasPrice: "0x2"}
+
+	require.NoError(t, account.getFlows(context.Background(), mockClient))
+
+	assert.InDelta(t, 1000, testutil.ToFloat64(account.AccountETHReceived.WithLabelValues(labels...)), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(account.AccountGasSpent.WithLabelValues(labels...)), 0)
+}
+
+func TestAccount_getFlows_Disabled(t *testing.T) {
+	address := &AddressAccount{
+		Name:    testNameTestAccount,
+		Address: testHolder1Address,
+		Labels:  map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{}
+
+	account := NewAccount(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"account_flows_disabled",
+		map[string]string{},
+		[]*AddressAccount{address},
+		nil,
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, account.getFlows(context.Background(), mockClient))
+	assert.Empty(t, mockClient.ethGetBlockByNumberCalls)
+}
+
+func TestAccount_setSpendRate(t *testing.T) {
+	address := &AddressAccount{
+		Name:    testNameTestAccount,
+		Address: testHolder1Address,
+		SpendRate: &AccountSpendRate{
+			MinBalance: 12e18,
+		},
+		Labels: map[string]string{},
+	}
+
+	account := NewAccount(
+		mockClients(&mockExecutionClient{}),
+		testLogger(),
+		15*time.Second,
+		"account_spend_rate",
+		map[string]string{},
+		[]*AddressAccount{address},
+		nil,
+		nil,
+		testRegistry(),
+	)
+
+	labels := account.getLabelValues(address, testMockNodeName)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	account.setSpendRate(address, testMockNodeName, 10e18, start)
+
+	assertMetricValue(t, account.AccountBelowThreshold, labels, 1)
+	assert.Equal(t, 0, testutil.CollectAndCount(&account.AccountSpendRate))
+
+	// A top up within the window does not offset the spending.
+	account.setSpendRate(address, testMockNodeName, 9e18, start.Add(30*time.Minute))
+	account.setSpendRate(address, testMockNodeName, 13e18, start.Add(45*time.Minute))
+	account.setSpendRate(address, testMockNodeName, 12e18, start.Add(time.Hour))
+
+	assertMetricValue(t, account.AccountSpendRate, labels, 2e18)
+	assertMetricValue(t, account.AccountTimeToEmpty, labels, 6*time.Hour.Seconds())
+	assertMetricValue(t, account.AccountBelowThreshold, labels, 0)
+
+	// Samples older than the window are dropped.
+	account.setSpendRate(address, testMockNodeName, 12e18, start.Add(2*time.Hour))
+
+	assertMetricValue(t, account.AccountSpendRate, labels, 0)
+	assert.True(t, math.IsInf(testutil.ToFloat64(account.AccountTimeToEmpty.WithLabelValues(labels...)), 1))
+	assert.Len(t, account.spendSamples[accountStateKey{execution: testMockNodeName, name: address.Name}], 2)
+}
+
+func TestAddressAccount_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		address *AddressAccount
+		wantErr string
+	}{
+		{
+			name:    "without spend rate",
+			address: &AddressAccount{Name: "relayer"},
+		},
+		{
+			name:    "spend rate",
+			address: &AddressAccount{Name: "relayer", SpendRate: &AccountSpendRate{Window: time.Hour, MinBalance: 1e18}},
+		},
+		{
+			name:    "negative window",
+			address: &AddressAccount{Name: "relayer", SpendRate: &AccountSpendRate{Window: -time.Hour}},
+			wantErr: "spend rate window must not be negative",
+		},
+		{
+			name:    "default window",
+			address: &AddressAccount{Name: "relayer", SpendRate: &AccountSpendRate{}},
+		},
+		{
+			name:    "negative minimum balance",
+			address: &AddressAccount{Name: "relayer", SpendRate: &AccountSpendRate{MinBalance: -1}},
+			wantErr: "minBalance must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.address.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAccount_tick_Alerts(t *testing.T) {
+	notifications := make(chan map[string]any, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		notifications <- body
+	}))
+	t.Cleanup(server.Close)
+
+	minBalance := 2e18
+
+	address := &AddressAccount{
+		Name:    testNameTestAccount,
+		Address: testHolder1Address,
+		Alerts:  []*alerts.Rule{{Name: "low balance", Min: &minBalance}},
+		Labels:  map[string]string{},
+	}
+
+	alerting, err := alerts.NewManager(testLogger(), &alerts.Config{
+		Webhooks: []*alerts.Webhook{{Name: "ops", URL: server.URL}},
+	}, "account_alerts", map[string]string{}, testRegistry())
+	require.NoError(t, err)
+
+	mockClient := &mockExecutionClient{ethGetBalanceResponse: "0x0de0b6b3a7640000"}
+
+	account := NewAccount(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"account_alerts",
+		map[string]string{},
+		[]*AddressAccount{address},
+		alerting,
+		nil,
+		testRegistry(),
+	)
+
+	account.tick(context.Background())
+
+	require.Len(t, notifications, 1)
+
+	notification := <-notifications
+	assert.Equal(t, alerts.StatusFiring, notification["status"])
+	assert.Equal(t, NameAccount, notification["job"])
+	assert.Equal(t, testNameTestAccount, notification["name"])
+	assert.InDelta(t, 1e18, notification["value"], 0)
+}
@@ -8,10 +8,13 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // ChainlinkDataFeed exposes metrics for ethereum chainlink data feed contract.
 type ChainlinkDataFeed struct {
+	*tickTracker
+
 	clients                  []api.ExecutionClient
 	log                      logrus.FieldLogger
 	ChainlinkDataFeedBalance prometheus.GaugeVec
@@ -19,6 +22,7 @@ type ChainlinkDataFeed struct {
 	checkInterval            time.Duration
 	addresses                []*AddressChainlinkDataFeed
 	labelsMap                map[string]int
+	snapshots                *snapshot.Store
 }
 
 type AddressChainlinkDataFeed struct {
@@ -41,7 +45,7 @@ func (n *ChainlinkDataFeed) Name() string {
 }
 
 // NewChainlinkDataFeed returns a new ChainlinkDataFeed instance.
-func NewChainlinkDataFeed(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressChainlinkDataFeed) ChainlinkDataFeed {
+func NewChainlinkDataFeed(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressChainlinkDataFeed, snapshots *snapshot.Store, registerer prometheus.Registerer) ChainlinkDataFeed {
 	namespace += "_" + NameChainlinkDataFeed
 
 	labelsMap := map[string]int{
@@ -68,6 +72,8 @@ func NewChainlinkDataFeed(clients []api.ExecutionClient, log logrus.FieldLogger,
 	instance := ChainlinkDataFeed{
 		clients:       clients,
 		log:           log.WithField("module", NameChainlinkDataFeed),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
@@ -91,14 +97,15 @@ func NewChainlinkDataFeed(clients []api.ExecutionClient, log logrus.FieldLogger,
 		),
 	}
 
-	prometheus.MustRegister(instance.ChainlinkDataFeedBalance)
-	prometheus.MustRegister(instance.ChainlinkDataFeedError)
+	registerer.MustRegister(instance.ChainlinkDataFeedBalance)
+	registerer.MustRegister(instance.ChainlinkDataFeedError)
 
 	return instance
 }
 
 func (n *ChainlinkDataFeed) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -106,6 +113,7 @@ func (n *ChainlinkDataFeed) Start(ctx context.Context) {
 			return
 		case <-time.After(n.checkInterval):
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
@@ -157,6 +165,7 @@ func (n *ChainlinkDataFeed) getBalance(ctx context.Context, client api.Execution
 	defer func() {
 		if err != nil {
 			n.ChainlinkDataFeedError.WithLabelValues(n.getLabelValues(address, client.Name())...).Inc()
+			n.snapshots.SetError(NameChainlinkDataFeed, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
@@ -171,7 +180,15 @@ func (n *ChainlinkDataFeed) getBalance(ctx context.Context, client api.Execution
 		return err
 	}
 
-	n.ChainlinkDataFeedBalance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(hexStringToFloat64(balanceStr))
+	balance := hexStringToFloat64(balanceStr)
+	n.ChainlinkDataFeedBalance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(balance)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameChainlinkDataFeed,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Contract:  address.Contract,
+		Value:     balance,
+	}, time.Now())
 
 	return nil
 }
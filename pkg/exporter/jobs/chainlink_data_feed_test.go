@@ -72,6 +72,8 @@ func TestChainlinkDataFeed_getBalance(t *testing.T) {
 				namespace,
 				map[string]string{},
 				[]*AddressChainlinkDataFeed{tt.address},
+				nil,
+				testRegistry(),
 			)
 
 			err := chainlink.getBalance(context.Background(), mockClient, tt.address)
@@ -125,6 +127,8 @@ func TestChainlinkDataFeed_tick(t *testing.T) {
 		"test_chainlink_tick",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
@@ -160,6 +164,8 @@ func TestChainlinkDataFeed_getLabelValues(t *testing.T) {
 		"test_chainlink_labels",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	labels := chainlink.getLabelValues(addresses[0], "mock-node")
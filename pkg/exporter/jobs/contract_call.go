@@ -0,0 +1,398 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameContractCall = "contract_call"
+)
+
+var contractCallMetricNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ContractCall exposes metrics for arbitrary view functions described by their
+// ABI signature.
+type ContractCall struct {
+	*tickTracker
+
+	clients       []api.ExecutionClient
+	log           logrus.FieldLogger
+	checkInterval time.Duration
+	addresses     []*AddressContractCall
+	labelsMap     map[string]int
+	// calls holds the parsed signature and call data of each address.
+	calls map[*AddressContractCall]*contractCallABI
+
+	// ContractCallValues holds one gauge per configured output metric name.
+	ContractCallValues map[string]*prometheus.GaugeVec
+	ContractCallError  prometheus.CounterVec
+	snapshots          *snapshot.Store
+}
+
+type AddressContractCall struct {
+	Contract string `yaml:"contract"`
+	// Signature is the function signature including return types,
+	// e.g. getPoolData(address)(uint256,uint256,bool).
+	Signature string   `yaml:"signature"`
+	Args      []string `yaml:"args"`
+	// Outputs selects the return values to export.
+	Outputs []*ContractCallOutput `yaml:"outputs"`
+	Name    string                `yaml:"name"`
+	Labels  map[string]string     `yaml:"labels"`
+}
+
+// ContractCallOutput selects a single numeric return value of a contract call.
+type ContractCallOutput struct {
+	// Path is the index of the return value, with nested tuple members
+	// separated by dots, e.g. "1" or "0.2".
+	Path string `yaml:"path"`
+	// Metric is the gauge name the value is exported as.
+	Metric string `yaml:"metric"`
+	// Decimals divides the raw value by 10^decimals.
+	Decimals int `yaml:"decimals"`
+	// Scale multiplies the value after applying decimals. Defaults to 1.
+	Scale  float64           `yaml:"scale"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// contractCallABI is the parsed signature and encoded call data of an address.
+type contractCallABI struct {
+	function *abiFunction
+	callData string
+	err      error
+}
+
+func newContractCallABI(address *AddressContractCall) *contractCallABI {
+	function, err := parseABIFunction(address.Signature)
+	if err != nil {
+		return &contractCallABI{err: err}
+	}
+
+	callData, err := function.encodeCall(address.Args)
+
+	return &contractCallABI{function: function, callData: callData, err: err}
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressContractCall) GetName() string { return a.Name }
+
+// seriesKey identifies the series an output writes within its address, the
+// metric name and the labels the output sets.
+func (o *ContractCallOutput) seriesKey() string {
+	labels := make([]string, 0, len(o.Labels))
+
+	for label, value := range o.Labels {
+		if value != "" {
+			labels = append(labels, label+"="+strconv.Quote(value))
+		}
+	}
+
+	if len(labels) == 0 {
+		return o.Metric
+	}
+
+	slices.Sort(labels)
+
+	return o.Metric + "{" + strings.Join(labels, ",") + "}"
+}
+
+// Validate checks the signature, arguments and outputs are consistent.
+func (a *AddressContractCall) Validate() error {
+	function, err := parseABIFunction(a.Signature)
+	if err != nil {
+		return fmt.Errorf("contract call %s: %w", a.Name, err)
+	}
+
+	if _, err := function.encodeCall(a.Args); err != nil {
+		return fmt.Errorf("contract call %s: %w", a.Name, err)
+	}
+
+	if len(a.Outputs) == 0 {
+		return fmt.Errorf("contract call %s: at least one output must be configured", a.Name)
+	}
+
+	series := make(map[string]struct{}, len(a.Outputs))
+
+	for _, output := range a.Outputs {
+		if _, ok := series[output.seriesKey()]; ok {
+			return fmt.Errorf("contract call %s: outputs must not share metric %q without different labels", a.Name, output.Metric)
+		}
+
+		series[output.seriesKey()] = struct{}{}
+
+		if !contractCallMetricNameRegexp.MatchString(output.Metric) || output.Metric == metricNameErrorsTotal {
+			return fmt.Errorf("contract call %s: invalid metric name %q", a.Name, output.Metric)
+		}
+
+		if output.Decimals < 0 || output.Decimals > tokenMaxSupportedDecimals {
+			return fmt.Errorf("contract call %s: invalid decimals %d", a.Name, output.Decimals)
+		}
+
+		path, err := parseContractCallPath(output.Path)
+		if err != nil {
+			return fmt.Errorf("contract call %s: %w", a.Name, err)
+		}
+
+		valueType, err := function.outputType(path)
+		if err != nil {
+			return fmt.Errorf("contract call %s: %w", a.Name, err)
+		}
+
+		if !valueType.isNumeric() {
+			return fmt.Errorf("contract call %s: output %q has non numeric type %s", a.Name, output.Path, valueType.canonical())
+		}
+	}
+
+	return nil
+}
+
+func (n *ContractCall) Name() string {
+	return NameContractCall
+}
+
+// NewContractCall returns a new ContractCall instance.
+func NewContractCall(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressContractCall, snapshots *snapshot.Store, registerer prometheus.Registerer) ContractCall {
+	namespace += "_" + NameContractCall
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelContract:  1,
+		LabelExecution: 2,
+	}
+
+	addLabels := func(labels map[string]string) {
+		for label := range labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	for _, address := range addresses {
+		addLabels(address.Labels)
+
+		for _, output := range address.Outputs {
+			addLabels(output.Labels)
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	instance := ContractCall{
+		clients:            clients,
+		log:                log.WithField("module", NameContractCall),
+		tickTracker:        newTickTracker(),
+		snapshots:          snapshots,
+		addresses:          addresses,
+		checkInterval:      checkInterval,
+		labelsMap:          labelsMap,
+		calls:              make(map[*AddressContractCall]*contractCallABI, len(addresses)),
+		ContractCallValues: make(map[string]*prometheus.GaugeVec),
+		ContractCallError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when calling a ethereum contract view function.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	for _, address := range addresses {
+		instance.calls[address] = newContractCallABI(address)
+
+		for _, output := range address.Outputs {
+			if _, ok := instance.ContractCallValues[output.Metric]; ok {
+				continue
+			}
+
+			gauge := prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace:   namespace,
+					Name:        output.Metric,
+					Help:        "The scaled return value of a ethereum contract view function.",
+					ConstLabels: constLabels,
+				},
+				labels,
+			)
+
+			instance.ContractCallValues[output.Metric] = gauge
+
+			registerer.MustRegister(gauge)
+		}
+	}
+
+	registerer.MustRegister(instance.ContractCallError)
+
+	return instance
+}
+
+func (n *ContractCall) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *ContractCall) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		for _, address := range n.addresses {
+			err := n.getValues(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to call contract")
+			}
+		}
+	}
+}
+
+func (n *ContractCall) getLabelValues(address *AddressContractCall, output *ContractCallOutput, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		switch {
+		case output != nil && output.Labels[label] != "":
+			values[index] = output.Labels[label]
+		case address.Labels != nil && address.Labels[label] != "":
+			values[index] = address.Labels[label]
+		default:
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+func (n *ContractCall) getValues(ctx context.Context, client api.ExecutionClient, address *AddressContractCall) error {
+	var err error
+
+	defer func() {
+		if err != nil {
+			n.ContractCallError.WithLabelValues(n.getLabelValues(address, nil, client.Name())...).Inc()
+			n.snapshots.SetError(NameContractCall, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	call, ok := n.calls[address]
+	if !ok {
+		call = newContractCallABI(address)
+	}
+
+	if err = call.err; err != nil {
+		return err
+	}
+
+	function := call.function
+
+	data, err := ethCallABI(ctx, client, address.Contract, call.callData)
+	if err != nil {
+		return err
+	}
+
+	values := make([]float64, len(address.Outputs))
+
+	for i, output := range address.Outputs {
+		values[i], err = decodeContractCallOutput(function, data, output)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, output := range address.Outputs {
+		gauge, ok := n.ContractCallValues[output.Metric]
+		if !ok {
+			err = fmt.Errorf("metric %s is not registered", output.Metric)
+
+			return err
+		}
+
+		gauge.WithLabelValues(n.getLabelValues(address, output, client.Name())...).Set(values[i])
+		n.snapshots.Set(&snapshot.Update{
+			Job:       NameContractCall,
+			Name:      address.Name,
+			Execution: client.Name(),
+			Contract:  address.Contract,
+			Key:       output.seriesKey(),
+			Value:     values[i],
+		}, time.Now())
+	}
+
+	return nil
+}
+
+func decodeContractCallOutput(function *abiFunction, data []byte, output *ContractCallOutput) (float64, error) {
+	path, err := parseContractCallPath(output.Path)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := function.decodeNumericOutput(data, path)
+	if err != nil {
+		return 0, err
+	}
+
+	value := tokenAmountToFloat64(raw, output.Decimals)
+
+	if output.Scale != 0 {
+		value *= output.Scale
+	}
+
+	return value, nil
+}
+
+// parseContractCallPath parses a dot separated return value path, e.g. "0.2".
+// An empty path selects the first return value.
+func parseContractCallPath(path string) ([]int, error) {
+	if path == "" {
+		return []int{0}, nil
+	}
+
+	parts := strings.Split(path, ".")
+	indexes := make([]int, len(parts))
+
+	for i, part := range parts {
+		index, err := strconv.Atoi(part)
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("invalid return value path %q", path)
+		}
+
+		indexes[i] = index
+	}
+
+	return indexes, nil
+}
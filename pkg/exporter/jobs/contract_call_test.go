@@ -0,0 +1,235 @@
+package jobs
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContractCallSignature = "getPoolData(address)(uint256,uint256,bool)"
+
+func TestContractCall_getValues(t *testing.T) {
+	address := &AddressContractCall{
+		Name:      "Pool",
+		Contract:  testContractAAddress,
+		Signature: testContractCallSignature,
+		Args:      []string{testContractBAddress},
+		Outputs: []*ContractCallOutput{
+			{Path: "0", Metric: "pool_reserve", Decimals: 18, Labels: map[string]string{"side": "base"}},
+			{Path: "1", Metric: "pool_reserve", Decimals: 6, Labels: map[string]string{"side": "quote"}},
+			{Path: "2", Metric: "pool_paused"},
+			{Path: "1", Metric: "pool_quote_scaled", Decimals: 6, Scale: 0.5},
+		},
+		Labels: map[string]string{"pool": "main"},
+	}
+
+	function, err := parseABIFunction(testContractCallSignature)
+	require.NoError(t, err)
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			function.selector: encodeABIWordsReturn(
+				new(big.Int).Mul(big.NewInt(3), big.NewInt(1e18)),
+				big.NewInt(2500000),
+				big.NewInt(1),
+			),
+		},
+	}
+
+	contractCall := NewContractCall(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"contract_call_get",
+		map[string]string{},
+		[]*AddressContractCall{address},
+		nil,
+		testRegistry(),
+	)
+
+	err = contractCall.getValues(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	require.Len(t, contractCall.ContractCallValues, 3)
+
+	baseLabels := contractCall.getLabelValues(address, address.Outputs[0], testMockNodeName)
+	quoteLabels := contractCall.getLabelValues(address, address.Outputs[1], testMockNodeName)
+	defaultLabels := contractCall.getLabelValues(address, address.Outputs[2], testMockNodeName)
+
+	assertMetricValue(t, *contractCall.ContractCallValues["pool_reserve"], baseLabels, 3)
+	assertMetricValue(t, *contractCall.ContractCallValues["pool_reserve"], quoteLabels, 2.5)
+	assertMetricValue(t, *contractCall.ContractCallValues["pool_paused"], defaultLabels, 1)
+	assertMetricValue(t, *contractCall.ContractCallValues["pool_quote_scaled"], defaultLabels, 1.25)
+
+	require.Len(t, mockClient.callLog, 1)
+
+	encodedArg, err := encodeAddressArgument(testContractBAddress)
+	require.NoError(t, err)
+	assert.Equal(t, function.selector+encodedArg, mockClient.callLog[0].data)
+}
+
+func TestContractCall_getValues_Error(t *testing.T) {
+	address := &AddressContractCall{
+		Name:      "Broken",
+		Contract:  testContractAAddress,
+		Signature: "getValue()(uint256)",
+		Outputs:   []*ContractCallOutput{{Metric: "value"}},
+	}
+
+	// The default mock response is shorter than an ABI word.
+	mockClient := &mockExecutionClient{}
+
+	contractCall := NewContractCall(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"contract_call_error",
+		map[string]string{},
+		[]*AddressContractCall{address},
+		nil,
+		testRegistry(),
+	)
+
+	err := contractCall.getValues(context.Background(), mockClient, address)
+	require.Error(t, err)
+
+	labels := contractCall.getLabelValues(address, nil, testMockNodeName)
+	assert.InDelta(t, 1, testutil.ToFloat64(contractCall.ContractCallError.WithLabelValues(labels...)), 0)
+}
+
+func TestContractCall_getLabelValues(t *testing.T) {
+	address := &AddressContractCall{
+		Name:     "Pool",
+		Contract: testContractAAddress,
+		Outputs: []*ContractCallOutput{
+			{Metric: "value", Labels: map[string]string{"side": "base", "pool": "override"}},
+		},
+		Labels: map[string]string{"pool": "main"},
+	}
+
+	contractCall := NewContractCall(
+		mockClients(&mockExecutionClient{}),
+		testLogger(),
+		15*time.Second,
+		"contract_call_labels",
+		map[string]string{},
+		[]*AddressContractCall{address},
+		nil,
+		testRegistry(),
+	)
+
+	labels := contractCall.getLabelValues(address, address.Outputs[0], testMockNodeName)
+	assertLabelContains(t, labels, "Pool")
+	assertLabelContains(t, labels, testContractAAddress)
+	assertLabelContains(t, labels, testMockNodeName)
+	assertLabelContains(t, labels, "base")
+	assertLabelContains(t, labels, "override")
+
+	labels = contractCall.getLabelValues(address, nil, testMockNodeName)
+	assertLabelContains(t, labels, "main")
+	assertLabelContains(t, labels, LabelDefaultValue)
+}
+
+func TestAddressContractCall_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		address *AddressContractCall
+		wantErr string
+	}{
+		{
+			name: "valid",
+			address: &AddressContractCall{
+				Name: "call", Signature: testContractCallSignature, Args: []string{testContractBAddress},
+				Outputs: []*ContractCallOutput{{Path: "2", Metric: "paused"}},
+			},
+		},
+		{
+			name:    "invalid signature",
+			address: &AddressContractCall{Name: "call", Signature: "getPoolData(address"},
+			wantErr: "invalid function signature",
+		},
+		{
+			name:    "wrong argument count",
+			address: &AddressContractCall{Name: "call", Signature: testContractCallSignature},
+			wantErr: "expects 1 arguments",
+		},
+		{
+			name: "no outputs",
+			address: &AddressContractCall{
+				Name: "call", Signature: testContractCallSignature, Args: []string{testContractBAddress},
+			},
+			wantErr: "at least one output",
+		},
+		{
+			name: "reserved metric name",
+			address: &AddressContractCall{
+				Name: "call", Signature: testContractCallSignature, Args: []string{testContractBAddress},
+				Outputs: []*ContractCallOutput{{Metric: metricNameErrorsTotal}},
+			},
+			wantErr: "invalid metric name",
+		},
+		{
+			name: "path out of range",
+			address: &AddressContractCall{
+				Name: "call", Signature: testContractCallSignature, Args: []string{testContractBAddress},
+				Outputs: []*ContractCallOutput{{Path: "3", Metric: "value"}},
+			},
+			wantErr: "out of range",
+		},
+		{
+			name: "duplicate metric",
+			address: &AddressContractCall{
+				Name: "call", Signature: testContractCallSignature, Args: []string{testContractBAddress},
+				Outputs: []*ContractCallOutput{{Path: "0", Metric: "value"}, {Path: "1", Metric: "value"}},
+			},
+			wantErr: "must not share metric",
+		},
+		{
+			name: "same metric with different labels",
+			address: &AddressContractCall{
+				Name: "call", Signature: testContractCallSignature, Args: []string{testContractBAddress},
+				Outputs: []*ContractCallOutput{
+					{Path: "0", Metric: "value", Labels: map[string]string{"side": "a"}},
+					{Path: "1", Metric: "value", Labels: map[string]string{"side": "b"}},
+				},
+			},
+		},
+		{
+			name: "non numeric output",
+			address: &AddressContractCall{
+				Name: "call", Signature: "owner()(address)",
+				Outputs: []*ContractCallOutput{{Metric: "owner"}},
+			},
+			wantErr: "non numeric type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.address.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestContractCall_Name(t *testing.T) {
+	contractCall := &ContractCall{}
+	if contractCall.Name() != NameContractCall {
+		t.Errorf("Expected name %s, got %s", NameContractCall, contractCall.Name())
+	}
+}
@@ -4,38 +4,104 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // ERC1155 exposes metrics for ethereum ERC115 contract by address and token id.
 type ERC1155 struct {
+	*tickTracker
+
 	clients        []api.ExecutionClient
 	log            logrus.FieldLogger
 	ERC1155Balance prometheus.GaugeVec
+	ERC1155URI     prometheus.GaugeVec
 	ERC1155Error   prometheus.CounterVec
 	checkInterval  time.Duration
 	addresses      []*AddressERC1155
 	labelsMap      map[string]int
+	// uris caches the resolved uri(id) of each token per execution client.
+	uris      map[erc1155URIKey]string
+	snapshots *snapshot.Store
 }
 
 type AddressERC1155 struct {
-	Address  string            `yaml:"address"`
-	Contract string            `yaml:"contract"`
-	TokenID  big.Int           `yaml:"tokenId"`
-	Name     string            `yaml:"name"`
-	Labels   map[string]string `yaml:"labels"`
+	Address       string                 `yaml:"address"`
+	Contract      string                 `yaml:"contract"`
+	TokenID       big.Int                `yaml:"tokenId"`
+	TokenIDs      []*big.Int             `yaml:"tokenIds"`
+	TokenIDRanges []*ERC1155TokenIDRange `yaml:"tokenIdRanges"`
+	URI           bool                   `yaml:"uri"`
+	Name          string                 `yaml:"name"`
+	Labels        map[string]string      `yaml:"labels"`
+}
+
+// ERC1155TokenIDRange is an inclusive range of token IDs.
+type ERC1155TokenIDRange struct {
+	From *big.Int `yaml:"from"`
+	To   *big.Int `yaml:"to"`
+}
+
+type erc1155URIKey struct {
+	execution string
+	contract  string
+	tokenID   string
 }
 
 // GetName returns the configured name of this address.
 func (a *AddressERC1155) GetName() string { return a.Name }
 
+// Validate checks the configured token ID ranges.
+func (a *AddressERC1155) Validate() error {
+	total := big.NewInt(int64(len(a.TokenIDs)))
+
+	for _, r := range a.TokenIDRanges {
+		if r.From == nil || r.To == nil || r.From.Sign() < 0 || r.From.Cmp(r.To) > 0 {
+			return fmt.Errorf("erc1155 %s: token id ranges need a from lower than or equal to to", a.Name)
+		}
+
+		total.Add(total, new(big.Int).Sub(r.To, r.From))
+		total.Add(total, big.NewInt(1))
+	}
+
+	if total.Cmp(big.NewInt(erc1155MaxTokenIDs)) > 0 {
+		return fmt.Errorf("erc1155 %s: at most %d token ids can be tracked per entry", a.Name, erc1155MaxTokenIDs)
+	}
+
+	return nil
+}
+
+// tokenIDs returns the configured token IDs, falling back to the single
+// tokenId when no list or range is set.
+func (a *AddressERC1155) tokenIDs() []*big.Int {
+	if len(a.TokenIDs) == 0 && len(a.TokenIDRanges) == 0 {
+		return []*big.Int{&a.TokenID}
+	}
+
+	tokenIDs := append([]*big.Int{}, a.TokenIDs...)
+
+	for _, r := range a.TokenIDRanges {
+		for id := new(big.Int).Set(r.From); id.Cmp(r.To) <= 0; id = new(big.Int).Add(id, big.NewInt(1)) {
+			tokenIDs = append(tokenIDs, id)
+		}
+	}
+
+	return tokenIDs
+}
+
 const (
 	NameERC1155 = "erc1155"
+
+	erc1155BalanceOfBatchSelector = "0x4e1273f4"
+	erc1155URISelector            = "0x0e89341c"
+	// erc1155MaxTokenIDs bounds the token IDs fetched in a single balanceOfBatch call.
+	erc1155MaxTokenIDs = 1000
 )
 
 func (n *ERC1155) Name() string {
@@ -43,7 +109,7 @@ func (n *ERC1155) Name() string {
 }
 
 // NewERC1155 returns a new ERC1155 instance.
-func NewERC1155(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC1155) ERC1155 {
+func NewERC1155(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC1155, snapshots *snapshot.Store, registerer prometheus.Registerer) ERC1155 {
 	namespace += "_" + NameERC1155
 
 	labelsMap := map[string]int{
@@ -70,9 +136,12 @@ func NewERC1155(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 	instance := ERC1155{
 		clients:       clients,
 		log:           log.WithField("module", NameERC1155),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
+		uris:          make(map[erc1155URIKey]string),
 		ERC1155Balance: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace:   namespace,
@@ -82,6 +151,15 @@ func NewERC1155(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 			},
 			labels,
 		),
+		ERC1155URI: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "uri_info",
+				Help:        "The metadata uri of a ethereum ERC115 contract token id.",
+				ConstLabels: constLabels,
+			},
+			append(append([]string{}, labels...), LabelURI),
+		),
 		ERC1155Error: *prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace:   namespace,
@@ -93,14 +171,16 @@ func NewERC1155(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 		),
 	}
 
-	prometheus.MustRegister(instance.ERC1155Balance)
-	prometheus.MustRegister(instance.ERC1155Error)
+	registerer.MustRegister(instance.ERC1155Balance)
+	registerer.MustRegister(instance.ERC1155URI)
+	registerer.MustRegister(instance.ERC1155Error)
 
 	return instance
 }
 
 func (n *ERC1155) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -108,6 +188,7 @@ func (n *ERC1155) Start(ctx context.Context) {
 			return
 		case <-time.After(n.checkInterval):
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
@@ -122,11 +203,21 @@ func (n *ERC1155) tick(ctx context.Context) {
 					LabelExecution: client.Name(),
 				}).Error("Failed to get erc1155 contract balanceOf address")
 			}
+
+			if address.URI {
+				err = n.getURIs(ctx, client, address)
+				if err != nil {
+					n.log.WithError(err).WithFields(logrus.Fields{
+						LabelAddress:   address,
+						LabelExecution: client.Name(),
+					}).Error("Failed to get erc1155 contract uri")
+				}
+			}
 		}
 	}
 }
 
-func (n *ERC1155) getLabelValues(address *AddressERC1155, executionName string) []string {
+func (n *ERC1155) getLabelValues(address *AddressERC1155, tokenID *big.Int, executionName string) []string {
 	values := make([]string, len(n.labelsMap))
 
 	for label, index := range n.labelsMap {
@@ -141,7 +232,7 @@ func (n *ERC1155) getLabelValues(address *AddressERC1155, executionName string)
 			case LabelContract:
 				values[index] = address.Contract
 			case LabelTokenID:
-				values[index] = address.TokenID.String()
+				values[index] = tokenID.String()
 			case LabelExecution:
 				values[index] = executionName
 			default:
@@ -154,16 +245,22 @@ func (n *ERC1155) getLabelValues(address *AddressERC1155, executionName string)
 }
 
 func (n *ERC1155) getBalance(ctx context.Context, client api.ExecutionClient, address *AddressERC1155) error {
+	tokenIDs := address.tokenIDs()
+	if len(tokenIDs) > 1 {
+		return n.getBalanceBatch(ctx, client, address, tokenIDs)
+	}
+
 	var err error
 
 	defer func() {
 		if err != nil {
-			n.ERC1155Error.WithLabelValues(n.getLabelValues(address, client.Name())...).Inc()
+			n.ERC1155Error.WithLabelValues(n.getLabelValues(address, tokenIDs[0], client.Name())...).Inc()
+			n.snapshots.SetError(NameERC1155, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
 	// call balanceOf(address,uint256) which is 0x00fdd58e
-	balanceOfData := "0x00fdd58e000000000000000000000000" + address.Address[2:] + fmt.Sprintf("%064x", &address.TokenID)
+	balanceOfData := "0x00fdd58e000000000000000000000000" + address.Address[2:] + fmt.Sprintf("%064x", tokenIDs[0])
 
 	balanceStr, err := client.ETHCall(ctx, &api.ETHCallTransaction{
 		To:   address.Contract,
@@ -173,7 +270,148 @@ func (n *ERC1155) getBalance(ctx context.Context, client api.ExecutionClient, ad
 		return err
 	}
 
-	n.ERC1155Balance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(hexStringToFloat64(balanceStr))
+	balance := hexStringToFloat64(balanceStr)
+	n.ERC1155Balance.WithLabelValues(n.getLabelValues(address, tokenIDs[0], client.Name())...).Set(balance)
+	n.setSnapshot(client, address, tokenIDs[0], balance)
+
+	return nil
+}
+
+// setSnapshot records the balance of a token ID of the address.
+func (n *ERC1155) setSnapshot(client api.ExecutionClient, address *AddressERC1155, tokenID *big.Int, balance float64) {
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameERC1155,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Key:       tokenID.String(),
+		Value:     balance,
+	}, time.Now())
+}
+
+// getBalanceBatch fetches the balances of several token IDs with a single
+// balanceOfBatch call.
+func (n *ERC1155) getBalanceBatch(ctx context.Context, client api.ExecutionClient, address *AddressERC1155, tokenIDs []*big.Int) error {
+	var err error
+
+	defer func() {
+		if err != nil {
+			for _, tokenID := range tokenIDs {
+				n.ERC1155Error.WithLabelValues(n.getLabelValues(address, tokenID, client.Name())...).Inc()
+			}
+
+			n.snapshots.SetError(NameERC1155, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	data, err := encodeERC1155BalanceOfBatchCall(address.Address, tokenIDs)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   address.Contract,
+		Data: &data,
+	}, "latest")
+	if err != nil {
+		return err
+	}
+
+	balances, err := decodeABIUint256Array(rsp)
+	if err != nil {
+		return fmt.Errorf("failed to decode balanceOfBatch: %w", err)
+	}
+
+	if len(balances) != len(tokenIDs) {
+		err = fmt.Errorf("balanceOfBatch returned %d balances for %d token ids", len(balances), len(tokenIDs))
+
+		return err
+	}
+
+	for i, tokenID := range tokenIDs {
+		balance := bigIntToFloat64(balances[i])
+		n.ERC1155Balance.WithLabelValues(n.getLabelValues(address, tokenID, client.Name())...).Set(balance)
+		n.setSnapshot(client, address, tokenID, balance)
+	}
 
 	return nil
 }
+
+// getURIs exports the uri(id) of every token ID, substituting the {id}
+// placeholder as described in EIP-1155. URIs are fetched once per execution
+// client and cached.
+func (n *ERC1155) getURIs(ctx context.Context, client api.ExecutionClient, address *AddressERC1155) error {
+	for _, tokenID := range address.tokenIDs() {
+		key := erc1155URIKey{execution: client.Name(), contract: address.Contract, tokenID: tokenID.String()}
+		labels := n.getLabelValues(address, tokenID, client.Name())
+
+		uri, ok := n.uris[key]
+		if !ok {
+			var err error
+
+			uri, err = n.getURI(ctx, client, address.Contract, tokenID)
+			if err != nil {
+				n.ERC1155Error.WithLabelValues(labels...).Inc()
+				n.snapshots.SetError(NameERC1155, address.Name, client.Name(), err, time.Now())
+
+				return fmt.Errorf("failed to get uri of token %s: %w", tokenID, err)
+			}
+
+			n.uris[key] = uri
+		}
+
+		n.ERC1155URI.WithLabelValues(append(append([]string{}, labels...), uri)...).Set(1)
+	}
+
+	return nil
+}
+
+func (n *ERC1155) getURI(ctx context.Context, client api.ExecutionClient, contract string, tokenID *big.Int) (string, error) {
+	data := erc1155URISelector + formatABIWord(tokenID)
+
+	rsp, err := client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   contract,
+		Data: &data,
+	}, "latest")
+	if err != nil {
+		return "", err
+	}
+
+	uri, err := hexStringToString(rsp)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(uri, "{id}", formatABIWord(tokenID)), nil
+}
+
+// encodeERC1155BalanceOfBatchCall encodes balanceOfBatch(address[],uint256[])
+// for a single account repeated for every token ID.
+func encodeERC1155BalanceOfBatchCall(account string, tokenIDs []*big.Int) (string, error) {
+	encodedAccount, err := encodeAddressArgument(account)
+	if err != nil {
+		return "", err
+	}
+
+	count := int64(len(tokenIDs))
+
+	var data strings.Builder
+
+	data.WriteString(erc1155BalanceOfBatchSelector)
+	data.WriteString(formatABIWord(big.NewInt(64)))
+	data.WriteString(formatABIWord(big.NewInt(64 + 32 + 32*count)))
+	data.WriteString(formatABIWord(big.NewInt(count)))
+
+	for range tokenIDs {
+		data.WriteString(encodedAccount)
+	}
+
+	data.WriteString(formatABIWord(big.NewInt(count)))
+
+	for _, tokenID := range tokenIDs {
+		data.WriteString(formatABIWord(tokenID))
+	}
+
+	return data.String(), nil
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestERC1155_getBalance(t *testing.T) {
@@ -75,6 +77,8 @@ func TestERC1155_getBalance(t *testing.T) {
 				namespace,
 				map[string]string{},
 				[]*AddressERC1155{tt.address},
+				nil,
+				testRegistry(),
 			)
 
 			err := erc1155.getBalance(context.Background(), mockClient, tt.address)
@@ -130,6 +134,8 @@ func TestERC1155_tick(t *testing.T) {
 		"test_erc1155_tick",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
@@ -167,9 +173,11 @@ func TestERC1155_getLabelValues(t *testing.T) {
 		"test_erc1155_labels",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
-	labels := erc1155.getLabelValues(addresses[0], "mock-node")
+	labels := erc1155.getLabelValues(addresses[0], tokenID, "mock-node")
 
 	if len(labels) != len(erc1155.labelsMap) {
 		t.Errorf("Expected %d label values, got %d", len(erc1155.labelsMap), len(labels))
@@ -188,6 +196,128 @@ func TestERC1155_getLabelValues(t *testing.T) {
 	}
 }
 
+func TestERC1155_getBalanceBatch(t *testing.T) {
+	address := &AddressERC1155{
+		Name:     "Game Items",
+		Address:  testHolder1Address,
+		Contract: testERC1155Contract,
+		TokenIDs: []*big.Int{big.NewInt(1)},
+		TokenIDRanges: []*ERC1155TokenIDRange{
+			{From: big.NewInt(10), To: big.NewInt(11)},
+		},
+		Labels: map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			erc1155BalanceOfBatchSelector: encodeABIUintArrayReturn(3, 0, 7),
+		},
+	}
+
+	erc1155 := NewERC1155(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc1155_batch",
+		map[string]string{},
+		[]*AddressERC1155{address},
+		nil,
+		testRegistry(),
+	)
+
+	err := erc1155.getBalance(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	assertCallSelectors(t, mockClient.callLog, []string{erc1155BalanceOfBatchSelector})
+
+	expected, err := encodeERC1155BalanceOfBatchCall(testHolder1Address, []*big.Int{big.NewInt(1), big.NewInt(10), big.NewInt(11)})
+	require.NoError(t, err)
+	assert.Equal(t, expected, mockClient.callLog[0].data)
+
+	for tokenID, balance := range map[int64]float64{1: 3, 10: 0, 11: 7} {
+		assertMetricValue(t, erc1155.ERC1155Balance, erc1155.getLabelValues(address, big.NewInt(tokenID), testMockNodeName), balance)
+	}
+}
+
+func TestERC1155_getURIs(t *testing.T) {
+	address := &AddressERC1155{
+		Name:     "Game Items",
+		Address:  testHolder1Address,
+		Contract: testERC1155Contract,
+		TokenIDs: []*big.Int{big.NewInt(1), big.NewInt(2)},
+		URI:      true,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			erc1155URISelector: encodeABIStringReturn("https://example.com/{id}.json"),
+		},
+	}
+
+	erc1155 := NewERC1155(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc1155_uri",
+		map[string]string{},
+		[]*AddressERC1155{address},
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, erc1155.getURIs(context.Background(), mockClient, address))
+	require.NoError(t, erc1155.getURIs(context.Background(), mockClient, address))
+
+	labels := append(erc1155.getLabelValues(address, big.NewInt(2), testMockNodeName), "https://example.com/"+formatABIWord(big.NewInt(2))+".json")
+	assertMetricValue(t, erc1155.ERC1155URI, labels, 1)
+	assertCallSelectors(t, mockClient.callLog, []string{erc1155URISelector, erc1155URISelector})
+}
+
+func TestAddressERC1155_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		address *AddressERC1155
+		wantErr string
+	}{
+		{
+			name:    "single token id",
+			address: &AddressERC1155{Name: "items"},
+		},
+		{
+			name:    "valid range",
+			address: &AddressERC1155{Name: "items", TokenIDRanges: []*ERC1155TokenIDRange{{From: big.NewInt(1), To: big.NewInt(40)}}},
+		},
+		{
+			name:    "inverted range",
+			address: &AddressERC1155{Name: "items", TokenIDRanges: []*ERC1155TokenIDRange{{From: big.NewInt(40), To: big.NewInt(1)}}},
+			wantErr: "token id ranges",
+		},
+		{
+			name:    "too many token ids",
+			address: &AddressERC1155{Name: "items", TokenIDRanges: []*ERC1155TokenIDRange{{From: big.NewInt(0), To: big.NewInt(erc1155MaxTokenIDs)}}},
+			wantErr: "at most",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.address.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestERC1155_Name(t *testing.T) {
 	erc1155 := &ERC1155{}
 	if erc1155.Name() != NameERC1155 {
@@ -2,30 +2,48 @@ package jobs
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
-// ERC20 exposes metrics for ethereum ERC20 contract by address.
+// ERC20 exposes metrics for ethereum ERC20 contract by address, and supply
+// metrics for contract-level entries without an address.
 type ERC20 struct {
-	clients       []api.ExecutionClient
-	log           logrus.FieldLogger
-	ERC20Balance  prometheus.GaugeVec
-	ERC20Error    prometheus.CounterVec
-	checkInterval time.Duration
-	addresses     []*AddressERC20
-	labelsMap     map[string]int
+	*tickTracker
+
+	clients            []api.ExecutionClient
+	log                logrus.FieldLogger
+	ERC20Balance       prometheus.GaugeVec
+	ERC20ShareOfSupply prometheus.GaugeVec
+	ERC20TotalSupply   prometheus.GaugeVec
+	ERC20Cap           prometheus.GaugeVec
+	ERC20Error         prometheus.CounterVec
+	checkInterval      time.Duration
+	addresses          []*AddressERC20
+	labelsMap          map[string]int
+	tokens             *TokenMetadataCache
+	alerts             *alerts.Manager
+	snapshots          *snapshot.Store
 }
 
 type AddressERC20 struct {
-	Address  string            `yaml:"address"`
-	Contract string            `yaml:"contract"`
-	Name     string            `yaml:"name"`
-	Labels   map[string]string `yaml:"labels"`
+	// Address is the token holder. When empty, contract-level supply metrics are exported instead of a balance.
+	Address  string `yaml:"address"`
+	Contract string `yaml:"contract"`
+	// Cap enables the cap() call for contract-level entries of capped tokens.
+	Cap bool `yaml:"cap"`
+	// Alerts are evaluated on the balance of the address, or on the total
+	// supply of contract-level entries.
+	Alerts []*alerts.Rule    `yaml:"alerts"`
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels"`
 }
 
 // GetName returns the configured name of this address.
@@ -33,6 +51,9 @@ func (a *AddressERC20) GetName() string { return a.Name }
 
 const (
 	NameERC20 = "erc20"
+
+	erc20TotalSupplySelector = "0x18160ddd"
+	erc20CapSelector         = "0x355274ea"
 )
 
 func (n *ERC20) Name() string {
@@ -40,7 +61,7 @@ func (n *ERC20) Name() string {
 }
 
 // NewERC20 returns a new ERC20 instance.
-func NewERC20(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC20) ERC20 {
+func NewERC20(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC20, tokens *TokenMetadataCache, alerting *alerts.Manager, snapshots *snapshot.Store, registerer prometheus.Registerer) ERC20 {
 	namespace += "_" + NameERC20
 
 	labelsMap := map[string]int{
@@ -67,9 +88,13 @@ func NewERC20(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterv
 	instance := ERC20{
 		clients:       clients,
 		log:           log.WithField("module", NameERC20),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
+		tokens:        tokens,
+		alerts:        alerting,
 		ERC20Balance: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace:   namespace,
@@ -79,6 +104,33 @@ func NewERC20(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterv
 			},
 			labels,
 		),
+		ERC20ShareOfSupply: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "share_of_supply",
+				Help:        "The ratio of a ethereum ERC20 contract total supply held by address.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		ERC20TotalSupply: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "total_supply",
+				Help:        "The total supply of a ethereum ERC20 contract.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		ERC20Cap: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "cap",
+				Help:        "The supply cap of a ethereum ERC20 contract.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
 		ERC20Error: *prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace:   namespace,
@@ -90,14 +142,18 @@ func NewERC20(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterv
 		),
 	}
 
-	prometheus.MustRegister(instance.ERC20Balance)
-	prometheus.MustRegister(instance.ERC20Error)
+	registerer.MustRegister(instance.ERC20Balance)
+	registerer.MustRegister(instance.ERC20ShareOfSupply)
+	registerer.MustRegister(instance.ERC20TotalSupply)
+	registerer.MustRegister(instance.ERC20Cap)
+	registerer.MustRegister(instance.ERC20Error)
 
 	return instance
 }
 
 func (n *ERC20) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -105,14 +161,28 @@ func (n *ERC20) Start(ctx context.Context) {
 			return
 		case <-time.After(n.checkInterval):
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
 
 func (n *ERC20) tick(ctx context.Context) {
 	for _, client := range n.clients {
+		supplies := erc20Supplies{}
+
 		for _, address := range n.addresses {
-			err := n.getBalance(ctx, client, address)
+			if address.Address == "" {
+				if err := n.getSupply(ctx, client, address, supplies); err != nil {
+					n.log.WithError(err).WithFields(logrus.Fields{
+						LabelContract:  address.Contract,
+						LabelExecution: client.Name(),
+					}).Error("Failed to get erc20 contract supply")
+				}
+
+				continue
+			}
+
+			err := n.getBalance(ctx, client, address, supplies)
 			if err != nil {
 				n.log.WithError(err).WithFields(logrus.Fields{
 					LabelAddress:   address,
@@ -121,6 +191,8 @@ func (n *ERC20) tick(ctx context.Context) {
 			}
 		}
 	}
+
+	n.alerts.Evaluate(ctx, NameERC20, time.Now())
 }
 
 func (n *ERC20) getLabelValues(address *AddressERC20, symbol, executionName string) []string {
@@ -150,7 +222,10 @@ func (n *ERC20) getLabelValues(address *AddressERC20, symbol, executionName stri
 	return values
 }
 
-func (n *ERC20) getBalance(ctx context.Context, client api.ExecutionClient, address *AddressERC20) error {
+// getBalance exports the balance of the address and its share of the total
+// supply. A failing totalSupply call only removes the share of supply, it is
+// logged but not counted as an error of the address.
+func (n *ERC20) getBalance(ctx context.Context, client api.ExecutionClient, address *AddressERC20, supplies erc20Supplies) error {
 	var err error
 
 	symbol := ""
@@ -158,6 +233,7 @@ func (n *ERC20) getBalance(ctx context.Context, client api.ExecutionClient, addr
 	defer func() {
 		if err != nil {
 			n.ERC20Error.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Inc()
+			n.snapshots.SetError(NameERC20, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
@@ -172,23 +248,129 @@ func (n *ERC20) getBalance(ctx context.Context, client api.ExecutionClient, addr
 		return err
 	}
 
-	// call symbol() which is 0x95d89b41
-	symbolData := "0x95d89b41000000000000000000000000"
+	symbol, err = n.tokens.Symbol(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
 
-	symbolHex, err := client.ETHCall(ctx, &api.ETHCallTransaction{
-		To:   address.Contract,
-		Data: &symbolData,
-	}, "latest")
+	labels := n.getLabelValues(address, symbol, client.Name())
+
+	balance := hexStringToFloat64(balanceStr)
+	n.ERC20Balance.WithLabelValues(labels...).Set(balance)
+	n.alerts.Observe(alerts.Target{Job: NameERC20, Name: address.Name, Execution: client.Name()}, address.Alerts, balance, time.Now())
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameERC20,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Symbol:    symbol,
+		Value:     balance,
+	}, time.Now())
+
+	// The balance is up to date even when the total supply is not, so the
+	// failure only drops the share of supply instead of counting as an error.
+	totalSupplyStr, supplyErr := n.totalSupply(ctx, client, address, supplies)
+	if supplyErr != nil {
+		n.ERC20ShareOfSupply.DeleteLabelValues(labels...)
+
+		n.log.WithError(supplyErr).WithFields(logrus.Fields{
+			LabelAddress:   address,
+			LabelExecution: client.Name(),
+		}).Warn("Failed to get erc20 contract totalSupply for the share of supply")
+
+		return nil
+	}
+
+	share := 0.0
+	if totalSupply := hexStringToFloat64(totalSupplyStr); totalSupply > 0 {
+		share = balance / totalSupply
+	}
+
+	n.ERC20ShareOfSupply.WithLabelValues(labels...).Set(share)
+
+	return nil
+}
+
+func (n *ERC20) getSupply(ctx context.Context, client api.ExecutionClient, address *AddressERC20, supplies erc20Supplies) error {
+	var err error
+
+	symbol := ""
+
+	defer func() {
+		if err != nil {
+			n.ERC20Error.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Inc()
+			n.snapshots.SetError(NameERC20, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	totalSupplyStr, err := n.totalSupply(ctx, client, address, supplies)
 	if err != nil {
 		return err
 	}
 
-	symbol, err = hexStringToString(symbolHex)
+	symbol, err = n.tokens.Symbol(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	labels := n.getLabelValues(address, symbol, client.Name())
+
+	totalSupply := hexStringToFloat64(totalSupplyStr)
+	n.ERC20TotalSupply.WithLabelValues(labels...).Set(totalSupply)
+	n.alerts.Observe(alerts.Target{Job: NameERC20, Name: address.Name, Execution: client.Name()}, address.Alerts, totalSupply, time.Now())
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameERC20,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Contract:  address.Contract,
+		Symbol:    symbol,
+		Value:     totalSupply,
+	}, time.Now())
+
+	if !address.Cap {
+		return nil
+	}
+
+	capData := erc20CapSelector
+
+	capStr, err := client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   address.Contract,
+		Data: &capData,
+	}, "latest")
 	if err != nil {
 		return err
 	}
 
-	n.ERC20Balance.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Set(hexStringToFloat64(balanceStr))
+	n.ERC20Cap.WithLabelValues(labels...).Set(hexStringToFloat64(capStr))
 
 	return nil
 }
+
+// erc20Supplies holds the totalSupply result of each contract within a tick of
+// one execution client, so contracts shared by several addresses are read once.
+type erc20Supplies map[string]erc20Supply
+
+type erc20Supply struct {
+	value string
+	err   error
+}
+
+func (n *ERC20) totalSupply(ctx context.Context, client api.ExecutionClient, address *AddressERC20, supplies erc20Supplies) (string, error) {
+	key := strings.ToLower(address.Contract)
+
+	if supply, ok := supplies[key]; ok {
+		return supply.value, supply.err
+	}
+
+	totalSupplyData := erc20TotalSupplySelector
+
+	value, err := client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   address.Contract,
+		Data: &totalSupplyData,
+	}, "latest")
+
+	supplies[key] = erc20Supply{value: value, err: err}
+
+	return value, err
+}
@@ -0,0 +1,247 @@
+package jobs
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameERC20Allowance = "erc20_allowance"
+
+	erc20AllowanceSelector = "0xdd62ed3e"
+)
+
+// maxUint256 is the value used by ERC20 tokens and wallets for infinite approvals.
+var maxUint256 = new(big.Int).Sub(abiWordModulus, big.NewInt(1))
+
+// ERC20Allowance exposes metrics for ethereum ERC20 allowances granted by an owner to a spender.
+type ERC20Allowance struct {
+	*tickTracker
+
+	clients                 []api.ExecutionClient
+	log                     logrus.FieldLogger
+	ERC20Allowance          prometheus.GaugeVec
+	ERC20AllowanceUnlimited prometheus.GaugeVec
+	ERC20AllowanceError     prometheus.CounterVec
+	checkInterval           time.Duration
+	addresses               []*AddressERC20Allowance
+	labelsMap               map[string]int
+	tokens                  *TokenMetadataCache
+	snapshots               *snapshot.Store
+}
+
+type AddressERC20Allowance struct {
+	Owner    string            `yaml:"owner"`
+	Spender  string            `yaml:"spender"`
+	Contract string            `yaml:"contract"`
+	Name     string            `yaml:"name"`
+	Labels   map[string]string `yaml:"labels"`
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressERC20Allowance) GetName() string { return a.Name }
+
+func (n *ERC20Allowance) Name() string {
+	return NameERC20Allowance
+}
+
+// NewERC20Allowance returns a new ERC20Allowance instance.
+func NewERC20Allowance(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC20Allowance, tokens *TokenMetadataCache, snapshots *snapshot.Store, registerer prometheus.Registerer) ERC20Allowance {
+	namespace += "_" + NameERC20Allowance
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelOwner:     1,
+		LabelSpender:   2,
+		LabelContract:  3,
+		LabelSymbol:    4,
+		LabelExecution: 5,
+	}
+
+	for address := range addresses {
+		for label := range addresses[address].Labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	instance := ERC20Allowance{
+		clients:       clients,
+		log:           log.WithField("module", NameERC20Allowance),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
+		addresses:     addresses,
+		checkInterval: checkInterval,
+		labelsMap:     labelsMap,
+		tokens:        tokens,
+		ERC20Allowance: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "allowance",
+				Help:        "The decimal adjusted allowance of a ethereum ERC20 contract granted by an owner to a spender.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		ERC20AllowanceUnlimited: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "unlimited",
+				Help:        "Whether the allowance of a ethereum ERC20 contract is an unlimited (max uint256) approval.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		ERC20AllowanceError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting the allowance of a ethereum ERC20 contract.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.ERC20Allowance)
+	registerer.MustRegister(instance.ERC20AllowanceUnlimited)
+	registerer.MustRegister(instance.ERC20AllowanceError)
+
+	return instance
+}
+
+func (n *ERC20Allowance) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *ERC20Allowance) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		for _, address := range n.addresses {
+			err := n.getAllowance(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get erc20 contract allowance")
+			}
+		}
+	}
+}
+
+func (n *ERC20Allowance) getLabelValues(address *AddressERC20Allowance, symbol, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		if address.Labels != nil && address.Labels[label] != "" {
+			values[index] = address.Labels[label]
+		} else {
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelOwner:
+				values[index] = address.Owner
+			case LabelSpender:
+				values[index] = address.Spender
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelSymbol:
+				values[index] = symbol
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+func (n *ERC20Allowance) getAllowance(ctx context.Context, client api.ExecutionClient, address *AddressERC20Allowance) error {
+	var err error
+
+	symbol := ""
+
+	defer func() {
+		if err != nil {
+			n.ERC20AllowanceError.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Inc()
+			n.snapshots.SetError(NameERC20Allowance, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	owner, err := encodeAddressArgument(address.Owner)
+	if err != nil {
+		return err
+	}
+
+	spender, err := encodeAddressArgument(address.Spender)
+	if err != nil {
+		return err
+	}
+
+	allowanceData, err := ethCallABI(ctx, client, address.Contract, erc20AllowanceSelector+owner+spender)
+	if err != nil {
+		return err
+	}
+
+	allowance, err := decodeABIWordAsBigInt(allowanceData, 0)
+	if err != nil {
+		return err
+	}
+
+	symbol, err = n.tokens.Symbol(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	decimals, err := n.tokens.Decimals(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	labels := n.getLabelValues(address, symbol, client.Name())
+
+	unlimited := 0.0
+	if allowance.Cmp(maxUint256) == 0 {
+		unlimited = 1
+	}
+
+	value := tokenAmountToFloat64(allowance, decimals)
+	n.ERC20Allowance.WithLabelValues(labels...).Set(value)
+	n.ERC20AllowanceUnlimited.WithLabelValues(labels...).Set(unlimited)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameERC20Allowance,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Owner,
+		Contract:  address.Contract,
+		Symbol:    symbol,
+		Value:     value,
+	}, time.Now())
+
+	return nil
+}
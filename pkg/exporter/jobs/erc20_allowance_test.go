@@ -0,0 +1,160 @@
+package jobs
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+)
+
+const testUniswapRouterAddress = "0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"
+
+func TestERC20Allowance_getAllowance(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowanceResponse string
+		wantAllowance     float64
+		wantUnlimited     float64
+	}{
+		{
+			name:              "limited allowance",
+			allowanceResponse: "0x0000000000000000000000000000000000000000000000000000000005f5e100", // 100 USDC (6 decimals)
+			wantAllowance:     100,
+			wantUnlimited:     0,
+		},
+		{
+			name:              "zero allowance",
+			allowanceResponse: "0x0000000000000000000000000000000000000000000000000000000000000000",
+			wantAllowance:     0,
+			wantUnlimited:     0,
+		},
+		{
+			name:              "unlimited allowance",
+			allowanceResponse: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			wantAllowance:     1.157920892373162e71,
+			wantUnlimited:     1,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := &AddressERC20Allowance{
+				Name:     "Hot Wallet Router",
+				Owner:    testHolder1Address,
+				Spender:  testUniswapRouterAddress,
+				Contract: testUSDCContract,
+				Labels:   map[string]string{},
+			}
+
+			mockClient := &mockExecutionClient{
+				symbolResponse:   testABISymbolUSDCResponse,
+				decimalsResponse: encodeABIUintReturn(6),
+				ethCallResponses: map[string]string{
+					erc20AllowanceSelector: tt.allowanceResponse,
+				},
+			}
+
+			allowance := NewERC20Allowance(
+				mockClients(mockClient),
+				testLogger(),
+				15*time.Second,
+				"erc20_allowance_"+strconv.Itoa(i),
+				map[string]string{},
+				[]*AddressERC20Allowance{address},
+				NewTokenMetadataCache(),
+				nil,
+				testRegistry(),
+			)
+
+			err := allowance.getAllowance(context.Background(), mockClient, address)
+			require.NoError(t, err)
+
+			labels := []string{"Hot Wallet Router", testHolder1Address, testUniswapRouterAddress, testUSDCContract, testNameUSDC, testMockNodeName}
+
+			assertMetricValue(t, allowance.ERC20AllowanceUnlimited, labels, tt.wantUnlimited)
+
+			assert.InEpsilon(t, 1+tt.wantAllowance, 1+testutil.ToFloat64(allowance.ERC20Allowance.WithLabelValues(labels...)), 1e-9)
+
+			assertCallSelectors(t, mockClient.callLog, []string{
+				erc20AllowanceSelector,
+				tokenSymbolSelector,
+				tokenDecimalsSelector,
+			})
+			assert.Equal(t,
+				erc20AllowanceSelector+
+					"0000000000000000000000001111111111111111111111111111111111111111"+
+					"00000000000000000000000068b3465833fb72a70ecdf485e0e4c7bd8665fc45",
+				mockClient.callLog[0].data,
+			)
+		})
+	}
+}
+
+func TestERC20Allowance_tick_CachesTokenMetadata(t *testing.T) {
+	address := &AddressERC20Allowance{
+		Name:     "Cached",
+		Owner:    testHolder1Address,
+		Spender:  testUniswapRouterAddress,
+		Contract: testUSDCContract,
+		Labels:   map[string]string{},
+	}
+
+	responses := map[string]string{
+		erc20AllowanceSelector: encodeABIUintReturn(1),
+	}
+
+	client1 := &mockExecutionClient{name: testNodeName1, ethCallResponses: responses}
+	client2 := &mockExecutionClient{name: "node-2", ethCallResponses: responses}
+
+	tokens := NewTokenMetadataCache()
+
+	allowance := NewERC20Allowance(
+		[]api.ExecutionClient{client1, client2},
+		testLogger(),
+		15*time.Second,
+		"erc20_allowance_cache",
+		map[string]string{},
+		[]*AddressERC20Allowance{address},
+		tokens,
+		nil,
+		testRegistry(),
+	)
+
+	allowance.tick(context.Background())
+	allowance.tick(context.Background())
+
+	// allowance + symbol + decimals on the first tick, allowance only on the second.
+	assert.Len(t, client1.callLog, 4)
+	assert.Len(t, client2.callLog, 4)
+
+	erc20 := NewERC20(
+		[]api.ExecutionClient{client1},
+		testLogger(),
+		15*time.Second,
+		"erc20_allowance_cache_shared",
+		map[string]string{},
+		[]*AddressERC20{{Name: "Shared", Address: testHolder1Address, Contract: testUSDCContract}},
+		tokens,
+		nil,
+		nil,
+		testRegistry(),
+	)
+
+	erc20.tick(context.Background())
+
+	// The ERC20 job reuses the symbol cached by the allowance job, calling only balanceOf and totalSupply.
+	assert.Len(t, client1.callLog, 6)
+}
+
+func TestERC20Allowance_Name(t *testing.T) {
+	allowance := &ERC20Allowance{}
+	if allowance.Name() != NameERC20Allowance {
+		t.Errorf("Expected name %s, got %s", NameERC20Allowance, allowance.Name())
+	}
+}
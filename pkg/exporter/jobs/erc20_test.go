@@ -2,11 +2,22 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 func TestERC20_getBalance(t *testing.T) {
@@ -74,17 +85,21 @@ func TestERC20_getBalance(t *testing.T) {
 				namespace,
 				map[string]string{},
 				[]*AddressERC20{tt.address},
+				NewTokenMetadataCache(),
+				nil,
+				nil,
+				testRegistry(),
 			)
 
-			err := erc20.getBalance(context.Background(), mockClient, tt.address)
+			err := erc20.getBalance(context.Background(), mockClient, tt.address, erc20Supplies{})
 
 			if (err != nil) != tt.wantError {
 				t.Errorf("getBalance() error = %v, wantError %v", err, tt.wantError)
 			}
 
-			// Verify both balanceOf and symbol calls were made
-			if len(mockClient.callLog) != 2 {
-				t.Errorf("Expected 2 RPC calls (balanceOf + symbol), got %d", len(mockClient.callLog))
+			// Verify balanceOf, symbol and totalSupply calls were made
+			if len(mockClient.callLog) != 3 {
+				t.Errorf("Expected 3 RPC calls (balanceOf + symbol + totalSupply), got %d", len(mockClient.callLog))
 			}
 
 			if len(mockClient.callLog) > 0 && mockClient.callLog[0].data[:10] != "0x70a08231" {
@@ -94,6 +109,10 @@ func TestERC20_getBalance(t *testing.T) {
 			if len(mockClient.callLog) > 1 && mockClient.callLog[1].data[:10] != "0x95d89b41" {
 				t.Errorf("Second call should be symbol (0x95d89b41)")
 			}
+
+			if len(mockClient.callLog) > 2 && mockClient.callLog[2].data[:10] != erc20TotalSupplySelector {
+				t.Errorf("Third call should be totalSupply (%s)", erc20TotalSupplySelector)
+			}
 		})
 	}
 }
@@ -130,13 +149,17 @@ func TestERC20_tick(t *testing.T) {
 		"test_erc20_tick",
 		map[string]string{},
 		addresses,
+		NewTokenMetadataCache(),
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
 	erc20.tick(ctx)
 
-	// Each address requires 2 calls (balanceOf + symbol), 1 client * 2 addresses
-	expectedCalls := len(addresses) * 2
+	// Each address requires 3 calls (balanceOf + symbol + totalSupply), 1 client * 2 addresses
+	expectedCalls := len(addresses) * 3
 	if len(mockClient.callLog) != expectedCalls {
 		t.Errorf("Expected %d RPC calls, got %d", expectedCalls, len(mockClient.callLog))
 	}
@@ -164,6 +187,10 @@ func TestERC20_getLabelValues(t *testing.T) {
 		"test_erc20_labels",
 		map[string]string{},
 		addresses,
+		NewTokenMetadataCache(),
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	labels := erc20.getLabelValues(addresses[0], testNameUSDC, "mock-node")
@@ -191,3 +218,308 @@ func TestERC20_Name(t *testing.T) {
 		t.Errorf("Expected name %s, got %s", NameERC20, erc20.Name())
 	}
 }
+
+func TestERC20_getBalance_ShareOfSupply(t *testing.T) {
+	address := &AddressERC20{
+		Name:     "Treasury",
+		Address:  testHolder1Address,
+		Contract: testUSDCContract,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		balanceOfResponse: encodeABIUintReturn(250),
+		symbolResponse:    testABISymbolUSDCResponse,
+		ethCallResponses: map[string]string{
+			erc20TotalSupplySelector: encodeABIUintReturn(1000),
+		},
+	}
+
+	erc20 := NewERC20(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc20_share_of_supply",
+		map[string]string{},
+		[]*AddressERC20{address},
+		NewTokenMetadataCache(),
+		nil,
+		nil,
+		testRegistry(),
+	)
+
+	err := erc20.getBalance(context.Background(), mockClient, address, erc20Supplies{})
+	require.NoError(t, err)
+
+	labels := erc20.getLabelValues(address, testNameUSDC, testMockNodeName)
+
+	assertMetricValue(t, erc20.ERC20Balance, labels, 250)
+	assertMetricValue(t, erc20.ERC20ShareOfSupply, labels, 0.25)
+}
+
+func TestERC20_getBalance_TotalSupplyError(t *testing.T) {
+	address := &AddressERC20{
+		Name:     "Treasury",
+		Address:  testHolder1Address,
+		Contract: testUSDCContract,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		balanceOfResponse: encodeABIUintReturn(250),
+		symbolResponse:    testABISymbolUSDCResponse,
+		ethCallResponses: map[string]string{
+			erc20TotalSupplySelector: encodeABIUintReturn(1000),
+		},
+	}
+
+	snapshots := snapshot.NewStore(testLogger())
+
+	erc20 := NewERC20(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc20_total_supply_error",
+		map[string]string{},
+		[]*AddressERC20{address},
+		NewTokenMetadataCache(),
+		nil,
+		snapshots,
+		testRegistry(),
+	)
+
+	require.NoError(t, erc20.getBalance(context.Background(), mockClient, address, erc20Supplies{}))
+
+	labels := erc20.getLabelValues(address, testNameUSDC, testMockNodeName)
+
+	mockClient.balanceOfResponse = encodeABIUintReturn(300)
+
+	supplies := erc20Supplies{
+		strings.ToLower(testUSDCContract): {err: errors.New("execution reverted")},
+	}
+
+	require.NoError(t, erc20.getBalance(context.Background(), mockClient, address, supplies))
+
+	// The balance is still exported and not reported as failing, only the
+	// share of supply is dropped.
+	assertMetricValue(t, erc20.ERC20Balance, labels, 300)
+	assert.Equal(t, 0, testutil.CollectAndCount(&erc20.ERC20ShareOfSupply))
+	assert.Equal(t, 0, testutil.CollectAndCount(&erc20.ERC20Error))
+
+	node := snapshots.Snapshot()[NameERC20][0].Nodes[testMockNodeName]
+	assert.Equal(t, 300.0, node.Value)
+	assert.Empty(t, node.LastError)
+}
+
+func TestERC20_tick_SharedTotalSupply(t *testing.T) {
+	mockClient := &mockExecutionClient{
+		balanceOfResponse: encodeABIUintReturn(250),
+		symbolResponse:    testABISymbolUSDCResponse,
+		ethCallResponses: map[string]string{
+			erc20TotalSupplySelector: encodeABIUintReturn(1000),
+		},
+	}
+
+	addresses := []*AddressERC20{
+		{Name: "Treasury", Address: testHolder1Address, Contract: testUSDCContract, Labels: map[string]string{}},
+		{Name: "Reserve", Address: testHolder2Address, Contract: testUSDCContract, Labels: map[string]string{}},
+		{Name: "USDC Supply", Contract: testUSDCContract, Labels: map[string]string{}},
+	}
+
+	erc20 := NewERC20(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc20_shared_total_supply",
+		map[string]string{},
+		addresses,
+		NewTokenMetadataCache(),
+		nil,
+		nil,
+		testRegistry(),
+	)
+
+	erc20.tick(context.Background())
+
+	// totalSupply and symbol are read once for the contract, balanceOf per address.
+	assertCallSelectors(t, mockClient.callLog, []string{
+		"0x70a08231", tokenSymbolSelector, erc20TotalSupplySelector,
+		"0x70a08231",
+	})
+
+	assertMetricValue(t, erc20.ERC20ShareOfSupply, erc20.getLabelValues(addresses[1], testNameUSDC, testMockNodeName), 0.25)
+	assertMetricValue(t, erc20.ERC20TotalSupply, erc20.getLabelValues(addresses[2], testNameUSDC, testMockNodeName), 1000)
+}
+
+func TestERC20_getBalance_Snapshot(t *testing.T) {
+	address := &AddressERC20{
+		Name:     "Treasury",
+		Address:  testHolder1Address,
+		Contract: testUSDCContract,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		balanceOfResponse: encodeABIUintReturn(250),
+		symbolResponse:    testABISymbolUSDCResponse,
+		ethCallResponses: map[string]string{
+			erc20TotalSupplySelector: encodeABIUintReturn(1000),
+		},
+	}
+
+	snapshots := snapshot.NewStore(testLogger())
+	snapshots.SetHead(testMockNodeName, 100, time.Now())
+
+	erc20 := NewERC20(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc20_snapshot",
+		map[string]string{},
+		[]*AddressERC20{address},
+		NewTokenMetadataCache(),
+		nil,
+		snapshots,
+		testRegistry(),
+	)
+
+	require.NoError(t, erc20.getBalance(context.Background(), mockClient, address, erc20Supplies{}))
+
+	mockClient.balanceOfError = errors.New("execution reverted")
+
+	require.Error(t, erc20.getBalance(context.Background(), mockClient, address, erc20Supplies{}))
+
+	addresses := snapshots.Snapshot()[NameERC20]
+	require.Len(t, addresses, 1)
+
+	assert.Equal(t, "Treasury", addresses[0].Name)
+	assert.Equal(t, testHolder1Address, addresses[0].Address)
+	assert.Equal(t, testUSDCContract, addresses[0].Contract)
+	assert.Equal(t, testNameUSDC, addresses[0].Symbol)
+
+	node := addresses[0].Nodes[testMockNodeName]
+	require.NotNil(t, node)
+
+	assert.Equal(t, 250.0, node.Value)
+	assert.Equal(t, uint64(100), node.BlockNumber)
+	assert.NotNil(t, node.Timestamp)
+	assert.Equal(t, "execution reverted", node.LastError)
+	assert.NotNil(t, node.LastErrorTimestamp)
+}
+
+func TestERC20_getSupply(t *testing.T) {
+	tests := []struct {
+		name          string
+		cap           bool
+		wantSelectors []string
+	}{
+		{
+			name:          "total supply only",
+			wantSelectors: []string{erc20TotalSupplySelector, tokenSymbolSelector},
+		},
+		{
+			name:          "total supply and cap",
+			cap:           true,
+			wantSelectors: []string{erc20TotalSupplySelector, tokenSymbolSelector, erc20CapSelector},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := &AddressERC20{
+				Name:     "USDC Supply",
+				Contract: testUSDCContract,
+				Cap:      tt.cap,
+				Labels:   map[string]string{},
+			}
+
+			mockClient := &mockExecutionClient{
+				symbolResponse: testABISymbolUSDCResponse,
+				ethCallResponses: map[string]string{
+					erc20TotalSupplySelector: encodeABIUintReturn(1000),
+					erc20CapSelector:         encodeABIUintReturn(5000),
+				},
+			}
+
+			erc20 := NewERC20(
+				mockClients(mockClient),
+				testLogger(),
+				15*time.Second,
+				"erc20_supply_"+strconv.Itoa(i),
+				map[string]string{},
+				[]*AddressERC20{address},
+				NewTokenMetadataCache(),
+				nil,
+				nil,
+				testRegistry(),
+			)
+
+			erc20.tick(context.Background())
+
+			labels := erc20.getLabelValues(address, testNameUSDC, testMockNodeName)
+
+			assertMetricValue(t, erc20.ERC20TotalSupply, labels, 1000)
+			assertCallSelectors(t, mockClient.callLog, tt.wantSelectors)
+
+			if tt.cap {
+				assertMetricValue(t, erc20.ERC20Cap, labels, 5000)
+			}
+		})
+	}
+}
+
+func TestERC20_tick_SupplyAlerts(t *testing.T) {
+	notifications := make(chan map[string]any, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		notifications <- body
+	}))
+	t.Cleanup(server.Close)
+
+	maxSupply := 500.0
+
+	address := &AddressERC20{
+		Name:     "USDC Supply",
+		Contract: testUSDCContract,
+		Alerts:   []*alerts.Rule{{Name: "minted", Max: &maxSupply}},
+		Labels:   map[string]string{},
+	}
+
+	alerting, err := alerts.NewManager(testLogger(), &alerts.Config{
+		Webhooks: []*alerts.Webhook{{Name: "ops", URL: server.URL}},
+	}, "erc20_supply_alerts", map[string]string{}, testRegistry())
+	require.NoError(t, err)
+
+	mockClient := &mockExecutionClient{
+		symbolResponse: testABISymbolUSDCResponse,
+		ethCallResponses: map[string]string{
+			erc20TotalSupplySelector: encodeABIUintReturn(1000),
+		},
+	}
+
+	erc20 := NewERC20(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc20_supply_alerts",
+		map[string]string{},
+		[]*AddressERC20{address},
+		NewTokenMetadataCache(),
+		alerting,
+		nil,
+		testRegistry(),
+	)
+
+	erc20.tick(context.Background())
+
+	require.Len(t, notifications, 1)
+
+	notification := <-notifications
+	assert.Equal(t, alerts.StatusFiring, notification["status"])
+	assert.Equal(t, NameERC20, notification["job"])
+	assert.Equal(t, "USDC Supply", notification["name"])
+	assert.InDelta(t, 1000, notification["value"], 0)
+}
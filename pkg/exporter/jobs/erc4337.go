@@ -2,30 +2,44 @@ package jobs
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // ERC4337 exposes metrics for ethereum ERC4337 EntryPoint contract by address.
 type ERC4337 struct {
-	clients        []api.ExecutionClient
-	log            logrus.FieldLogger
-	ERC4337Balance prometheus.GaugeVec
-	ERC4337Error   prometheus.CounterVec
-	checkInterval  time.Duration
-	addresses      []*AddressERC4337
-	labelsMap      map[string]int
+	*tickTracker
+
+	clients                    []api.ExecutionClient
+	log                        logrus.FieldLogger
+	ERC4337Balance             prometheus.GaugeVec
+	ERC4337Staked              prometheus.GaugeVec
+	ERC4337Stake               prometheus.GaugeVec
+	ERC4337UnstakeDelaySeconds prometheus.GaugeVec
+	ERC4337WithdrawTime        prometheus.GaugeVec
+	ERC4337Nonce               prometheus.GaugeVec
+	ERC4337Error               prometheus.CounterVec
+	checkInterval              time.Duration
+	addresses                  []*AddressERC4337
+	labelsMap                  map[string]int
+	snapshots                  *snapshot.Store
 }
 
 type AddressERC4337 struct {
-	Address  string            `yaml:"address"`
-	Contract string            `yaml:"contract"`
-	Name     string            `yaml:"name"`
-	Labels   map[string]string `yaml:"labels"`
+	Address  string `yaml:"address"`
+	Contract string `yaml:"contract"`
+	// NonceKeys are the 192 bit nonce keys to export getNonce(address, key) for.
+	NonceKeys []*big.Int        `yaml:"nonceKeys"`
+	Name      string            `yaml:"name"`
+	Labels    map[string]string `yaml:"labels"`
 }
 
 // GetName returns the configured name of this address.
@@ -33,14 +47,41 @@ func (a *AddressERC4337) GetName() string { return a.Name }
 
 const (
 	NameERC4337 = "erc4337"
+
+	// getDepositInfo(address) and getNonce(address,uint192) share their
+	// selectors and ABI encoded return layout across EntryPoint v0.6, v0.7 and
+	// v0.8. DepositInfo is (deposit, staked, stake, unstakeDelaySec,
+	// withdrawTime); the deposit equals balanceOf and is exported as the
+	// balance.
+	erc4337GetDepositInfoSelector = "0x5287ce12"
+	erc4337GetNonceSelector       = "0x35567e1a"
+
+	erc4337DepositInfoStakedWord          = 1
+	erc4337DepositInfoStakeWord           = 2
+	erc4337DepositInfoUnstakeDelaySecWord = 3
+	erc4337DepositInfoWithdrawTimeWord    = 4
 )
 
+// erc4337MaxNonceKey is the exclusive upper bound of a uint192 nonce key.
+var erc4337MaxNonceKey = new(big.Int).Lsh(big.NewInt(1), 192)
+
+// Validate checks the configured nonce keys fit in a uint192.
+func (a *AddressERC4337) Validate() error {
+	for _, key := range a.NonceKeys {
+		if key == nil || key.Sign() < 0 || key.Cmp(erc4337MaxNonceKey) >= 0 {
+			return fmt.Errorf("erc4337 %s: nonce keys must be uint192 values", a.Name)
+		}
+	}
+
+	return nil
+}
+
 func (n *ERC4337) Name() string {
 	return NameERC4337
 }
 
 // NewERC4337 returns a new ERC4337 instance.
-func NewERC4337(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC4337) ERC4337 {
+func NewERC4337(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC4337, snapshots *snapshot.Store, registerer prometheus.Registerer) ERC4337 {
 	namespace += "_" + NameERC4337
 
 	labelsMap := map[string]int{
@@ -63,21 +104,56 @@ func NewERC4337(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 		labels[index] = label
 	}
 
+	newGaugeVec := func(name, help string, labelNames []string) prometheus.GaugeVec {
+		return *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        name,
+				Help:        help,
+				ConstLabels: constLabels,
+			},
+			labelNames,
+		)
+	}
+
 	instance := ERC4337{
 		clients:       clients,
 		log:           log.WithField("module", NameERC4337),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
-		ERC4337Balance: *prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        metricNameBalance,
-				Help:        "The deposit balance of a ethereum ERC4337 account in the EntryPoint contract.",
-				ConstLabels: constLabels,
-			},
+		ERC4337Balance: newGaugeVec(
+			metricNameBalance,
+			"The deposit balance of a ethereum ERC4337 account in the EntryPoint contract.",
+			labels,
+		),
+		ERC4337Staked: newGaugeVec(
+			"staked",
+			"Whether a ethereum ERC4337 account is staked in the EntryPoint contract.",
 			labels,
 		),
+		ERC4337Stake: newGaugeVec(
+			"stake",
+			"The stake of a ethereum ERC4337 account in the EntryPoint contract.",
+			labels,
+		),
+		ERC4337UnstakeDelaySeconds: newGaugeVec(
+			"unstake_delay_seconds",
+			"The unstake delay of a ethereum ERC4337 account stake in the EntryPoint contract.",
+			labels,
+		),
+		ERC4337WithdrawTime: newGaugeVec(
+			"withdraw_time",
+			"The unix time after which the unlocked stake of a ethereum ERC4337 account can be withdrawn, 0 when not unlocking.",
+			labels,
+		),
+		ERC4337Nonce: newGaugeVec(
+			"nonce",
+			"The EntryPoint nonce of a ethereum ERC4337 account for a nonce key.",
+			append(append([]string{}, labels...), LabelKey),
+		),
 		ERC4337Error: *prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace:   namespace,
@@ -89,14 +165,20 @@ func NewERC4337(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 		),
 	}
 
-	prometheus.MustRegister(instance.ERC4337Balance)
-	prometheus.MustRegister(instance.ERC4337Error)
+	registerer.MustRegister(instance.ERC4337Balance)
+	registerer.MustRegister(instance.ERC4337Staked)
+	registerer.MustRegister(instance.ERC4337Stake)
+	registerer.MustRegister(instance.ERC4337UnstakeDelaySeconds)
+	registerer.MustRegister(instance.ERC4337WithdrawTime)
+	registerer.MustRegister(instance.ERC4337Nonce)
+	registerer.MustRegister(instance.ERC4337Error)
 
 	return instance
 }
 
 func (n *ERC4337) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -104,6 +186,7 @@ func (n *ERC4337) Start(ctx context.Context) {
 			return
 		case <-time.After(n.checkInterval):
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
@@ -118,6 +201,22 @@ func (n *ERC4337) tick(ctx context.Context) {
 					LabelExecution: client.Name(),
 				}).Error("Failed to get erc4337 contract balanceOf address")
 			}
+
+			err = n.getDepositInfo(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get erc4337 contract deposit info of address")
+			}
+
+			err = n.getNonces(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get erc4337 contract nonces of address")
+			}
 		}
 	}
 }
@@ -153,6 +252,7 @@ func (n *ERC4337) getBalance(ctx context.Context, client api.ExecutionClient, ad
 	defer func() {
 		if err != nil {
 			n.ERC4337Error.WithLabelValues(n.getLabelValues(address, client.Name())...).Inc()
+			n.snapshots.SetError(NameERC4337, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
@@ -168,7 +268,96 @@ func (n *ERC4337) getBalance(ctx context.Context, client api.ExecutionClient, ad
 		return err
 	}
 
-	n.ERC4337Balance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(hexStringToFloat64(balanceStr))
+	balance := hexStringToFloat64(balanceStr)
+	n.ERC4337Balance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(balance)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameERC4337,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Value:     balance,
+	}, time.Now())
+
+	return nil
+}
+
+func (n *ERC4337) getDepositInfo(ctx context.Context, client api.ExecutionClient, address *AddressERC4337) error {
+	var err error
+
+	labels := n.getLabelValues(address, client.Name())
+
+	defer func() {
+		if err != nil {
+			n.ERC4337Error.WithLabelValues(labels...).Inc()
+			n.snapshots.SetError(NameERC4337, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	account, err := encodeAddressArgument(address.Address)
+	if err != nil {
+		return err
+	}
+
+	data, err := ethCallABI(ctx, client, address.Contract, erc4337GetDepositInfoSelector+account)
+	if err != nil {
+		return err
+	}
+
+	words := make([]*big.Int, erc4337DepositInfoWithdrawTimeWord+1)
+
+	for i := range words {
+		words[i], err = decodeABIWordAsBigInt(data, i*32)
+		if err != nil {
+			return err
+		}
+	}
+
+	n.ERC4337Staked.WithLabelValues(labels...).Set(bigIntToFloat64(words[erc4337DepositInfoStakedWord]))
+	n.ERC4337Stake.WithLabelValues(labels...).Set(bigIntToFloat64(words[erc4337DepositInfoStakeWord]))
+	n.ERC4337UnstakeDelaySeconds.WithLabelValues(labels...).Set(bigIntToFloat64(words[erc4337DepositInfoUnstakeDelaySecWord]))
+	n.ERC4337WithdrawTime.WithLabelValues(labels...).Set(bigIntToFloat64(words[erc4337DepositInfoWithdrawTimeWord]))
 
 	return nil
 }
+
+// getNonces exports the EntryPoint nonce of each configured nonce key, a
+// failing key does not stop the others from being exported.
+func (n *ERC4337) getNonces(ctx context.Context, client api.ExecutionClient, address *AddressERC4337) error {
+	if len(address.NonceKeys) == 0 {
+		return nil
+	}
+
+	labels := n.getLabelValues(address, client.Name())
+
+	account, err := encodeAddressArgument(address.Address)
+	if err != nil {
+		n.ERC4337Error.WithLabelValues(labels...).Inc()
+		n.snapshots.SetError(NameERC4337, address.Name, client.Name(), err, time.Now())
+
+		return err
+	}
+
+	var errs []error
+
+	for _, key := range address.NonceKeys {
+		keyLabels := append(append([]string{}, labels...), key.String())
+
+		nonce, err := ethCallUint256(ctx, client, address.Contract, erc4337GetNonceSelector+account+formatABIWord(key))
+		if err != nil {
+			err = fmt.Errorf("failed to get nonce of key %s: %w", key, err)
+
+			n.ERC4337Nonce.DeleteLabelValues(keyLabels...)
+			n.ERC4337Error.WithLabelValues(labels...).Inc()
+			n.snapshots.SetError(NameERC4337, address.Name, client.Name(), err, time.Now())
+
+			errs = append(errs, err)
+
+			continue
+		}
+
+		n.ERC4337Nonce.WithLabelValues(keyLabels...).Set(bigIntToFloat64(nonce))
+	}
+
+	return errors.Join(errs...)
+}
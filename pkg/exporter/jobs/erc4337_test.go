@@ -2,11 +2,15 @@ package jobs
 
 import (
 	"context"
+	"math/big"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
 )
 
 func TestERC4337_getBalance(t *testing.T) {
@@ -58,6 +62,8 @@ func TestERC4337_getBalance(t *testing.T) {
 				namespace,
 				map[string]string{},
 				[]*AddressERC4337{tt.address},
+				nil,
+				testRegistry(),
 			)
 
 			err := erc4337.getBalance(context.Background(), mockClient, tt.address)
@@ -109,13 +115,15 @@ func TestERC4337_tick(t *testing.T) {
 		"test_erc4337_tick",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
 	erc4337.tick(ctx)
 
-	// Each address requires 1 call (balanceOf), 1 client * 2 addresses
-	expectedCalls := len(addresses)
+	// Each address requires 2 calls (balanceOf and getDepositInfo), 1 client * 2 addresses
+	expectedCalls := len(addresses) * 2
 	if len(mockClient.callLog) != expectedCalls {
 		t.Errorf("Expected %d RPC calls, got %d", expectedCalls, len(mockClient.callLog))
 	}
@@ -143,6 +151,8 @@ func TestERC4337_getLabelValues(t *testing.T) {
 		"test_erc4337_labels",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	labels := erc4337.getLabelValues(addresses[0], "mock-node")
@@ -152,6 +162,120 @@ func TestERC4337_getLabelValues(t *testing.T) {
 	}
 }
 
+func TestERC4337_getDepositInfo(t *testing.T) {
+	entryPoints := map[string]string{
+		"v0.6": testEntryPointAddress,
+		"v0.7": "0x0000000071727De22E5E9d8BAf0edAc6f37da032",
+		"v0.8": "0x4337084D9E255Ff0702461CF8895CE9E3b5Ff108",
+	}
+
+	for version, entryPoint := range entryPoints {
+		t.Run(version, func(t *testing.T) {
+			address := &AddressERC4337{
+				Name:      "Paymaster",
+				Address:   testHolder1Address,
+				Contract:  entryPoint,
+				NonceKeys: []*big.Int{big.NewInt(0), big.NewInt(7)},
+				Labels:    map[string]string{},
+			}
+
+			account, err := encodeAddressArgument(testHolder1Address)
+			require.NoError(t, err)
+
+			mockClient := &mockExecutionClient{
+				ethCallResponses: map[string]string{
+					erc4337GetDepositInfoSelector: encodeABIWordsReturn(
+						big.NewInt(5e17), big.NewInt(1), big.NewInt(1e18), big.NewInt(86400), big.NewInt(1700000000),
+					),
+					erc4337GetNonceSelector + account + formatABIWord(big.NewInt(0)): encodeABIUintReturn(12),
+					erc4337GetNonceSelector + account + formatABIWord(big.NewInt(7)): encodeABIUintReturn(3),
+				},
+			}
+
+			erc4337 := NewERC4337(
+				mockClients(mockClient),
+				testLogger(),
+				15*time.Second,
+				"erc4337_deposit_info_"+strings.ReplaceAll(version, ".", "_"),
+				map[string]string{},
+				[]*AddressERC4337{address},
+				nil,
+				testRegistry(),
+			)
+
+			require.NoError(t, erc4337.getDepositInfo(context.Background(), mockClient, address))
+			require.NoError(t, erc4337.getNonces(context.Background(), mockClient, address))
+
+			labels := erc4337.getLabelValues(address, testMockNodeName)
+
+			assertMetricValue(t, erc4337.ERC4337Staked, labels, 1)
+			assertMetricValue(t, erc4337.ERC4337Stake, labels, 1e18)
+			assertMetricValue(t, erc4337.ERC4337UnstakeDelaySeconds, labels, 86400)
+			assertMetricValue(t, erc4337.ERC4337WithdrawTime, labels, 1700000000)
+			assertMetricValue(t, erc4337.ERC4337Nonce, append(append([]string{}, labels...), "0"), 12)
+			assertMetricValue(t, erc4337.ERC4337Nonce, append(append([]string{}, labels...), "7"), 3)
+
+			assertCallSelectors(t, mockClient.callLog, []string{
+				erc4337GetDepositInfoSelector,
+				erc4337GetNonceSelector,
+				erc4337GetNonceSelector,
+			})
+		})
+	}
+}
+
+func TestERC4337_getNonces_KeyError(t *testing.T) {
+	address := &AddressERC4337{
+		Name:      "Paymaster",
+		Address:   testHolder1Address,
+		Contract:  testEntryPointAddress,
+		NonceKeys: []*big.Int{big.NewInt(0), big.NewInt(7)},
+		Labels:    map[string]string{},
+	}
+
+	account, err := encodeAddressArgument(testHolder1Address)
+	require.NoError(t, err)
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			erc4337GetDepositInfoSelector: encodeABIWordsReturn(
+				big.NewInt(5e17), big.NewInt(1), big.NewInt(1e18), big.NewInt(86400), big.NewInt(0),
+			),
+			erc4337GetNonceSelector + account + formatABIWord(big.NewInt(0)): "0xzz",
+			erc4337GetNonceSelector + account + formatABIWord(big.NewInt(7)): encodeABIUintReturn(3),
+		},
+	}
+
+	erc4337 := NewERC4337(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc4337_nonce_key_error",
+		map[string]string{},
+		[]*AddressERC4337{address},
+		nil,
+		testRegistry(),
+	)
+
+	erc4337.tick(context.Background())
+
+	labels := erc4337.getLabelValues(address, testMockNodeName)
+
+	// The deposit info and the other key are exported despite the failing key.
+	assertMetricValue(t, erc4337.ERC4337Stake, labels, 1e18)
+	assertMetricValue(t, erc4337.ERC4337Nonce, append(append([]string{}, labels...), "7"), 3)
+	require.Equal(t, 1, testutil.CollectAndCount(&erc4337.ERC4337Nonce))
+	require.InDelta(t, 1.0, testutil.ToFloat64(erc4337.ERC4337Error.WithLabelValues(labels...)), 0)
+}
+
+func TestAddressERC4337_Validate(t *testing.T) {
+	valid := &AddressERC4337{Name: "valid", NonceKeys: []*big.Int{new(big.Int).Sub(erc4337MaxNonceKey, big.NewInt(1))}}
+	require.NoError(t, valid.Validate())
+
+	invalid := &AddressERC4337{Name: "invalid", NonceKeys: []*big.Int{erc4337MaxNonceKey}}
+	require.ErrorContains(t, invalid.Validate(), "uint192")
+}
+
 func TestERC4337_Name(t *testing.T) {
 	erc4337 := &ERC4337{}
 	if erc4337.Name() != NameERC4337 {
@@ -2,28 +2,38 @@ package jobs
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"math/big"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // ERC4626 exposes metrics for ethereum ERC4626 vault contracts.
 type ERC4626 struct {
-	clients       []api.ExecutionClient
-	log           logrus.FieldLogger
-	ERC4626Assets prometheus.GaugeVec
-	ERC4626Error  prometheus.CounterVec
-	checkInterval time.Duration
-	addresses     []*AddressERC4626
-	labelsMap     map[string]int
+	*tickTracker
+
+	clients              []api.ExecutionClient
+	log                  logrus.FieldLogger
+	ERC4626Assets        prometheus.GaugeVec
+	ERC4626TotalAssets   prometheus.GaugeVec
+	ERC4626TotalSupply   prometheus.GaugeVec
+	ERC4626PricePerShare prometheus.GaugeVec
+	ERC4626MaxWithdraw   prometheus.GaugeVec
+	ERC4626MaxRedeem     prometheus.GaugeVec
+	ERC4626Error         prometheus.CounterVec
+	checkInterval        time.Duration
+	addresses            []*AddressERC4626
+	labelsMap            map[string]int
+	tokens               *TokenMetadataCache
+	snapshots            *snapshot.Store
 }
 
 type AddressERC4626 struct {
+	// Address is the share holder. When empty only vault-level metrics are exported.
 	Address  string            `yaml:"address"`
 	Contract string            `yaml:"contract"`
 	Name     string            `yaml:"name"`
@@ -35,6 +45,13 @@ func (a *AddressERC4626) GetName() string { return a.Name }
 
 const (
 	NameERC4626 = "erc4626"
+
+	erc4626AssetSelector           = "0x38d52e0f"
+	erc4626BalanceOfSelector       = "0x70a08231"
+	erc4626TotalAssetsSelector     = "0x01e1d114"
+	erc4626ConvertToAssetsSelector = "0x07a2d13a"
+	erc4626MaxWithdrawSelector     = "0xce96cb77"
+	erc4626MaxRedeemSelector       = "0xd905777e"
 )
 
 func (n *ERC4626) Name() string {
@@ -42,7 +59,7 @@ func (n *ERC4626) Name() string {
 }
 
 // NewERC4626 returns a new ERC4626 instance.
-func NewERC4626(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC4626) ERC4626 {
+func NewERC4626(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC4626, tokens *TokenMetadataCache, snapshots *snapshot.Store, registerer prometheus.Registerer) ERC4626 {
 	namespace += "_" + NameERC4626
 
 	labelsMap := map[string]int{
@@ -66,20 +83,50 @@ func NewERC4626(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 		labels[index] = label
 	}
 
+	newGaugeVec := func(name, help string) prometheus.GaugeVec {
+		return *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        name,
+				Help:        help,
+				ConstLabels: constLabels,
+			},
+			labels,
+		)
+	}
+
 	instance := ERC4626{
 		clients:       clients,
 		log:           log.WithField("module", NameERC4626),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
-		ERC4626Assets: *prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace:   namespace,
-				Name:        "assets",
-				Help:        "The asset value from ERC4626 vault convertToAssets function.",
-				ConstLabels: constLabels,
-			},
-			labels,
+		tokens:        tokens,
+		ERC4626Assets: newGaugeVec(
+			"assets",
+			"The asset value from ERC4626 vault convertToAssets function.",
+		),
+		ERC4626TotalAssets: newGaugeVec(
+			"total_assets",
+			"The decimal adjusted total underlying assets managed by an ERC4626 vault.",
+		),
+		ERC4626TotalSupply: newGaugeVec(
+			"total_supply",
+			"The decimal adjusted total supply of shares of an ERC4626 vault, labelled with the vault share symbol.",
+		),
+		ERC4626PricePerShare: newGaugeVec(
+			"price_per_share",
+			"The decimal adjusted underlying assets one whole share of an ERC4626 vault converts to.",
+		),
+		ERC4626MaxWithdraw: newGaugeVec(
+			"max_withdraw",
+			"The decimal adjusted underlying assets a holder can withdraw from an ERC4626 vault.",
+		),
+		ERC4626MaxRedeem: newGaugeVec(
+			"max_redeem",
+			"The decimal adjusted shares a holder can redeem from an ERC4626 vault, labelled with the vault share symbol.",
 		),
 		ERC4626Error: *prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -92,14 +139,20 @@ func NewERC4626(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 		),
 	}
 
-	prometheus.MustRegister(instance.ERC4626Assets)
-	prometheus.MustRegister(instance.ERC4626Error)
+	registerer.MustRegister(instance.ERC4626Assets)
+	registerer.MustRegister(instance.ERC4626TotalAssets)
+	registerer.MustRegister(instance.ERC4626TotalSupply)
+	registerer.MustRegister(instance.ERC4626PricePerShare)
+	registerer.MustRegister(instance.ERC4626MaxWithdraw)
+	registerer.MustRegister(instance.ERC4626MaxRedeem)
+	registerer.MustRegister(instance.ERC4626Error)
 
 	return instance
 }
 
 func (n *ERC4626) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -107,6 +160,7 @@ func (n *ERC4626) Start(ctx context.Context) {
 			return
 		case <-time.After(n.checkInterval):
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
@@ -114,12 +168,22 @@ func (n *ERC4626) Start(ctx context.Context) {
 func (n *ERC4626) tick(ctx context.Context) {
 	for _, client := range n.clients {
 		for _, address := range n.addresses {
-			err := n.getAssets(ctx, client, address)
+			if address.Address != "" {
+				err := n.getAssets(ctx, client, address)
+				if err != nil {
+					n.log.WithError(err).WithFields(logrus.Fields{
+						LabelAddress:   address,
+						LabelExecution: client.Name(),
+					}).Error("Failed to get ERC4626 vault assets")
+				}
+			}
+
+			err := n.getVault(ctx, client, address)
 			if err != nil {
 				n.log.WithError(err).WithFields(logrus.Fields{
 					LabelAddress:   address,
 					LabelExecution: client.Name(),
-				}).Error("Failed to get ERC4626 vault assets")
+				}).Error("Failed to get ERC4626 vault state")
 			}
 		}
 	}
@@ -160,62 +224,156 @@ func (n *ERC4626) getAssets(ctx context.Context, client api.ExecutionClient, add
 	defer func() {
 		if err != nil {
 			n.ERC4626Error.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Inc()
+			n.snapshots.SetError(NameERC4626, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
-	// Step 1: Call balanceOf(address) on the vault contract to get shares balance
-	// Function selector for balanceOf(address) is 0x70a08231
-	balanceOfData := "0x70a08231000000000000000000000000" + address.Address[2:]
+	asset, err := n.tokens.VaultAsset(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
 
-	sharesStr, err := client.ETHCall(ctx, &api.ETHCallTransaction{
-		To:   address.Contract,
-		Data: &balanceOfData,
-	}, "latest")
+	symbol, err = n.tokens.Symbol(ctx, client, asset)
 	if err != nil {
 		return err
 	}
 
-	// Extract the shares value (remove 0x prefix and ensure proper padding)
-	shares := strings.TrimPrefix(sharesStr, "0x")
-	if shares == "" {
-		shares = "0"
+	decimals, err := n.tokens.Decimals(ctx, client, asset)
+	if err != nil {
+		return err
 	}
 
-	// Ensure shares is properly padded to 32 bytes (64 hex chars)
-	if len(shares) < 64 {
-		shares = fmt.Sprintf("%064s", shares)
+	holder, err := encodeAddressArgument(address.Address)
+	if err != nil {
+		return err
 	}
 
-	// Step 2: Call convertToAssets(uint256 shares) on the vault contract
-	// Function selector for convertToAssets(uint256) is 0x07a2d13a
-	convertToAssetsData := "0x07a2d13a" + shares
+	shares, err := ethCallUint256(ctx, client, address.Contract, erc4626BalanceOfSelector+holder)
+	if err != nil {
+		return err
+	}
 
-	assetsStr, err := client.ETHCall(ctx, &api.ETHCallTransaction{
-		To:   address.Contract,
-		Data: &convertToAssetsData,
-	}, "latest")
+	assetsAmount, err := ethCallUint256(ctx, client, address.Contract, erc4626ConvertToAssetsSelector+formatABIWord(shares))
 	if err != nil {
 		return err
 	}
 
-	// Step 3: Call symbol() to get the vault token symbol
-	// Function selector for symbol() is 0x95d89b41
-	symbolData := "0x95d89b41000000000000000000000000"
+	assets := tokenAmountToFloat64(assetsAmount, decimals)
+	n.ERC4626Assets.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Set(assets)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameERC4626,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Symbol:    symbol,
+		Key:       "assets",
+		Value:     assets,
+	}, time.Now())
+
+	return nil
+}
+
+// getVault exports vault-level metrics, and the holder's withdrawable assets and
+// redeemable shares when a holder address is configured. Values in assets are
+// labelled with the underlying asset symbol and values in shares with the
+// vault share symbol.
+func (n *ERC4626) getVault(ctx context.Context, client api.ExecutionClient, address *AddressERC4626) error {
+	var err error
+
+	symbol := ""
 
-	symbolHex, err := client.ETHCall(ctx, &api.ETHCallTransaction{
-		To:   address.Contract,
-		Data: &symbolData,
-	}, "latest")
+	defer func() {
+		if err != nil {
+			n.ERC4626Error.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Inc()
+			n.snapshots.SetError(NameERC4626, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	asset, err := n.tokens.VaultAsset(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	symbol, err = n.tokens.Symbol(ctx, client, asset)
+	if err != nil {
+		return err
+	}
+
+	assetDecimals, err := n.tokens.Decimals(ctx, client, asset)
+	if err != nil {
+		return err
+	}
+
+	shareDecimals, err := n.tokens.Decimals(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	shareSymbol, err := n.tokens.Symbol(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	totalAssets, err := ethCallUint256(ctx, client, address.Contract, erc4626TotalAssetsSelector)
+	if err != nil {
+		return err
+	}
+
+	totalSupply, err := ethCallUint256(ctx, client, address.Contract, erc20TotalSupplySelector)
 	if err != nil {
 		return err
 	}
 
-	symbol, err = hexStringToString(symbolHex)
+	oneShare := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shareDecimals)), nil)
+
+	pricePerShare, err := ethCallUint256(ctx, client, address.Contract, erc4626ConvertToAssetsSelector+formatABIWord(oneShare))
 	if err != nil {
 		return err
 	}
 
-	n.ERC4626Assets.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Set(hexStringToFloat64(assetsStr))
+	labels := n.getLabelValues(address, symbol, client.Name())
+	shareLabels := n.getLabelValues(address, shareSymbol, client.Name())
+
+	if address.Address != "" {
+		var (
+			holder                 string
+			maxWithdraw, maxRedeem *big.Int
+		)
+
+		holder, err = encodeAddressArgument(address.Address)
+		if err != nil {
+			return err
+		}
+
+		maxWithdraw, err = ethCallUint256(ctx, client, address.Contract, erc4626MaxWithdrawSelector+holder)
+		if err != nil {
+			return err
+		}
+
+		maxRedeem, err = ethCallUint256(ctx, client, address.Contract, erc4626MaxRedeemSelector+holder)
+		if err != nil {
+			return err
+		}
+
+		n.ERC4626MaxWithdraw.WithLabelValues(labels...).Set(tokenAmountToFloat64(maxWithdraw, assetDecimals))
+		n.ERC4626MaxRedeem.WithLabelValues(shareLabels...).Set(tokenAmountToFloat64(maxRedeem, shareDecimals))
+	}
+
+	totalAssetsValue := tokenAmountToFloat64(totalAssets, assetDecimals)
+	n.ERC4626TotalAssets.WithLabelValues(labels...).Set(totalAssetsValue)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameERC4626,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Symbol:    symbol,
+		Key:       "total_assets",
+		Value:     totalAssetsValue,
+	}, time.Now())
+	n.ERC4626TotalSupply.WithLabelValues(shareLabels...).Set(tokenAmountToFloat64(totalSupply, shareDecimals))
+	n.ERC4626PricePerShare.WithLabelValues(labels...).Set(tokenAmountToFloat64(pricePerShare, assetDecimals))
 
 	return nil
 }
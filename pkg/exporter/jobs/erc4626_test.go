@@ -2,13 +2,26 @@ package jobs
 
 import (
 	"context"
+	"math/big"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
 )
 
+func newTestERC4626VaultResponses() map[string]string {
+	return map[string]string{
+		erc4626AssetSelector:       encodeABIAddressReturn(testUSDCContract),
+		erc4626TotalAssetsSelector: encodeABIUintReturn(2000000),
+		erc20TotalSupplySelector:   encodeABIUintReturn(1000000),
+		erc4626MaxWithdrawSelector: encodeABIUintReturn(500000),
+		erc4626MaxRedeemSelector:   encodeABIUintReturn(250000),
+	}
+}
+
 //nolint:gocognit,funlen // table-driven test with detailed call verification
 func TestERC4626_getAssets(t *testing.T) {
 	tests := []struct {
@@ -52,8 +65,8 @@ func TestERC4626_getAssets(t *testing.T) {
 				Contract: testERC4626Vault,
 				Labels:   map[string]string{},
 			},
-			balanceOfResponse:       "0x0000000000000000000000000000000000000000000000056bc75e2d63100000",   // 1e20
-			convertToAssetsResponse: "0x0000000000000000000000000000000000000000000000152d02c7e14af6800000", // large number
+			balanceOfResponse:       "0x0000000000000000000000000000000000000000000000056bc75e2d63100000", // 1e20
+			convertToAssetsResponse: "0x00000000000000000000000000000000000000000000152d02c7e14af6800000", // 1e23
 			wantError:               false,
 		},
 	}
@@ -63,7 +76,9 @@ func TestERC4626_getAssets(t *testing.T) {
 			mockClient := &mockExecutionClient{
 				balanceOfResponse:       tt.balanceOfResponse,
 				convertToAssetsResponse: tt.convertToAssetsResponse,
-				symbolResponse:          testABISymbolSUSDCResponse, // "sUSDC-Vault"
+				symbolResponse:          testABISymbolUSDCResponse,
+				decimalsResponse:        encodeABIUintReturn(6),
+				ethCallResponses:        map[string]string{erc4626AssetSelector: encodeABIAddressReturn(testUSDCContract)},
 				balanceOfError:          tt.balanceOfError,
 				convertToAssetsError:    tt.convertToAssetsError,
 				callLog:                 []mockCall{},
@@ -82,6 +97,9 @@ func TestERC4626_getAssets(t *testing.T) {
 				namespace,
 				map[string]string{},
 				[]*AddressERC4626{tt.address},
+				NewTokenMetadataCache(),
+				nil,
+				testRegistry(),
 			)
 
 			err := erc4626.getAssets(context.Background(), mockClient, tt.address)
@@ -90,46 +108,29 @@ func TestERC4626_getAssets(t *testing.T) {
 				t.Errorf("getAssets() error = %v, wantError %v", err, tt.wantError)
 			}
 
-			// Verify that all three calls were made in the correct order
-			if len(mockClient.callLog) != 3 {
-				t.Errorf("Expected 3 RPC calls (balanceOf + convertToAssets + symbol), got %d", len(mockClient.callLog))
-			}
-
-			// Verify first call was balanceOf
-			if len(mockClient.callLog) > 0 {
-				firstCall := mockClient.callLog[0]
-				if firstCall.to != tt.address.Contract {
-					t.Errorf("First call should be to contract %s, got %s", tt.address.Contract, firstCall.to)
-				}
-
-				if len(firstCall.data) < 10 || firstCall.data[:10] != "0x70a08231" {
-					t.Errorf("First call should be balanceOf (0x70a08231), got %s", firstCall.data[:10])
-				}
+			// The asset, its symbol and decimals are resolved before the holder's
+			// shares are converted to assets.
+			wantCalls := []mockCall{
+				{to: tt.address.Contract, data: erc4626AssetSelector},
+				{to: testUSDCContract, data: tokenSymbolSelector},
+				{to: testUSDCContract, data: tokenDecimalsSelector},
+				{to: tt.address.Contract, data: erc4626BalanceOfSelector},
+				{to: tt.address.Contract, data: erc4626ConvertToAssetsSelector},
 			}
 
-			// Verify second call was convertToAssets
-			if len(mockClient.callLog) > 1 {
-				secondCall := mockClient.callLog[1]
-				if secondCall.to != tt.address.Contract {
-					t.Errorf("Second call should be to contract %s, got %s", tt.address.Contract, secondCall.to)
-				}
+			require.Len(t, mockClient.callLog, len(wantCalls))
 
-				if len(secondCall.data) < 10 || secondCall.data[:10] != "0x07a2d13a" {
-					t.Errorf("Second call should be convertToAssets (0x07a2d13a), got %s", secondCall.data[:10])
-				}
+			for i, want := range wantCalls {
+				call := mockClient.callLog[i]
+				require.True(t, strings.EqualFold(want.to, call.to), "call %d to %s, want %s", i, call.to, want.to)
+				require.Equal(t, want.data, call.data[:10], "call %d", i)
 			}
 
-			// Verify third call was symbol
-			if len(mockClient.callLog) > 2 {
-				thirdCall := mockClient.callLog[2]
-				if thirdCall.to != tt.address.Contract {
-					t.Errorf("Third call should be to contract %s, got %s", tt.address.Contract, thirdCall.to)
-				}
+			assets, ok := new(big.Int).SetString(tt.convertToAssetsResponse[2:], 16)
+			require.True(t, ok)
 
-				if len(thirdCall.data) < 10 || thirdCall.data[:10] != "0x95d89b41" {
-					t.Errorf("Third call should be symbol (0x95d89b41), got %s", thirdCall.data[:10])
-				}
-			}
+			labels := erc4626.getLabelValues(tt.address, "USDC", testMockNodeName)
+			assertMetricValue(t, erc4626.ERC4626Assets, labels, tokenAmountToFloat64(assets, 6))
 		})
 	}
 }
@@ -139,7 +140,9 @@ func TestERC4626_tick(t *testing.T) {
 		balanceOfResponse:       "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000",
 		convertToAssetsResponse: "0x00000000000000000000000000000000000000000000000000038d7ea4c68000",
 		symbolResponse:          testABISymbolSUSDCResponse, // "sUSDC-Vault"
+		decimalsResponse:        encodeABIUintReturn(18),
 		callLog:                 []mockCall{},
+		ethCallResponses:        newTestERC4626VaultResponses(),
 	}
 
 	log := logrus.New()
@@ -167,13 +170,18 @@ func TestERC4626_tick(t *testing.T) {
 		"test_tick",
 		map[string]string{},
 		addresses,
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
 	erc4626.tick(ctx)
 
-	// Verify that tick called getAssets for each address (1 client * 2 addresses * 3 calls each = 6 total)
-	expectedCalls := len(addresses) * 3
+	// Each address makes 2 holder calls and 5 vault calls, and the first address
+	// also fetches the cached asset address, asset symbol and decimals and share
+	// decimals and symbol.
+	expectedCalls := len(addresses)*7 + 5
 	if len(mockClient.callLog) != expectedCalls {
 		t.Errorf("Expected %d RPC calls for %d addresses, got %d", expectedCalls, len(addresses), len(mockClient.callLog))
 	}
@@ -204,6 +212,9 @@ func TestERC4626_getLabelValues(t *testing.T) {
 		"test_labels",
 		map[string]string{},
 		addresses,
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
 	)
 
 	labels := erc4626.getLabelValues(addresses[0], "sUSDC-Vault", "mock-node")
@@ -260,3 +271,73 @@ func TestERC4626_Name(t *testing.T) {
 		t.Errorf("Expected name %s, got %s", NameERC4626, erc4626.Name())
 	}
 }
+
+func TestERC4626_getVault(t *testing.T) {
+	address := &AddressERC4626{
+		Name:     "Vault",
+		Address:  testHolder1Address,
+		Contract: testERC4626Vault,
+		Labels:   map[string]string{},
+	}
+
+	responses := newTestERC4626VaultResponses()
+	responses[erc4626ConvertToAssetsSelector+formatABIWord(big.NewInt(1000000))] = encodeABIUintReturn(1050000)
+
+	mockClient := &mockExecutionClient{
+		symbolResponse:   testABISymbolUSDCResponse,
+		decimalsResponse: encodeABIUintReturn(6),
+		ethCallResponses: responses,
+	}
+
+	erc4626 := NewERC4626(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc4626_vault",
+		map[string]string{},
+		[]*AddressERC4626{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
+	)
+
+	// The mock answers symbol() alike for every contract, so the share symbol is seeded.
+	erc4626.tokens.symbols[newTokenMetadataKey(mockClient, testERC4626Vault)] = "sUSDC"
+
+	err := erc4626.getVault(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	labels := erc4626.getLabelValues(address, testNameUSDC, testMockNodeName)
+	shareLabels := erc4626.getLabelValues(address, "sUSDC", testMockNodeName)
+
+	assertMetricValue(t, erc4626.ERC4626TotalAssets, labels, 2)
+	assertMetricValue(t, erc4626.ERC4626TotalSupply, shareLabels, 1)
+	assertMetricValue(t, erc4626.ERC4626PricePerShare, labels, 1.05)
+	assertMetricValue(t, erc4626.ERC4626MaxWithdraw, labels, 0.5)
+	assertMetricValue(t, erc4626.ERC4626MaxRedeem, shareLabels, 0.25)
+
+	assertCallSelectors(t, mockClient.callLog, []string{
+		erc4626AssetSelector,
+		tokenSymbolSelector,
+		tokenDecimalsSelector,
+		tokenDecimalsSelector,
+		erc4626TotalAssetsSelector,
+		erc20TotalSupplySelector,
+		erc4626ConvertToAssetsSelector,
+		erc4626MaxWithdrawSelector,
+		erc4626MaxRedeemSelector,
+	})
+
+	// Vault-only entries skip the holder calls.
+	mockClient.callLog = nil
+	vaultOnly := &AddressERC4626{Name: "Vault only", Contract: testERC4626Vault}
+
+	err = erc4626.getVault(context.Background(), mockClient, vaultOnly)
+	require.NoError(t, err)
+
+	assertCallSelectors(t, mockClient.callLog, []string{
+		erc4626TotalAssetsSelector,
+		erc20TotalSupplySelector,
+		erc4626ConvertToAssetsSelector,
+	})
+}
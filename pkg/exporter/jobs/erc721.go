@@ -2,30 +2,52 @@ package jobs
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // ERC721 exposes metrics for ethereum ERC721 contract by address.
 type ERC721 struct {
+	*tickTracker
+
 	clients       []api.ExecutionClient
 	log           logrus.FieldLogger
 	ERC721Balance prometheus.GaugeVec
+	ERC721Owned   prometheus.GaugeVec
+	ERC721Held    prometheus.GaugeVec
 	ERC721Error   prometheus.CounterVec
 	checkInterval time.Duration
 	addresses     []*AddressERC721
 	labelsMap     map[string]int
+	// enumerable caches whether each contract supports ERC721Enumerable per execution client.
+	enumerable map[tokenMetadataKey]bool
+	// held holds the last enumerated token IDs per execution client and address name.
+	held      map[erc721StateKey][]string
+	snapshots *snapshot.Store
 }
 
 type AddressERC721 struct {
-	Address  string            `yaml:"address"`
-	Contract string            `yaml:"contract"`
-	Name     string            `yaml:"name"`
-	Labels   map[string]string `yaml:"labels"`
+	Address   string            `yaml:"address"`
+	Contract  string            `yaml:"contract"`
+	TokenIDs  []*big.Int        `yaml:"tokenIds"`
+	Enumerate bool              `yaml:"enumerate"`
+	Name      string            `yaml:"name"`
+	Labels    map[string]string `yaml:"labels"`
+}
+
+type erc721StateKey struct {
+	execution string
+	name      string
 }
 
 // GetName returns the configured name of this address.
@@ -33,6 +55,15 @@ func (a *AddressERC721) GetName() string { return a.Name }
 
 const (
 	NameERC721 = "erc721"
+
+	erc721BalanceOfSelector           = "0x70a08231"
+	erc721OwnerOfSelector             = "0x6352211e"
+	erc721SupportsInterfaceSelector   = "0x01ffc9a7"
+	erc721TokenOfOwnerByIndexSelector = "0x2f745c59"
+	// erc721EnumerableInterfaceID is the ERC165 interface ID of ERC721Enumerable.
+	erc721EnumerableInterfaceID = "0x780e9d63"
+	// erc721MaxEnumeratedTokens bounds the tokenOfOwnerByIndex calls made per address and tick.
+	erc721MaxEnumeratedTokens = 256
 )
 
 func (n *ERC721) Name() string {
@@ -40,7 +71,7 @@ func (n *ERC721) Name() string {
 }
 
 // NewERC721 returns a new ERC721 instance.
-func NewERC721(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC721) ERC721 {
+func NewERC721(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressERC721, snapshots *snapshot.Store, registerer prometheus.Registerer) ERC721 {
 	namespace += "_" + NameERC721
 
 	labelsMap := map[string]int{
@@ -63,12 +94,18 @@ func NewERC721(clients []api.ExecutionClient, log logrus.FieldLogger, checkInter
 		labels[index] = label
 	}
 
+	tokenLabels := append(append([]string{}, labels...), LabelTokenID)
+
 	instance := ERC721{
 		clients:       clients,
 		log:           log.WithField("module", NameERC721),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
+		enumerable:    make(map[tokenMetadataKey]bool),
+		held:          make(map[erc721StateKey][]string),
 		ERC721Balance: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace:   namespace,
@@ -78,6 +115,24 @@ func NewERC721(clients []api.ExecutionClient, log logrus.FieldLogger, checkInter
 			},
 			labels,
 		),
+		ERC721Owned: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "owned",
+				Help:        "Whether a configured token ID of a ethereum ERC721 contract is owned by address.",
+				ConstLabels: constLabels,
+			},
+			tokenLabels,
+		),
+		ERC721Held: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "held_token_info",
+				Help:        "A token ID of a ethereum ERC721Enumerable contract held by address.",
+				ConstLabels: constLabels,
+			},
+			tokenLabels,
+		),
 		ERC721Error: *prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace:   namespace,
@@ -89,14 +144,17 @@ func NewERC721(clients []api.ExecutionClient, log logrus.FieldLogger, checkInter
 		),
 	}
 
-	prometheus.MustRegister(instance.ERC721Balance)
-	prometheus.MustRegister(instance.ERC721Error)
+	registerer.MustRegister(instance.ERC721Balance)
+	registerer.MustRegister(instance.ERC721Owned)
+	registerer.MustRegister(instance.ERC721Held)
+	registerer.MustRegister(instance.ERC721Error)
 
 	return instance
 }
 
 func (n *ERC721) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -104,6 +162,7 @@ func (n *ERC721) Start(ctx context.Context) {
 			return
 		case <-time.After(n.checkInterval):
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
@@ -118,6 +177,26 @@ func (n *ERC721) tick(ctx context.Context) {
 					LabelExecution: client.Name(),
 				}).Error("Failed to get erc721 contract balanceOf address")
 			}
+
+			if len(address.TokenIDs) > 0 {
+				err = n.getOwnership(ctx, client, address)
+				if err != nil {
+					n.log.WithError(err).WithFields(logrus.Fields{
+						LabelAddress:   address,
+						LabelExecution: client.Name(),
+					}).Error("Failed to get erc721 contract ownerOf token ids")
+				}
+			}
+
+			if address.Enumerate {
+				err = n.getHeldTokens(ctx, client, address)
+				if err != nil {
+					n.log.WithError(err).WithFields(logrus.Fields{
+						LabelAddress:   address,
+						LabelExecution: client.Name(),
+					}).Error("Failed to get erc721 contract tokenOfOwnerByIndex address")
+				}
+			}
 		}
 	}
 }
@@ -153,6 +232,7 @@ func (n *ERC721) getBalance(ctx context.Context, client api.ExecutionClient, add
 	defer func() {
 		if err != nil {
 			n.ERC721Error.WithLabelValues(n.getLabelValues(address, client.Name())...).Inc()
+			n.snapshots.SetError(NameERC721, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
@@ -167,7 +247,161 @@ func (n *ERC721) getBalance(ctx context.Context, client api.ExecutionClient, add
 		return err
 	}
 
-	n.ERC721Balance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(hexStringToFloat64(balanceStr))
+	balance := hexStringToFloat64(balanceStr)
+	n.ERC721Balance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(balance)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameERC721,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Value:     balance,
+	}, time.Now())
+
+	return nil
+}
+
+// getOwnership exports whether the address owns each configured token ID. A
+// failing ownerOf call only drops the series of that token, so one burned or
+// invalid token ID does not hide the ownership of the others.
+func (n *ERC721) getOwnership(ctx context.Context, client api.ExecutionClient, address *AddressERC721) error {
+	var errs []error
+
+	labels := n.getLabelValues(address, client.Name())
+
+	for _, tokenID := range address.TokenIDs {
+		tokenLabels := append(append([]string{}, labels...), tokenID.String())
+
+		owner, err := ethCallAddress(ctx, client, address.Contract, erc721OwnerOfSelector+formatABIWord(tokenID))
+		if err != nil {
+			err = fmt.Errorf("failed to get owner of token %s: %w", tokenID, err)
+
+			n.ERC721Owned.DeleteLabelValues(tokenLabels...)
+			n.ERC721Error.WithLabelValues(labels...).Inc()
+			n.snapshots.SetError(NameERC721, address.Name, client.Name(), err, time.Now())
+
+			errs = append(errs, err)
+
+			continue
+		}
+
+		owned := 0.0
+		if strings.EqualFold(owner, address.Address) {
+			owned = 1
+		}
+
+		n.ERC721Owned.WithLabelValues(tokenLabels...).Set(owned)
+	}
+
+	return errors.Join(errs...)
+}
+
+// getHeldTokens exports the token IDs held by the address, removing series for
+// tokens that are no longer held. Contracts without ERC721Enumerable support
+// are skipped.
+func (n *ERC721) getHeldTokens(ctx context.Context, client api.ExecutionClient, address *AddressERC721) error {
+	var err error
+
+	labels := n.getLabelValues(address, client.Name())
+
+	defer func() {
+		if err != nil {
+			n.ERC721Error.WithLabelValues(labels...).Inc()
+			n.snapshots.SetError(NameERC721, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	supported, err := n.supportsEnumerable(ctx, client, address.Contract)
+	if err != nil {
+		return fmt.Errorf("failed to check erc721 enumerable support: %w", err)
+	}
+
+	if !supported {
+		return nil
+	}
+
+	balanceOfData, err := encodeAddressCall(erc721BalanceOfSelector, address.Address)
+	if err != nil {
+		return err
+	}
+
+	balanceData, err := ethCallABI(ctx, client, address.Contract, balanceOfData)
+	if err != nil {
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	balance, err := decodeABIWordAsBigInt(balanceData, 0)
+	if err != nil {
+		return fmt.Errorf("failed to decode balance: %w", err)
+	}
+
+	count := erc721MaxEnumeratedTokens
+	if balance.Cmp(big.NewInt(erc721MaxEnumeratedTokens)) <= 0 {
+		count = int(balance.Int64())
+	} else {
+		n.log.WithFields(logrus.Fields{
+			LabelName:      address.Name,
+			LabelExecution: client.Name(),
+		}).Warnf("Only enumerating the first %d of %s erc721 tokens", count, balance)
+	}
+
+	tokenIDs := make([]string, 0, count)
+
+	for i := 0; i < count; i++ {
+		var data []byte
+
+		data, err = ethCallABI(ctx, client, address.Contract, erc721TokenOfOwnerByIndexSelector+balanceOfData[len(erc721BalanceOfSelector):]+formatABIWord(big.NewInt(int64(i))))
+		if err != nil {
+			return fmt.Errorf("failed to get token of owner by index %d: %w", i, err)
+		}
+
+		var tokenID *big.Int
+
+		tokenID, err = decodeABIWordAsBigInt(data, 0)
+		if err != nil {
+			return fmt.Errorf("failed to decode token of owner by index %d: %w", i, err)
+		}
+
+		tokenIDs = append(tokenIDs, tokenID.String())
+	}
+
+	key := erc721StateKey{execution: client.Name(), name: address.Name}
+
+	for _, tokenID := range n.held[key] {
+		if !slices.Contains(tokenIDs, tokenID) {
+			n.ERC721Held.DeleteLabelValues(append(append([]string{}, labels...), tokenID)...)
+		}
+	}
+
+	n.held[key] = tokenIDs
+
+	for _, tokenID := range tokenIDs {
+		n.ERC721Held.WithLabelValues(append(append([]string{}, labels...), tokenID)...).Set(1)
+	}
 
 	return nil
 }
+
+// supportsEnumerable checks ERC165 support for ERC721Enumerable, caching the
+// result per execution client and contract.
+func (n *ERC721) supportsEnumerable(ctx context.Context, client api.ExecutionClient, contract string) (bool, error) {
+	key := newTokenMetadataKey(client, contract)
+
+	if supported, ok := n.enumerable[key]; ok {
+		return supported, nil
+	}
+
+	data, err := ethCallABI(ctx, client, contract, erc721SupportsInterfaceSelector+strings.TrimPrefix(erc721EnumerableInterfaceID, "0x")+strings.Repeat("0", 56))
+	if err != nil {
+		return false, err
+	}
+
+	supported, err := decodeABIWordAsBool(data, 0)
+	if err != nil {
+		return false, err
+	}
+
+	n.enumerable[key] = supported
+
+	return supported, nil
+}
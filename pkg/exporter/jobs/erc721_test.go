@@ -2,11 +2,16 @@ package jobs
 
 import (
 	"context"
+	"math/big"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestERC721_getBalance(t *testing.T) {
@@ -69,6 +74,8 @@ func TestERC721_getBalance(t *testing.T) {
 				namespace,
 				map[string]string{},
 				[]*AddressERC721{tt.address},
+				nil,
+				testRegistry(),
 			)
 
 			err := erc721.getBalance(context.Background(), mockClient, tt.address)
@@ -120,6 +127,8 @@ func TestERC721_tick(t *testing.T) {
 		"test_erc721_tick",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
@@ -132,6 +141,175 @@ func TestERC721_tick(t *testing.T) {
 	}
 }
 
+func TestERC721_getOwnership(t *testing.T) {
+	address := &AddressERC721{
+		Name:     "ENS Names",
+		Address:  testHolder1Address,
+		Contract: testContractAAddress,
+		TokenIDs: []*big.Int{big.NewInt(1), big.NewInt(2)},
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			erc721OwnerOfSelector + formatABIWord(big.NewInt(1)): encodeABIAddressReturn(testHolder1Address),
+			erc721OwnerOfSelector + formatABIWord(big.NewInt(2)): encodeABIAddressReturn(testHolder2Address),
+		},
+	}
+
+	erc721 := NewERC721(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc721_ownership",
+		map[string]string{},
+		[]*AddressERC721{address},
+		nil,
+		testRegistry(),
+	)
+
+	err := erc721.getOwnership(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	labels := erc721.getLabelValues(address, testMockNodeName)
+
+	assertMetricValue(t, erc721.ERC721Owned, append(append([]string{}, labels...), "1"), 1)
+	assertMetricValue(t, erc721.ERC721Owned, append(append([]string{}, labels...), "2"), 0)
+	assertCallSelectors(t, mockClient.callLog, []string{erc721OwnerOfSelector, erc721OwnerOfSelector})
+}
+
+func TestERC721_getOwnership_TokenError(t *testing.T) {
+	address := &AddressERC721{
+		Name:     "ENS Names",
+		Address:  testHolder1Address,
+		Contract: testContractAAddress,
+		TokenIDs: []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)},
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			erc721OwnerOfSelector + formatABIWord(big.NewInt(1)): "0xzz",
+			erc721OwnerOfSelector + formatABIWord(big.NewInt(2)): encodeABIAddressReturn(testHolder1Address),
+			erc721OwnerOfSelector + formatABIWord(big.NewInt(3)): encodeABIAddressReturn(testHolder2Address),
+		},
+	}
+
+	erc721 := NewERC721(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc721_ownership_error",
+		map[string]string{},
+		[]*AddressERC721{address},
+		nil,
+		testRegistry(),
+	)
+
+	labels := erc721.getLabelValues(address, testMockNodeName)
+
+	// A previously exported value must not outlive a failing call.
+	erc721.ERC721Owned.WithLabelValues(append(append([]string{}, labels...), "1")...).Set(1)
+
+	err := erc721.getOwnership(context.Background(), mockClient, address)
+	require.Error(t, err)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(&erc721.ERC721Owned))
+	assertMetricValue(t, erc721.ERC721Owned, append(append([]string{}, labels...), "2"), 1)
+	assertMetricValue(t, erc721.ERC721Owned, append(append([]string{}, labels...), "3"), 0)
+	assert.Equal(t, float64(1), testutil.ToFloat64(erc721.ERC721Error.WithLabelValues(labels...)))
+	assertCallSelectors(t, mockClient.callLog, []string{erc721OwnerOfSelector, erc721OwnerOfSelector, erc721OwnerOfSelector})
+}
+
+func TestERC721_getHeldTokens(t *testing.T) {
+	address := &AddressERC721{
+		Name:      "Positions",
+		Address:   testHolder1Address,
+		Contract:  testContractAAddress,
+		Enumerate: true,
+		Labels:    map[string]string{},
+	}
+
+	owner, err := encodeAddressArgument(testHolder1Address)
+	require.NoError(t, err)
+
+	tokenOfOwnerByIndex := func(index int64) string {
+		return erc721TokenOfOwnerByIndexSelector + owner + formatABIWord(big.NewInt(index))
+	}
+
+	mockClient := &mockExecutionClient{
+		balanceOfResponse: encodeABIUintReturn(2),
+		ethCallResponses: map[string]string{
+			erc721SupportsInterfaceSelector: encodeABIUintReturn(1),
+			tokenOfOwnerByIndex(0):          encodeABIUintReturn(7),
+			tokenOfOwnerByIndex(1):          encodeABIUintReturn(9),
+		},
+	}
+
+	erc721 := NewERC721(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc721_held",
+		map[string]string{},
+		[]*AddressERC721{address},
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, erc721.getHeldTokens(context.Background(), mockClient, address))
+
+	labels := erc721.getLabelValues(address, testMockNodeName)
+
+	assertMetricValue(t, erc721.ERC721Held, append(append([]string{}, labels...), "7"), 1)
+	assertMetricValue(t, erc721.ERC721Held, append(append([]string{}, labels...), "9"), 1)
+	assert.Equal(t, erc721SupportsInterfaceSelector+"780e9d63"+strings.Repeat("0", 56), mockClient.callLog[0].data)
+
+	// Token 7 is transferred away, and support is not checked again.
+	mockClient.balanceOfResponse = encodeABIUintReturn(1)
+	mockClient.ethCallResponses[tokenOfOwnerByIndex(0)] = encodeABIUintReturn(9)
+	mockClient.callLog = nil
+
+	require.NoError(t, erc721.getHeldTokens(context.Background(), mockClient, address))
+
+	assert.Equal(t, 1, testutil.CollectAndCount(&erc721.ERC721Held))
+	assertMetricValue(t, erc721.ERC721Held, append(append([]string{}, labels...), "9"), 1)
+	assertCallSelectors(t, mockClient.callLog, []string{erc721BalanceOfSelector, erc721TokenOfOwnerByIndexSelector})
+}
+
+func TestERC721_getHeldTokens_NotEnumerable(t *testing.T) {
+	address := &AddressERC721{
+		Name:      "Positions",
+		Address:   testHolder1Address,
+		Contract:  testContractAAddress,
+		Enumerate: true,
+		Labels:    map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			erc721SupportsInterfaceSelector: encodeABIUintReturn(0),
+		},
+	}
+
+	erc721 := NewERC721(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"erc721_not_enumerable",
+		map[string]string{},
+		[]*AddressERC721{address},
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, erc721.getHeldTokens(context.Background(), mockClient, address))
+	require.NoError(t, erc721.getHeldTokens(context.Background(), mockClient, address))
+
+	assert.Equal(t, 0, testutil.CollectAndCount(&erc721.ERC721Held))
+	assertCallSelectors(t, mockClient.callLog, []string{erc721SupportsInterfaceSelector})
+}
+
 func TestERC721_getLabelValues(t *testing.T) {
 	log := logrus.New()
 	log.SetLevel(logrus.ErrorLevel)
@@ -154,6 +332,8 @@ func TestERC721_getLabelValues(t *testing.T) {
 		"test_erc721_labels",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	labels := erc721.getLabelValues(addresses[0], "mock-node")
@@ -0,0 +1,14 @@
+package jobs
+
+import "golang.org/x/crypto/sha3"
+
+// keccak256 returns the legacy Keccak-256 hash used by Ethereum.
+func keccak256(data ...[]byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+
+	for _, d := range data {
+		hasher.Write(d)
+	}
+
+	return hasher.Sum(nil)
+}
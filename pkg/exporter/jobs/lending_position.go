@@ -0,0 +1,323 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameLendingPosition = "lending_position"
+
+	lendingPositionGetUserAccountDataSelector = "0xbf92857c"
+	lendingPositionAddressesProviderSelector  = "0x0542975c"
+	lendingPositionGetPriceOracleSelector     = "0xfca513a8"
+	lendingPositionBaseCurrencyUnitSelector   = "0x8c89b64f"
+
+	// lendingPositionPercentageDecimals is the precision of the LTV and liquidation threshold in basis points.
+	lendingPositionPercentageDecimals = 4
+	// lendingPositionHealthFactorDecimals is the wad precision of the health factor.
+	lendingPositionHealthFactorDecimals = 18
+	// lendingPositionMaxBaseCurrencyDecimals bounds the decimals derived from BASE_CURRENCY_UNIT.
+	lendingPositionMaxBaseCurrencyDecimals = 77
+)
+
+// LendingPosition exposes metrics for Aave v3-compatible lending pool positions.
+type LendingPosition struct {
+	*tickTracker
+
+	clients                             []api.ExecutionClient
+	log                                 logrus.FieldLogger
+	LendingPositionTotalCollateral      prometheus.GaugeVec
+	LendingPositionTotalDebt            prometheus.GaugeVec
+	LendingPositionAvailableBorrows     prometheus.GaugeVec
+	LendingPositionLTV                  prometheus.GaugeVec
+	LendingPositionLiquidationThreshold prometheus.GaugeVec
+	LendingPositionHealthFactor         prometheus.GaugeVec
+	LendingPositionError                prometheus.CounterVec
+	checkInterval                       time.Duration
+	addresses                           []*AddressLendingPosition
+	labelsMap                           map[string]int
+	// baseCurrencyDecimals caches the base currency decimals of each pool per execution client.
+	baseCurrencyDecimals map[tokenMetadataKey]int
+	snapshots            *snapshot.Store
+}
+
+type AddressLendingPosition struct {
+	Address  string `yaml:"address"`
+	Contract string `yaml:"contract"`
+	// BaseCurrencyDecimals overrides the decimals read from the pool's price oracle BASE_CURRENCY_UNIT.
+	BaseCurrencyDecimals *int              `yaml:"baseCurrencyDecimals"`
+	Name                 string            `yaml:"name"`
+	Labels               map[string]string `yaml:"labels"`
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressLendingPosition) GetName() string { return a.Name }
+
+func (n *LendingPosition) Name() string {
+	return NameLendingPosition
+}
+
+// NewLendingPosition returns a new LendingPosition instance.
+func NewLendingPosition(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressLendingPosition, snapshots *snapshot.Store, registerer prometheus.Registerer) LendingPosition {
+	namespace += "_" + NameLendingPosition
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelAddress:   1,
+		LabelContract:  2,
+		LabelExecution: 3,
+	}
+
+	for address := range addresses {
+		for label := range addresses[address].Labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	newGaugeVec := func(name, help string) prometheus.GaugeVec {
+		return *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        name,
+				Help:        help,
+				ConstLabels: constLabels,
+			},
+			labels,
+		)
+	}
+
+	instance := LendingPosition{
+		clients:              clients,
+		log:                  log.WithField("module", NameLendingPosition),
+		tickTracker:          newTickTracker(),
+		snapshots:            snapshots,
+		addresses:            addresses,
+		checkInterval:        checkInterval,
+		labelsMap:            labelsMap,
+		baseCurrencyDecimals: make(map[tokenMetadataKey]int),
+		LendingPositionTotalCollateral: newGaugeVec(
+			"total_collateral",
+			"The total collateral of a lending pool position in the pool's base currency.",
+		),
+		LendingPositionTotalDebt: newGaugeVec(
+			"total_debt",
+			"The total debt of a lending pool position in the pool's base currency.",
+		),
+		LendingPositionAvailableBorrows: newGaugeVec(
+			"available_borrows",
+			"The borrowing power left of a lending pool position in the pool's base currency.",
+		),
+		LendingPositionLTV: newGaugeVec(
+			"ltv",
+			"The loan to value ratio of a lending pool position.",
+		),
+		LendingPositionLiquidationThreshold: newGaugeVec(
+			"liquidation_threshold",
+			"The liquidation threshold ratio of a lending pool position.",
+		),
+		LendingPositionHealthFactor: newGaugeVec(
+			"health_factor",
+			"The health factor of a lending pool position, liquidatable below 1 and +Inf without debt.",
+		),
+		LendingPositionError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting the account data of a lending pool position.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.LendingPositionTotalCollateral)
+	registerer.MustRegister(instance.LendingPositionTotalDebt)
+	registerer.MustRegister(instance.LendingPositionAvailableBorrows)
+	registerer.MustRegister(instance.LendingPositionLTV)
+	registerer.MustRegister(instance.LendingPositionLiquidationThreshold)
+	registerer.MustRegister(instance.LendingPositionHealthFactor)
+	registerer.MustRegister(instance.LendingPositionError)
+
+	return instance
+}
+
+func (n *LendingPosition) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *LendingPosition) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		for _, address := range n.addresses {
+			err := n.getPosition(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get lending pool position")
+			}
+		}
+	}
+}
+
+func (n *LendingPosition) getLabelValues(address *AddressLendingPosition, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		if address.Labels != nil && address.Labels[label] != "" {
+			values[index] = address.Labels[label]
+		} else {
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelAddress:
+				values[index] = address.Address
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+func (n *LendingPosition) getPosition(ctx context.Context, client api.ExecutionClient, address *AddressLendingPosition) error {
+	var err error
+
+	labels := n.getLabelValues(address, client.Name())
+
+	defer func() {
+		if err != nil {
+			n.LendingPositionError.WithLabelValues(labels...).Inc()
+			n.snapshots.SetError(NameLendingPosition, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	decimals, err := n.getBaseCurrencyDecimals(ctx, client, address)
+	if err != nil {
+		return fmt.Errorf("failed to get base currency decimals: %w", err)
+	}
+
+	callData, err := encodeAddressCall(lendingPositionGetUserAccountDataSelector, address.Address)
+	if err != nil {
+		return err
+	}
+
+	data, err := ethCallABI(ctx, client, address.Contract, callData)
+	if err != nil {
+		return fmt.Errorf("failed to get user account data: %w", err)
+	}
+
+	// getUserAccountData returns (totalCollateralBase, totalDebtBase, availableBorrowsBase,
+	// currentLiquidationThreshold, ltv, healthFactor).
+	words := make([]*big.Int, 6)
+	for i := range words {
+		words[i], err = decodeABIWordAsBigInt(data, i*32)
+		if err != nil {
+			return fmt.Errorf("failed to decode user account data: %w", err)
+		}
+	}
+
+	healthFactor := math.Inf(1)
+	if words[1].Sign() != 0 {
+		healthFactor = tokenAmountToFloat64(words[5], lendingPositionHealthFactorDecimals)
+	}
+
+	n.LendingPositionTotalCollateral.WithLabelValues(labels...).Set(tokenAmountToFloat64(words[0], decimals))
+	n.LendingPositionTotalDebt.WithLabelValues(labels...).Set(tokenAmountToFloat64(words[1], decimals))
+	n.LendingPositionAvailableBorrows.WithLabelValues(labels...).Set(tokenAmountToFloat64(words[2], decimals))
+	n.LendingPositionLiquidationThreshold.WithLabelValues(labels...).Set(tokenAmountToFloat64(words[3], lendingPositionPercentageDecimals))
+	n.LendingPositionLTV.WithLabelValues(labels...).Set(tokenAmountToFloat64(words[4], lendingPositionPercentageDecimals))
+	n.LendingPositionHealthFactor.WithLabelValues(labels...).Set(healthFactor)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameLendingPosition,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Value:     healthFactor,
+	}, time.Now())
+
+	return nil
+}
+
+// getBaseCurrencyDecimals returns the configured base currency decimals, or
+// derives them from BASE_CURRENCY_UNIT of the pool's price oracle, caching the
+// result per execution client and pool.
+func (n *LendingPosition) getBaseCurrencyDecimals(ctx context.Context, client api.ExecutionClient, address *AddressLendingPosition) (int, error) {
+	if address.BaseCurrencyDecimals != nil {
+		return *address.BaseCurrencyDecimals, nil
+	}
+
+	key := newTokenMetadataKey(client, address.Contract)
+
+	if decimals, ok := n.baseCurrencyDecimals[key]; ok {
+		return decimals, nil
+	}
+
+	provider, err := ethCallAddress(ctx, client, address.Contract, lendingPositionAddressesProviderSelector)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get addresses provider: %w", err)
+	}
+
+	oracle, err := ethCallAddress(ctx, client, provider, lendingPositionGetPriceOracleSelector)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price oracle: %w", err)
+	}
+
+	data, err := ethCallABI(ctx, client, oracle, lendingPositionBaseCurrencyUnitSelector)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get base currency unit: %w", err)
+	}
+
+	unit, err := decodeABIWordAsBigInt(data, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	decimals := 0
+	ten := big.NewInt(10)
+
+	for power := big.NewInt(1); power.Cmp(unit) != 0; power.Mul(power, ten) {
+		if decimals == lendingPositionMaxBaseCurrencyDecimals {
+			return 0, fmt.Errorf("base currency unit %s is not a power of ten", unit)
+		}
+
+		decimals++
+	}
+
+	n.baseCurrencyDecimals[key] = decimals
+
+	return decimals, nil
+}
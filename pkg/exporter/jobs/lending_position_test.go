@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testLendingPositionProvider = "0x1111111111111111111111111111111111111111"
+	testLendingPositionOracle   = "0x2222222222222222222222222222222222222222"
+)
+
+func TestLendingPosition_getPosition(t *testing.T) {
+	healthFactor, _ := new(big.Int).SetString("1500000000000000000", 10)
+
+	address := &AddressLendingPosition{
+		Name:     "Treasury",
+		Address:  testHolder1Address,
+		Contract: testContractAAddress,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			lendingPositionAddressesProviderSelector: encodeABIAddressReturn(testLendingPositionProvider),
+			lendingPositionGetPriceOracleSelector:    encodeABIAddressReturn(testLendingPositionOracle),
+			lendingPositionBaseCurrencyUnitSelector:  encodeABIUintReturn(1e8),
+			lendingPositionGetUserAccountDataSelector: encodeABIWordsReturn(
+				big.NewInt(300000e8),
+				big.NewInt(150000e8),
+				big.NewInt(75000e8),
+				big.NewInt(8250),
+				big.NewInt(8000),
+				healthFactor,
+			),
+		},
+	}
+
+	lendingPosition := NewLendingPosition(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"lending_position_get",
+		map[string]string{},
+		[]*AddressLendingPosition{address},
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, lendingPosition.getPosition(context.Background(), mockClient, address))
+	require.NoError(t, lendingPosition.getPosition(context.Background(), mockClient, address))
+
+	labels := lendingPosition.getLabelValues(address, testMockNodeName)
+
+	assertMetricValue(t, lendingPosition.LendingPositionTotalCollateral, labels, 300000)
+	assertMetricValue(t, lendingPosition.LendingPositionTotalDebt, labels, 150000)
+	assertMetricValue(t, lendingPosition.LendingPositionAvailableBorrows, labels, 75000)
+	assertMetricValue(t, lendingPosition.LendingPositionLiquidationThreshold, labels, 0.825)
+	assertMetricValue(t, lendingPosition.LendingPositionLTV, labels, 0.8)
+	assertMetricValue(t, lendingPosition.LendingPositionHealthFactor, labels, 1.5)
+
+	// The base currency decimals are only discovered once.
+	assertCallSelectors(t, mockClient.callLog, []string{
+		lendingPositionAddressesProviderSelector,
+		lendingPositionGetPriceOracleSelector,
+		lendingPositionBaseCurrencyUnitSelector,
+		lendingPositionGetUserAccountDataSelector,
+		lendingPositionGetUserAccountDataSelector,
+	})
+	assert.Equal(t, testLendingPositionOracle, mockClient.callLog[2].to)
+}
+
+func TestLendingPosition_getPosition_NoDebt(t *testing.T) {
+	baseCurrencyDecimals := 8
+
+	address := &AddressLendingPosition{
+		Name:                 "Supplier",
+		Address:              testHolder1Address,
+		Contract:             testContractAAddress,
+		BaseCurrencyDecimals: &baseCurrencyDecimals,
+		Labels:               map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			lendingPositionGetUserAccountDataSelector: encodeABIWordsReturn(
+				big.NewInt(100e8),
+				big.NewInt(0),
+				big.NewInt(80e8),
+				big.NewInt(8250),
+				big.NewInt(8000),
+				new(big.Int).Sub(abiWordModulus, big.NewInt(1)),
+			),
+		},
+	}
+
+	lendingPosition := NewLendingPosition(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"lending_position_no_debt",
+		map[string]string{},
+		[]*AddressLendingPosition{address},
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, lendingPosition.getPosition(context.Background(), mockClient, address))
+
+	labels := lendingPosition.getLabelValues(address, testMockNodeName)
+
+	assert.True(t, math.IsInf(testutil.ToFloat64(lendingPosition.LendingPositionHealthFactor.WithLabelValues(labels...)), 1))
+	assertMetricValue(t, lendingPosition.LendingPositionTotalCollateral, labels, 100)
+	assertCallSelectors(t, mockClient.callLog, []string{lendingPositionGetUserAccountDataSelector})
+}
+
+func TestLendingPosition_Name(t *testing.T) {
+	lendingPosition := &LendingPosition{}
+	if lendingPosition.Name() != NameLendingPosition {
+		t.Errorf("Expected name %s, got %s", NameLendingPosition, lendingPosition.Name())
+	}
+}
@@ -2,47 +2,66 @@ package jobs
 
 import (
 	"context"
-	"encoding/hex"
-	"errors"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 const (
 	NameLidoWithdrawalQueueERC721 = "lido_withdrawal_queue_erc721"
 
 	lidoWithdrawalQueueUnderlyingTokenSelector        = "0xe00bfe50" //nolint:gosec // Ethereum ABI selector, not a credential.
-	lidoWithdrawalQueueSymbolSelector                 = "0x95d89b41"
-	lidoWithdrawalQueueDecimalsSelector               = "0x313ce567"
 	lidoWithdrawalQueueGetWithdrawalRequestsSelector  = "0x7d031b65"
 	lidoWithdrawalQueueGetWithdrawalStatusSelector    = "0xb8c4b85a"
+	lidoWithdrawalQueueUnfinalizedStETHSelector       = "0xd0fb84e8"
+	lidoWithdrawalQueueLastFinalizedRequestIDSelector = "0x4f069a13"
 	lidoWithdrawalQueueRequestStatusWordLength        = 6
 	lidoWithdrawalQueueRequestStatusAmountWord        = 0
+	lidoWithdrawalQueueRequestStatusTimestampWord     = 3
 	lidoWithdrawalQueueRequestStatusFinalizedWord     = 4
 	lidoWithdrawalQueueRequestStatusClaimedWord       = 5
-	lidoWithdrawalQueueMaxSupportedUnderlyingDecimals = 255
+
+	lidoWithdrawalQueueStatusPending   = "pending"
+	lidoWithdrawalQueueStatusClaimable = "claimable"
+	lidoWithdrawalQueueStatusClaimed   = "claimed"
 )
 
 // LidoWithdrawalQueueERC721 exposes metrics for Lido-compatible withdrawal queue ERC721 contracts.
 type LidoWithdrawalQueueERC721 struct {
+	*tickTracker
+
 	clients       []api.ExecutionClient
 	log           logrus.FieldLogger
 	checkInterval time.Duration
 	addresses     []*AddressLidoWithdrawalQueueERC721
 	labelsMap     map[string]int
+	tokens        *TokenMetadataCache
+	// requests holds the last observed status by request ID per execution client and address name.
+	requests map[lidoWithdrawalQueueStateKey]map[string]string
+
+	LidoWithdrawalQueueERC721RequestCount                prometheus.GaugeVec
+	LidoWithdrawalQueueERC721Pending                     prometheus.GaugeVec
+	LidoWithdrawalQueueERC721Claimable                   prometheus.GaugeVec
+	LidoWithdrawalQueueERC721Claimed                     prometheus.GaugeVec
+	LidoWithdrawalQueueERC721UnfinalizedStETH            prometheus.GaugeVec
+	LidoWithdrawalQueueERC721LastFinalizedRequestID      prometheus.GaugeVec
+	LidoWithdrawalQueueERC721RequestAmount               prometheus.GaugeVec
+	LidoWithdrawalQueueERC721RequestAge                  prometheus.GaugeVec
+	LidoWithdrawalQueueERC721RequestStatus               prometheus.GaugeVec
+	LidoWithdrawalQueueERC721RequestFinalizationDistance prometheus.GaugeVec
+	LidoWithdrawalQueueERC721Error                       prometheus.CounterVec
+	snapshots                                            *snapshot.Store
+}
 
-	LidoWithdrawalQueueERC721RequestCount prometheus.GaugeVec
-	LidoWithdrawalQueueERC721Pending      prometheus.GaugeVec
-	LidoWithdrawalQueueERC721Claimable    prometheus.GaugeVec
-	LidoWithdrawalQueueERC721Claimed      prometheus.GaugeVec
-	LidoWithdrawalQueueERC721Error        prometheus.CounterVec
+type lidoWithdrawalQueueStateKey struct {
+	execution string
+	name      string
 }
 
 type AddressLidoWithdrawalQueueERC721 struct {
@@ -60,7 +79,7 @@ func (n *LidoWithdrawalQueueERC721) Name() string {
 }
 
 // NewLidoWithdrawalQueueERC721 returns a new LidoWithdrawalQueueERC721 instance.
-func NewLidoWithdrawalQueueERC721(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressLidoWithdrawalQueueERC721) LidoWithdrawalQueueERC721 {
+func NewLidoWithdrawalQueueERC721(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressLidoWithdrawalQueueERC721, tokens *TokenMetadataCache, snapshots *snapshot.Store, registerer prometheus.Registerer) LidoWithdrawalQueueERC721 {
 	namespace += "_" + NameLidoWithdrawalQueueERC721
 
 	labelsMap := map[string]int{
@@ -84,12 +103,18 @@ func NewLidoWithdrawalQueueERC721(clients []api.ExecutionClient, log logrus.Fiel
 		labels[index] = label
 	}
 
+	requestLabels := append(append([]string{}, labels...), LabelRequestID)
+
 	instance := LidoWithdrawalQueueERC721{
 		clients:       clients,
 		log:           log.WithField("module", NameLidoWithdrawalQueueERC721),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
+		tokens:        tokens,
+		requests:      make(map[lidoWithdrawalQueueStateKey]map[string]string),
 		LidoWithdrawalQueueERC721RequestCount: *prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace:   namespace,
@@ -126,6 +151,60 @@ func NewLidoWithdrawalQueueERC721(clients []api.ExecutionClient, log logrus.Fiel
 			},
 			labels,
 		),
+		LidoWithdrawalQueueERC721UnfinalizedStETH: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "unfinalized_steth",
+				Help:        "The underlying token amount in all unfinalized requests of the withdrawal queue ERC721 contract.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		LidoWithdrawalQueueERC721LastFinalizedRequestID: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "last_finalized_request_id",
+				Help:        "The ID of the last finalized request of the withdrawal queue ERC721 contract.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		LidoWithdrawalQueueERC721RequestAmount: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "request_amount",
+				Help:        "The underlying token amount of a withdrawal queue ERC721 request owned by an address.",
+				ConstLabels: constLabels,
+			},
+			requestLabels,
+		),
+		LidoWithdrawalQueueERC721RequestAge: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "request_age_seconds",
+				Help:        "The seconds since a withdrawal queue ERC721 request owned by an address was created.",
+				ConstLabels: constLabels,
+			},
+			requestLabels,
+		),
+		LidoWithdrawalQueueERC721RequestStatus: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "request_status",
+				Help:        "The status of a withdrawal queue ERC721 request owned by an address.",
+				ConstLabels: constLabels,
+			},
+			append(append([]string{}, requestLabels...), LabelStatus),
+		),
+		LidoWithdrawalQueueERC721RequestFinalizationDistance: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "request_finalization_distance",
+				Help:        "The number of requests between the last finalized request and a withdrawal queue ERC721 request owned by an address.",
+				ConstLabels: constLabels,
+			},
+			requestLabels,
+		),
 		LidoWithdrawalQueueERC721Error: *prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace:   namespace,
@@ -137,17 +216,24 @@ func NewLidoWithdrawalQueueERC721(clients []api.ExecutionClient, log logrus.Fiel
 		),
 	}
 
-	prometheus.MustRegister(instance.LidoWithdrawalQueueERC721RequestCount)
-	prometheus.MustRegister(instance.LidoWithdrawalQueueERC721Pending)
-	prometheus.MustRegister(instance.LidoWithdrawalQueueERC721Claimable)
-	prometheus.MustRegister(instance.LidoWithdrawalQueueERC721Claimed)
-	prometheus.MustRegister(instance.LidoWithdrawalQueueERC721Error)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721RequestCount)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721Pending)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721Claimable)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721Claimed)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721UnfinalizedStETH)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721LastFinalizedRequestID)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721RequestAmount)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721RequestAge)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721RequestStatus)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721RequestFinalizationDistance)
+	registerer.MustRegister(instance.LidoWithdrawalQueueERC721Error)
 
 	return instance
 }
 
 func (n *LidoWithdrawalQueueERC721) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -155,6 +241,7 @@ func (n *LidoWithdrawalQueueERC721) Start(ctx context.Context) {
 			return
 		case <-time.After(n.checkInterval):
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
@@ -208,6 +295,7 @@ func (n *LidoWithdrawalQueueERC721) getWithdrawalQueue(ctx context.Context, clie
 	defer func() {
 		if err != nil {
 			n.LidoWithdrawalQueueERC721Error.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Inc()
+			n.snapshots.SetError(NameLidoWithdrawalQueueERC721, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
@@ -216,12 +304,12 @@ func (n *LidoWithdrawalQueueERC721) getWithdrawalQueue(ctx context.Context, clie
 		return err
 	}
 
-	symbol, err = n.getTokenSymbol(ctx, client, underlyingToken)
+	symbol, err = n.tokens.Symbol(ctx, client, underlyingToken)
 	if err != nil {
 		return err
 	}
 
-	decimals, err := n.getTokenDecimals(ctx, client, underlyingToken)
+	decimals, err := n.tokens.Decimals(ctx, client, underlyingToken)
 	if err != nil {
 		return err
 	}
@@ -233,26 +321,109 @@ func (n *LidoWithdrawalQueueERC721) getWithdrawalQueue(ctx context.Context, clie
 
 	labels := n.getLabelValues(address, symbol, client.Name())
 	n.LidoWithdrawalQueueERC721RequestCount.WithLabelValues(labels...).Set(float64(len(requestIDs)))
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameLidoWithdrawalQueueERC721,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Symbol:    symbol,
+		Value:     float64(len(requestIDs)),
+	}, time.Now())
+
+	var statuses []lidoWithdrawalQueueRequestStatus
+
+	if len(requestIDs) > 0 {
+		statuses, err = n.getWithdrawalStatuses(ctx, client, address, requestIDs)
+		if err != nil {
+			return err
+		}
 
-	if len(requestIDs) == 0 {
-		n.setAmounts(labels, 0, 0, 0)
+		if len(statuses) != len(requestIDs) {
+			err = fmt.Errorf("got %d withdrawal statuses for %d request ids", len(statuses), len(requestIDs))
 
-		return nil
+			return err
+		}
 	}
 
-	statuses, err := n.getWithdrawalStatuses(ctx, client, address, requestIDs)
+	pending, claimable, claimed := sumLidoWithdrawalQueueStatuses(statuses, decimals)
+	n.setAmounts(labels, pending, claimable, claimed)
+
+	// The queue state is read once the amounts are exported, so failing to
+	// read it does not hide them.
+	lastFinalizedRequestID, err := n.getQueueState(ctx, client, address, labels, decimals)
+
+	key := lidoWithdrawalQueueStateKey{execution: client.Name(), name: address.Name}
+	n.setRequests(key, labels, requestIDs, statuses, lastFinalizedRequestID, decimals)
+
+	return err
+}
+
+// getQueueState exports the unfinalized stETH and the last finalized request
+// ID of the queue, returning the latter.
+func (n *LidoWithdrawalQueueERC721) getQueueState(ctx context.Context, client api.ExecutionClient, address *AddressLidoWithdrawalQueueERC721, labels []string, decimals int) (*big.Int, error) {
+	unfinalized, err := ethCallUint256(ctx, client, address.Contract, lidoWithdrawalQueueUnfinalizedStETHSelector)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to get unfinalized stETH: %w", err)
 	}
 
-	if len(statuses) != len(requestIDs) {
-		return fmt.Errorf("got %d withdrawal statuses for %d request ids", len(statuses), len(requestIDs))
+	n.LidoWithdrawalQueueERC721UnfinalizedStETH.WithLabelValues(labels...).Set(tokenAmountToFloat64(unfinalized, decimals))
+
+	lastFinalizedRequestID, err := ethCallUint256(ctx, client, address.Contract, lidoWithdrawalQueueLastFinalizedRequestIDSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last finalized request id: %w", err)
 	}
 
-	pending, claimable, claimed := sumLidoWithdrawalQueueStatuses(statuses, decimals)
-	n.setAmounts(labels, pending, claimable, claimed)
+	n.LidoWithdrawalQueueERC721LastFinalizedRequestID.WithLabelValues(labels...).Set(bigIntToFloat64(lastFinalizedRequestID))
 
-	return nil
+	return lastFinalizedRequestID, nil
+}
+
+// setRequests exports the per-request series and removes the series of
+// requests that are no longer returned or whose status changed. The
+// finalization distance is removed when the last finalized request ID is nil.
+func (n *LidoWithdrawalQueueERC721) setRequests(key lidoWithdrawalQueueStateKey, labels []string, requestIDs []*big.Int, statuses []lidoWithdrawalQueueRequestStatus, lastFinalizedRequestID *big.Int, decimals int) {
+	current := make(map[string]string, len(requestIDs))
+
+	for i, requestID := range requestIDs {
+		status := statuses[i]
+		requestLabels := append(append([]string{}, labels...), requestID.String())
+
+		current[requestID.String()] = status.status()
+
+		n.LidoWithdrawalQueueERC721RequestAmount.WithLabelValues(requestLabels...).Set(tokenAmountToFloat64(status.amount, decimals))
+		n.LidoWithdrawalQueueERC721RequestAge.WithLabelValues(requestLabels...).Set(time.Since(time.Unix(status.timestamp.Int64(), 0)).Seconds())
+		n.LidoWithdrawalQueueERC721RequestStatus.WithLabelValues(append(requestLabels, status.status())...).Set(1)
+
+		if lastFinalizedRequestID == nil {
+			n.LidoWithdrawalQueueERC721RequestFinalizationDistance.DeleteLabelValues(requestLabels...)
+
+			continue
+		}
+
+		distance := new(big.Int)
+		if !status.isFinalized {
+			distance.Sub(requestID, lastFinalizedRequestID)
+		}
+
+		n.LidoWithdrawalQueueERC721RequestFinalizationDistance.WithLabelValues(requestLabels...).Set(bigIntToFloat64(distance))
+	}
+
+	for requestID, status := range n.requests[key] {
+		requestLabels := append(append([]string{}, labels...), requestID)
+
+		if current[requestID] != status {
+			n.LidoWithdrawalQueueERC721RequestStatus.DeleteLabelValues(append(requestLabels, status)...)
+		}
+
+		if _, ok := current[requestID]; !ok {
+			n.LidoWithdrawalQueueERC721RequestAmount.DeleteLabelValues(requestLabels...)
+			n.LidoWithdrawalQueueERC721RequestAge.DeleteLabelValues(requestLabels...)
+			n.LidoWithdrawalQueueERC721RequestFinalizationDistance.DeleteLabelValues(requestLabels...)
+		}
+	}
+
+	n.requests[key] = current
 }
 
 func (n *LidoWithdrawalQueueERC721) setAmounts(labels []string, pending, claimable, claimed float64) {
@@ -275,48 +446,6 @@ func (n *LidoWithdrawalQueueERC721) getUnderlyingToken(ctx context.Context, clie
 	return decodeABIAddress(tokenHex)
 }
 
-func (n *LidoWithdrawalQueueERC721) getTokenSymbol(ctx context.Context, client api.ExecutionClient, tokenAddress string) (string, error) {
-	callData := lidoWithdrawalQueueSymbolSelector
-
-	symbolHex, err := client.ETHCall(ctx, &api.ETHCallTransaction{
-		To:   tokenAddress,
-		Data: &callData,
-	}, "latest")
-	if err != nil {
-		return "", err
-	}
-
-	return hexStringToString(symbolHex)
-}
-
-func (n *LidoWithdrawalQueueERC721) getTokenDecimals(ctx context.Context, client api.ExecutionClient, tokenAddress string) (int, error) {
-	callData := lidoWithdrawalQueueDecimalsSelector
-
-	decimalsHex, err := client.ETHCall(ctx, &api.ETHCallTransaction{
-		To:   tokenAddress,
-		Data: &callData,
-	}, "latest")
-	if err != nil {
-		return 0, err
-	}
-
-	decimals, err := decodeABIUint256(decimalsHex)
-	if err != nil {
-		return 0, err
-	}
-
-	if !decimals.IsUint64() || decimals.Uint64() > lidoWithdrawalQueueMaxSupportedUnderlyingDecimals {
-		return 0, fmt.Errorf("unsupported underlying token decimals: %s", decimals.String())
-	}
-
-	decimalsInt := 0
-	for range decimals.Uint64() {
-		decimalsInt++
-	}
-
-	return decimalsInt, nil
-}
-
 func (n *LidoWithdrawalQueueERC721) getWithdrawalRequests(ctx context.Context, client api.ExecutionClient, address *AddressLidoWithdrawalQueueERC721) ([]*big.Int, error) {
 	callData, err := encodeAddressCall(lidoWithdrawalQueueGetWithdrawalRequestsSelector, address.Address)
 	if err != nil {
@@ -350,10 +479,22 @@ func (n *LidoWithdrawalQueueERC721) getWithdrawalStatuses(ctx context.Context, c
 
 type lidoWithdrawalQueueRequestStatus struct {
 	amount      *big.Int
+	timestamp   *big.Int
 	isFinalized bool
 	isClaimed   bool
 }
 
+func (s lidoWithdrawalQueueRequestStatus) status() string {
+	switch {
+	case s.isClaimed:
+		return lidoWithdrawalQueueStatusClaimed
+	case s.isFinalized:
+		return lidoWithdrawalQueueStatusClaimable
+	default:
+		return lidoWithdrawalQueueStatusPending
+	}
+}
+
 func sumLidoWithdrawalQueueStatuses(statuses []lidoWithdrawalQueueRequestStatus, decimals int) (float64, float64, float64) {
 	pending := new(big.Int)
 	claimable := new(big.Int)
@@ -375,107 +516,6 @@ func sumLidoWithdrawalQueueStatuses(statuses []lidoWithdrawalQueueRequestStatus,
 		tokenAmountToFloat64(claimed, decimals)
 }
 
-func tokenAmountToFloat64(amount *big.Int, decimals int) float64 {
-	if amount.Sign() == 0 {
-		return 0
-	}
-
-	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
-	value := new(big.Float).SetPrec(256).SetInt(amount)
-	value.Quo(value, new(big.Float).SetPrec(256).SetInt(denominator))
-
-	result, _ := value.Float64()
-
-	return result
-}
-
-func encodeAddressCall(selector, address string) (string, error) {
-	encodedAddress, err := encodeAddressArgument(address)
-	if err != nil {
-		return "", err
-	}
-
-	return selector + encodedAddress, nil
-}
-
-func encodeAddressArgument(address string) (string, error) {
-	cleanedAddress := strings.TrimPrefix(strings.ToLower(address), "0x")
-	if len(cleanedAddress) != 40 {
-		return "", fmt.Errorf("invalid ethereum address length: %s", address)
-	}
-
-	if _, err := hex.DecodeString(cleanedAddress); err != nil {
-		return "", fmt.Errorf("invalid ethereum address: %w", err)
-	}
-
-	return strings.Repeat("0", 24) + cleanedAddress, nil
-}
-
-func encodeUint256ArrayCall(selector string, values []*big.Int) string {
-	var data strings.Builder
-
-	data.WriteString(selector)
-	data.WriteString(formatABIWord(big.NewInt(32)))
-	data.WriteString(formatABIWord(big.NewInt(int64(len(values)))))
-
-	for _, value := range values {
-		data.WriteString(formatABIWord(value))
-	}
-
-	return data.String()
-}
-
-func formatABIWord(value *big.Int) string {
-	return fmt.Sprintf("%064x", value)
-}
-
-func decodeABIAddress(hexStr string) (string, error) {
-	data, err := decodeHexBytes(hexStr)
-	if err != nil {
-		return "", err
-	}
-
-	if len(data) < 32 {
-		return "", fmt.Errorf("ABI address response too short: %d bytes", len(data))
-	}
-
-	return "0x" + hex.EncodeToString(data[12:32]), nil
-}
-
-func decodeABIUint256(hexStr string) (*big.Int, error) {
-	data, err := decodeHexBytes(hexStr)
-	if err != nil {
-		return nil, err
-	}
-
-	word, err := abiWord(data, 0)
-	if err != nil {
-		return nil, err
-	}
-
-	return new(big.Int).SetBytes(word), nil
-}
-
-func decodeABIUint256Array(hexStr string) ([]*big.Int, error) {
-	data, offset, length, err := decodeDynamicABIArrayHeader(hexStr)
-	if err != nil {
-		return nil, err
-	}
-
-	values := make([]*big.Int, length)
-
-	for i := range length {
-		word, wordErr := abiWord(data, offset+32+i*32)
-		if wordErr != nil {
-			return nil, wordErr
-		}
-
-		values[i] = new(big.Int).SetBytes(word)
-	}
-
-	return values, nil
-}
-
 func decodeLidoWithdrawalQueueStatuses(hexStr string) ([]lidoWithdrawalQueueRequestStatus, error) {
 	data, offset, length, err := decodeDynamicABIArrayHeader(hexStr)
 	if err != nil {
@@ -491,6 +531,11 @@ func decodeLidoWithdrawalQueueStatuses(hexStr string) ([]lidoWithdrawalQueueRequ
 			return nil, amountErr
 		}
 
+		timestamp, timestampErr := decodeABIWordAsBigInt(data, baseOffset+lidoWithdrawalQueueRequestStatusTimestampWord*32)
+		if timestampErr != nil {
+			return nil, timestampErr
+		}
+
 		isFinalized, finalizedErr := decodeABIWordAsBool(data, baseOffset+lidoWithdrawalQueueRequestStatusFinalizedWord*32)
 		if finalizedErr != nil {
 			return nil, finalizedErr
@@ -503,6 +548,7 @@ func decodeLidoWithdrawalQueueStatuses(hexStr string) ([]lidoWithdrawalQueueRequ
 
 		statuses[i] = lidoWithdrawalQueueRequestStatus{
 			amount:      amount,
+			timestamp:   timestamp,
 			isFinalized: isFinalized,
 			isClaimed:   isClaimed,
 		}
@@ -510,83 +556,3 @@ func decodeLidoWithdrawalQueueStatuses(hexStr string) ([]lidoWithdrawalQueueRequ
 
 	return statuses, nil
 }
-
-func decodeDynamicABIArrayHeader(hexStr string) ([]byte, int, int, error) {
-	data, err := decodeHexBytes(hexStr)
-	if err != nil {
-		return nil, 0, 0, err
-	}
-
-	offset, err := decodeABIWordAsInt(data, 0)
-	if err != nil {
-		return nil, 0, 0, err
-	}
-
-	length, err := decodeABIWordAsInt(data, offset)
-	if err != nil {
-		return nil, 0, 0, err
-	}
-
-	return data, offset, length, nil
-}
-
-func decodeABIWordAsBigInt(data []byte, offset int) (*big.Int, error) {
-	word, err := abiWord(data, offset)
-	if err != nil {
-		return nil, err
-	}
-
-	return new(big.Int).SetBytes(word), nil
-}
-
-func decodeABIWordAsBool(data []byte, offset int) (bool, error) {
-	value, err := decodeABIWordAsBigInt(data, offset)
-	if err != nil {
-		return false, err
-	}
-
-	return value.Sign() != 0, nil
-}
-
-func decodeABIWordAsInt(data []byte, offset int) (int, error) {
-	value, err := decodeABIWordAsBigInt(data, offset)
-	if err != nil {
-		return 0, err
-	}
-
-	if !value.IsInt64() {
-		return 0, fmt.Errorf("ABI value does not fit in int64: %s", value.String())
-	}
-
-	int64Value := value.Int64()
-	if int64Value < 0 || int64Value > int64(^uint(0)>>1) {
-		return 0, fmt.Errorf("ABI value does not fit in int: %s", value.String())
-	}
-
-	return int(int64Value), nil
-}
-
-func abiWord(data []byte, offset int) ([]byte, error) {
-	if offset < 0 {
-		return nil, errors.New("negative ABI word offset")
-	}
-
-	if len(data) < offset+32 {
-		return nil, fmt.Errorf("ABI word at offset %d exceeds %d bytes", offset, len(data))
-	}
-
-	return data[offset : offset+32], nil
-}
-
-func decodeHexBytes(hexStr string) ([]byte, error) {
-	cleaned := strings.TrimPrefix(hexStr, "0x")
-	if cleaned == "" {
-		return nil, errors.New("empty hex string")
-	}
-
-	if len(cleaned)%2 != 0 {
-		cleaned = "0" + cleaned
-	}
-
-	return hex.DecodeString(cleaned)
-}
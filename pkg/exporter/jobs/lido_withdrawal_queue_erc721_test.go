@@ -34,6 +34,8 @@ func TestLidoWithdrawalQueueERC721_getWithdrawalQueue(t *testing.T) {
 			testWithdrawalStatus(2500000, true, false),
 			testWithdrawalStatus(3000000, true, true),
 		),
+		unfinalizedStETHResponse: encodeABIUintReturn(42000000),
+		lastFinalizedResponse:    encodeABIUintReturn(102),
 	}
 
 	queue := NewLidoWithdrawalQueueERC721(
@@ -43,6 +45,9 @@ func TestLidoWithdrawalQueueERC721_getWithdrawalQueue(t *testing.T) {
 		"lido_queue_get",
 		map[string]string{},
 		[]*AddressLidoWithdrawalQueueERC721{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
 	)
 
 	err := queue.getWithdrawalQueue(context.Background(), mockClient, address)
@@ -62,13 +67,17 @@ func TestLidoWithdrawalQueueERC721_getWithdrawalQueue(t *testing.T) {
 	assertMetricValue(t, queue.LidoWithdrawalQueueERC721Pending, labels, 1.5)
 	assertMetricValue(t, queue.LidoWithdrawalQueueERC721Claimable, labels, 2.5)
 	assertMetricValue(t, queue.LidoWithdrawalQueueERC721Claimed, labels, 3)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721UnfinalizedStETH, labels, 42)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721LastFinalizedRequestID, labels, 102)
 
 	wantSelectors := []string{
 		lidoWithdrawalQueueUnderlyingTokenSelector,
-		lidoWithdrawalQueueSymbolSelector,
-		lidoWithdrawalQueueDecimalsSelector,
+		tokenSymbolSelector,
+		tokenDecimalsSelector,
 		lidoWithdrawalQueueGetWithdrawalRequestsSelector,
 		lidoWithdrawalQueueGetWithdrawalStatusSelector,
+		lidoWithdrawalQueueUnfinalizedStETHSelector,
+		lidoWithdrawalQueueLastFinalizedRequestIDSelector,
 	}
 
 	assertCallSelectors(t, mockClient.callLog, wantSelectors)
@@ -96,6 +105,9 @@ func TestLidoWithdrawalQueueERC721_getWithdrawalQueue_EmptyRequests(t *testing.T
 		"lido_queue_empty",
 		map[string]string{},
 		[]*AddressLidoWithdrawalQueueERC721{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
 	)
 
 	err := queue.getWithdrawalQueue(context.Background(), mockClient, address)
@@ -117,12 +129,65 @@ func TestLidoWithdrawalQueueERC721_getWithdrawalQueue_EmptyRequests(t *testing.T
 	assertMetricValue(t, queue.LidoWithdrawalQueueERC721Claimed, labels, 0)
 	assertCallSelectors(t, mockClient.callLog, []string{
 		lidoWithdrawalQueueUnderlyingTokenSelector,
-		lidoWithdrawalQueueSymbolSelector,
-		lidoWithdrawalQueueDecimalsSelector,
+		tokenSymbolSelector,
+		tokenDecimalsSelector,
 		lidoWithdrawalQueueGetWithdrawalRequestsSelector,
+		lidoWithdrawalQueueUnfinalizedStETHSelector,
+		lidoWithdrawalQueueLastFinalizedRequestIDSelector,
 	})
 }
 
+func TestLidoWithdrawalQueueERC721_getWithdrawalQueue_QueueStateError(t *testing.T) {
+	address := &AddressLidoWithdrawalQueueERC721{
+		Name:     "State Queue",
+		Address:  testLidoHolderAddress,
+		Contract: testLidoQueueContract,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		underlyingTokenResponse:    encodeABIAddressReturn(testHolder1Address),
+		symbolResponse:             encodeABIStringReturn("stETH"),
+		decimalsResponse:           encodeABIUintReturn(18),
+		withdrawalRequestsResponse: encodeABIUintArrayReturn(7),
+		withdrawalStatusResponse:   encodeLidoWithdrawalStatusesReturn(testWithdrawalStatus(2e18, false, false)),
+		ethCallResponses: map[string]string{
+			lidoWithdrawalQueueUnfinalizedStETHSelector: "0xzz",
+		},
+	}
+
+	queue := NewLidoWithdrawalQueueERC721(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"lido_queue_state_error",
+		map[string]string{},
+		[]*AddressLidoWithdrawalQueueERC721{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
+	)
+
+	if err := queue.getWithdrawalQueue(context.Background(), mockClient, address); err == nil {
+		t.Fatal("getWithdrawalQueue() error = nil, want the unfinalized stETH error")
+	}
+
+	labels := queue.getLabelValues(address, "stETH", testMockNodeName)
+
+	// Failing to read the queue state must not hide the request metrics.
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestCount, labels, 1)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721Pending, labels, 2)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestAmount, append(append([]string{}, labels...), "7"), 2)
+
+	if errors := testutil.ToFloat64(queue.LidoWithdrawalQueueERC721Error.WithLabelValues(labels...)); errors != 1 {
+		t.Fatalf("errors = %f, want 1", errors)
+	}
+
+	if count := testutil.CollectAndCount(&queue.LidoWithdrawalQueueERC721RequestFinalizationDistance); count != 0 {
+		t.Fatalf("finalization distance series = %d, want 0", count)
+	}
+}
+
 func TestLidoWithdrawalQueueERC721_tick_MultiClient(t *testing.T) {
 	address := &AddressLidoWithdrawalQueueERC721{
 		Name:     "Multi Queue",
@@ -156,19 +221,99 @@ func TestLidoWithdrawalQueueERC721_tick_MultiClient(t *testing.T) {
 		"lido_queue_multi",
 		map[string]string{},
 		[]*AddressLidoWithdrawalQueueERC721{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
 	)
 
 	queue.tick(context.Background())
 
-	if len(client1.callLog) != 5 {
-		t.Fatalf("node-1 expected 5 calls, got %d", len(client1.callLog))
+	if len(client1.callLog) != 7 {
+		t.Fatalf("node-1 expected 7 calls, got %d", len(client1.callLog))
 	}
 
-	if len(client2.callLog) != 5 {
-		t.Fatalf("node-2 expected 5 calls, got %d", len(client2.callLog))
+	if len(client2.callLog) != 7 {
+		t.Fatalf("node-2 expected 7 calls, got %d", len(client2.callLog))
 	}
 }
 
+func TestLidoWithdrawalQueueERC721_getWithdrawalQueue_Requests(t *testing.T) {
+	address := &AddressLidoWithdrawalQueueERC721{
+		Name:     "Request Queue",
+		Address:  testLidoHolderAddress,
+		Contract: testLidoQueueContract,
+		Labels:   map[string]string{},
+	}
+
+	created := time.Now().Add(-time.Hour).Unix()
+
+	pending := testWithdrawalStatus(2e18, false, false)
+	pending.timestamp = big.NewInt(created)
+
+	mockClient := &mockExecutionClient{
+		underlyingTokenResponse:    encodeABIAddressReturn(testHolder1Address),
+		symbolResponse:             encodeABIStringReturn("stETH"),
+		withdrawalRequestsResponse: encodeABIUintArrayReturn(90, 110),
+		withdrawalStatusResponse: encodeLidoWithdrawalStatusesReturn(
+			testWithdrawalStatus(1e18, true, false),
+			pending,
+		),
+		lastFinalizedResponse: encodeABIUintReturn(100),
+	}
+
+	queue := NewLidoWithdrawalQueueERC721(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"lido_queue_requests",
+		map[string]string{},
+		[]*AddressLidoWithdrawalQueueERC721{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
+	)
+
+	if err := queue.getWithdrawalQueue(context.Background(), mockClient, address); err != nil {
+		t.Fatalf("getWithdrawalQueue() error = %v", err)
+	}
+
+	labels := queue.getLabelValues(address, "stETH", testMockNodeName)
+	finalized := append(append([]string{}, labels...), "90")
+	unfinalized := append(append([]string{}, labels...), "110")
+
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestAmount, finalized, 1)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestAmount, unfinalized, 2)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestStatus, append(append([]string{}, finalized...), lidoWithdrawalQueueStatusClaimable), 1)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestStatus, append(append([]string{}, unfinalized...), lidoWithdrawalQueueStatusPending), 1)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestFinalizationDistance, finalized, 0)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestFinalizationDistance, unfinalized, 10)
+
+	age := testutil.ToFloat64(queue.LidoWithdrawalQueueERC721RequestAge.WithLabelValues(unfinalized...))
+	if age < 3600 || age > 3660 {
+		t.Fatalf("request age = %f, want about 3600", age)
+	}
+
+	// Request 90 is claimed and request 110 is finalized.
+	mockClient.withdrawalRequestsResponse = encodeABIUintArrayReturn(110)
+	mockClient.withdrawalStatusResponse = encodeLidoWithdrawalStatusesReturn(testWithdrawalStatus(2e18, true, false))
+	mockClient.lastFinalizedResponse = encodeABIUintReturn(110)
+
+	if err := queue.getWithdrawalQueue(context.Background(), mockClient, address); err != nil {
+		t.Fatalf("getWithdrawalQueue() error = %v", err)
+	}
+
+	if count := testutil.CollectAndCount(&queue.LidoWithdrawalQueueERC721RequestAmount); count != 1 {
+		t.Fatalf("request amount series = %d, want 1", count)
+	}
+
+	if count := testutil.CollectAndCount(&queue.LidoWithdrawalQueueERC721RequestStatus); count != 1 {
+		t.Fatalf("request status series = %d, want 1", count)
+	}
+
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestStatus, append(append([]string{}, unfinalized...), lidoWithdrawalQueueStatusClaimable), 1)
+	assertMetricValue(t, queue.LidoWithdrawalQueueERC721RequestFinalizationDistance, unfinalized, 0)
+}
+
 func TestLidoWithdrawalQueueERC721_getLabelValues(t *testing.T) {
 	addresses := []*AddressLidoWithdrawalQueueERC721{
 		{
@@ -188,6 +333,9 @@ func TestLidoWithdrawalQueueERC721_getLabelValues(t *testing.T) {
 		"lido_queue_labels",
 		map[string]string{},
 		addresses,
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
 	)
 
 	labels := queue.getLabelValues(addresses[0], "abcETH", "mock-node")
@@ -246,9 +394,16 @@ func testLogger() logrus.FieldLogger {
 	return log
 }
 
+// testRegistry returns a fresh registry so jobs constructed by tests do not
+// collide with each other.
+func testRegistry() prometheus.Registerer {
+	return prometheus.NewRegistry()
+}
+
 func testWithdrawalStatus(amount int64, finalized, claimed bool) lidoWithdrawalQueueRequestStatus {
 	return lidoWithdrawalQueueRequestStatus{
 		amount:      big.NewInt(amount),
+		timestamp:   new(big.Int),
 		isFinalized: finalized,
 		isClaimed:   claimed,
 	}
@@ -303,7 +458,7 @@ func encodeLidoWithdrawalStatusesReturn(statuses ...lidoWithdrawalQueueRequestSt
 		builder.WriteString(formatABIWord(status.amount))
 		builder.WriteString(formatABIWord(big.NewInt(0)))
 		builder.WriteString(formatABIWord(big.NewInt(0)))
-		builder.WriteString(formatABIWord(big.NewInt(0)))
+		builder.WriteString(formatABIWord(status.timestamp))
 		builder.WriteString(formatBoolWord(status.isFinalized))
 		builder.WriteString(formatBoolWord(status.isClaimed))
 	}
@@ -0,0 +1,553 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameLogs = "logs"
+
+	// LogsEventTransfer decodes ERC20 and ERC721 Transfer events.
+	LogsEventTransfer = "transfer"
+	// LogsEventApproval decodes ERC20 and ERC721 Approval events.
+	LogsEventApproval = "approval"
+
+	// keccak256("Transfer(address,address,uint256)").
+	logsTransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	// keccak256("Approval(address,address,uint256)").
+	logsApprovalTopic = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+
+	logsDirectionIn  = "in"
+	logsDirectionOut = "out"
+
+	// logsMaxBlockRange bounds the blocks requested by a single eth_getLogs call.
+	logsMaxBlockRange = 1000
+	// logsMaxBlocksPerTick bounds the blocks scanned per address per tick, the
+	// remaining blocks are scanned on the following ticks.
+	logsMaxBlocksPerTick = 10 * logsMaxBlockRange
+	// logsCursorFile is the name of the file the block cursors are persisted to in the state directory.
+	logsCursorFile = "logs_cursor.json"
+)
+
+// logsEventTopics maps each built-in decoder to its event signature topic.
+var logsEventTopics = map[string]string{
+	LogsEventTransfer: logsTransferTopic,
+	LogsEventApproval: logsApprovalTopic,
+}
+
+// Logs exposes counters for event logs emitted by ethereum contracts.
+type Logs struct {
+	*tickTracker
+
+	clients       []api.ExecutionClient
+	log           logrus.FieldLogger
+	LogsEvents    prometheus.CounterVec
+	LogsAmount    prometheus.CounterVec
+	LogsCursor    prometheus.GaugeVec
+	LogsError     prometheus.CounterVec
+	checkInterval time.Duration
+	addresses     []*AddressLogs
+	labelsMap     map[string]int
+	tokens        *TokenMetadataCache
+	cursors       *logsCursorStore
+	snapshots     *snapshot.Store
+}
+
+type AddressLogs struct {
+	// Address is the account the events are filtered on. Transfer events are
+	// counted as in when it is the recipient and out when it is the sender,
+	// approvals as out when it is the owner and in when it is the spender.
+	Address  string `yaml:"address"`
+	Contract string `yaml:"contract"`
+	// Event is a built-in decoder, either transfer or approval. When empty the
+	// raw Topics filter is used and only events are counted.
+	Event  string     `yaml:"event"`
+	Topics [][]string `yaml:"topics"`
+	// FromBlock is the first block scanned when no cursor is persisted. When
+	// unset scanning starts at the confirmed head block.
+	FromBlock *uint64 `yaml:"fromBlock"`
+	// Confirmations is the number of blocks below the head that are not
+	// scanned yet, so events of blocks that are reorged out are not counted.
+	Confirmations uint64            `yaml:"confirmations"`
+	Name          string            `yaml:"name"`
+	Labels        map[string]string `yaml:"labels"`
+}
+
+type logsFilter struct {
+	direction string
+	topics    [][]string
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressLogs) GetName() string { return a.Name }
+
+// Validate checks the contract, event decoder and topics.
+func (a *AddressLogs) Validate() error {
+	if _, err := encodeAddressArgument(a.Contract); err != nil {
+		return fmt.Errorf("logs %s: invalid contract: %w", a.Name, err)
+	}
+
+	if a.Address != "" {
+		if _, err := encodeAddressArgument(a.Address); err != nil {
+			return fmt.Errorf("logs %s: invalid address: %w", a.Name, err)
+		}
+	}
+
+	if a.Event == "" {
+		// Raw topics are matched as configured, the address is not applied.
+		if a.Address != "" && len(a.Topics) > 0 {
+			return fmt.Errorf("logs %s: address can only be set with an event, encode it in the topics instead", a.Name)
+		}
+
+		return nil
+	}
+
+	if _, ok := logsEventTopics[a.Event]; !ok {
+		return fmt.Errorf("logs %s: unsupported event %q, expected %s or %s", a.Name, a.Event, LogsEventTransfer, LogsEventApproval)
+	}
+
+	if len(a.Topics) > 0 {
+		return fmt.Errorf("logs %s: topics can only be set without an event", a.Name)
+	}
+
+	return nil
+}
+
+// filters returns the topic filters to query, one per direction.
+func (a *AddressLogs) filters() ([]logsFilter, error) {
+	eventTopic, ok := logsEventTopics[a.Event]
+	if !ok {
+		return []logsFilter{{topics: a.Topics}}, nil
+	}
+
+	if a.Address == "" {
+		return []logsFilter{{topics: [][]string{{eventTopic}}}}, nil
+	}
+
+	account, err := encodeAddressArgument(a.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	account = "0x" + account
+
+	// Transfer(from, to, value) and Approval(owner, spender, value) both index
+	// the outgoing party first.
+	return []logsFilter{
+		{direction: logsDirectionOut, topics: [][]string{{eventTopic}, {account}}},
+		{direction: logsDirectionIn, topics: [][]string{{eventTopic}, nil, {account}}},
+	}, nil
+}
+
+func (n *Logs) Name() string {
+	return NameLogs
+}
+
+// NewLogs returns a new Logs instance. Block cursors are persisted in
+// stateDir when it is set.
+func NewLogs(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressLogs, tokens *TokenMetadataCache, stateDir string, snapshots *snapshot.Store, registerer prometheus.Registerer) Logs {
+	namespace += "_" + NameLogs
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelAddress:   1,
+		LabelContract:  2,
+		LabelSymbol:    3,
+		LabelEvent:     4,
+		LabelExecution: 5,
+	}
+
+	for address := range addresses {
+		for label := range addresses[address].Labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	directionLabels := append(append([]string{}, labels...), LabelDirection)
+
+	instance := Logs{
+		clients:       clients,
+		log:           log.WithField("module", NameLogs),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
+		addresses:     addresses,
+		checkInterval: checkInterval,
+		labelsMap:     labelsMap,
+		tokens:        tokens,
+		cursors:       newLogsCursorStore(stateDir),
+		LogsEvents: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "events_total",
+				Help:        "The total event logs emitted by a ethereum contract.",
+				ConstLabels: constLabels,
+			},
+			directionLabels,
+		),
+		LogsAmount: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "amount_total",
+				Help:        "The total decimal adjusted amount of decoded Transfer and Approval event logs emitted by a ethereum contract.",
+				ConstLabels: constLabels,
+			},
+			directionLabels,
+		),
+		LogsCursor: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "cursor_block",
+				Help:        "The last block scanned for event logs emitted by a ethereum contract.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		LogsError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting event logs emitted by a ethereum contract.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.LogsEvents)
+	registerer.MustRegister(instance.LogsAmount)
+	registerer.MustRegister(instance.LogsCursor)
+	registerer.MustRegister(instance.LogsError)
+
+	return instance
+}
+
+func (n *Logs) Start(ctx context.Context) {
+	if err := n.cursors.load(); err != nil {
+		n.log.WithError(err).Error("Failed to load logs block cursors, scanning from the head block")
+	}
+
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *Logs) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		head, err := getBlockNumber(ctx, client)
+		if err != nil {
+			n.log.WithError(err).WithField(LabelExecution, client.Name()).Error("Failed to get block number")
+
+			continue
+		}
+
+		for _, address := range n.addresses {
+			err := n.getLogs(ctx, client, address, head)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get event logs")
+			}
+		}
+	}
+}
+
+func (n *Logs) getLabelValues(address *AddressLogs, symbol, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		if address.Labels != nil && address.Labels[label] != "" {
+			values[index] = address.Labels[label]
+		} else {
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelAddress:
+				values[index] = address.Address
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelSymbol:
+				values[index] = symbol
+			case LabelEvent:
+				values[index] = address.Event
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+// getLogs scans the blocks from the cursor up to the confirmed head in bounded
+// ranges, at most logsMaxBlocksPerTick per call, advancing and persisting the
+// cursor after each range so a restart resumes without counting any event twice.
+func (n *Logs) getLogs(ctx context.Context, client api.ExecutionClient, address *AddressLogs, head uint64) error {
+	var err error
+
+	symbol := ""
+
+	defer func() {
+		if err != nil {
+			n.LogsError.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Inc()
+			n.snapshots.SetError(NameLogs, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	// Nothing is confirmed yet.
+	if address.Confirmations > head {
+		return nil
+	}
+
+	head -= address.Confirmations
+
+	key := client.Name() + "/" + address.Name
+
+	from, ok := n.cursors.get(key)
+	if !ok {
+		from = head + 1
+		if address.FromBlock != nil {
+			from = *address.FromBlock
+		}
+	}
+
+	filters, err := address.filters()
+	if err != nil {
+		return err
+	}
+
+	if address.Event != "" {
+		symbol, err = n.tokens.Symbol(ctx, client, address.Contract)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Decimals are only read once an event with an amount is seen, ERC721
+	// contracts do not implement decimals().
+	decimals, decimalsOK := 0, false
+
+	labels := n.getLabelValues(address, symbol, client.Name())
+
+	last := min(head, from+logsMaxBlocksPerTick-1)
+
+	for from <= last {
+		to := min(from+logsMaxBlockRange-1, last)
+
+		// Every filter of the range is fetched before any is counted, so a
+		// failed range is retried in full on the next tick.
+		results := make([][]*api.ETHLog, len(filters))
+
+		for i, filter := range filters {
+			results[i], err = client.ETHGetLogs(ctx, &api.ETHGetLogsFilter{
+				FromBlock: "0x" + strconv.FormatUint(from, 16),
+				ToBlock:   "0x" + strconv.FormatUint(to, 16),
+				Address:   []string{address.Contract},
+				Topics:    filter.topics,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get logs for blocks %d to %d: %w", from, to, err)
+			}
+		}
+
+		if address.Event != "" && !decimalsOK && logsHaveAmounts(results) {
+			decimals, err = n.tokens.Decimals(ctx, client, address.Contract)
+			if err != nil {
+				return fmt.Errorf("failed to get decimals: %w", err)
+			}
+
+			decimalsOK = true
+		}
+
+		for i, filter := range filters {
+			n.countLogs(address, append(append([]string{}, labels...), filter.direction), results[i], decimals)
+		}
+
+		from = to + 1
+
+		n.LogsCursor.WithLabelValues(labels...).Set(float64(to))
+		n.snapshots.Set(&snapshot.Update{
+			Job:       NameLogs,
+			Name:      address.Name,
+			Execution: client.Name(),
+			Address:   address.Address,
+			Contract:  address.Contract,
+			Symbol:    symbol,
+			Value:     float64(to),
+		}, time.Now())
+
+		if err = n.cursors.set(key, from); err != nil {
+			return fmt.Errorf("failed to persist logs block cursor: %w", err)
+		}
+	}
+
+	if !ok {
+		err = n.cursors.set(key, from)
+	}
+
+	return err
+}
+
+func (n *Logs) countLogs(address *AddressLogs, labels []string, logs []*api.ETHLog, decimals int) {
+	events := n.LogsEvents.WithLabelValues(labels...)
+	amount := n.LogsAmount.WithLabelValues(labels...)
+
+	for _, entry := range logs {
+		if entry.Removed {
+			continue
+		}
+
+		events.Inc()
+
+		if address.Event == "" || !logHasAmount(entry) {
+			continue
+		}
+
+		value, err := decodeABIUint256(entry.Data)
+		if err != nil {
+			n.log.WithError(err).WithField("transaction", entry.TransactionHash).Debug("Failed to decode event log amount")
+
+			continue
+		}
+
+		amount.Add(tokenAmountToFloat64(value, decimals))
+	}
+}
+
+// logHasAmount reports whether a transfer or approval log carries an ERC20
+// amount. ERC721 events index the token ID and carry no data.
+func logHasAmount(entry *api.ETHLog) bool {
+	return !entry.Removed && len(entry.Topics) == 3
+}
+
+func logsHaveAmounts(results [][]*api.ETHLog) bool {
+	for _, logs := range results {
+		if slices.ContainsFunc(logs, logHasAmount) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getBlockNumber returns the number of the most recent block.
+func getBlockNumber(ctx context.Context, client api.ExecutionClient) (uint64, error) {
+	rsp, err := client.ETHBlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimPrefix(rsp, "0x"), 16, 64)
+}
+
+// logsCursorStore holds the next block to scan per execution client and
+// address name, persisting it to a JSON file when a path is set.
+type logsCursorStore struct {
+	mu      sync.Mutex
+	path    string
+	cursors map[string]uint64
+}
+
+func newLogsCursorStore(stateDir string) *logsCursorStore {
+	store := &logsCursorStore{
+		cursors: make(map[string]uint64),
+	}
+
+	if stateDir != "" {
+		store.path = filepath.Join(stateDir, logsCursorFile)
+	}
+
+	return store
+}
+
+func (s *logsCursorStore) load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return json.Unmarshal(data, &s.cursors)
+}
+
+func (s *logsCursorStore) get(key string) (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursor, ok := s.cursors[key]
+
+	return cursor, ok
+}
+
+// set updates a cursor and atomically rewrites the cursor file.
+func (s *logsCursorStore) set(key string, cursor uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursors[key] = cursor
+
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.cursors)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
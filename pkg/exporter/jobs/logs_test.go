@@ -0,0 +1,371 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+)
+
+func newTestTransferLog(block uint64, from, to string, amount int64) *api.ETHLog {
+	fromTopic, _ := encodeAddressArgument(from)
+	toTopic, _ := encodeAddressArgument(to)
+
+	return &api.ETHLog{
+		Address:     testContractAAddress,
+		Topics:      []string{logsTransferTopic, "0x" + fromTopic, "0x" + toTopic},
+		Data:        "0x" + formatABIWord(big.NewInt(amount)),
+		BlockNumber: "0x" + strconv.FormatUint(block, 16),
+	}
+}
+
+func newTestLogsClient() *mockExecutionClient {
+	return &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			tokenSymbolSelector:   encodeABIStringReturn("USDC"),
+			tokenDecimalsSelector: encodeABIUintReturn(6),
+		},
+		ethLogs: []*api.ETHLog{
+			newTestTransferLog(100, testHolder2Address, testHolder1Address, 1500000),
+			newTestTransferLog(1500, testHolder2Address, testHolder1Address, 500000),
+			newTestTransferLog(2100, testHolder1Address, testHolder2Address, 250000),
+			newTestTransferLog(2100, testHolder2Address, nativeTokenAddress, 9000000),
+		},
+	}
+}
+
+func TestLogs_getLogs(t *testing.T) {
+	stateDir := t.TempDir()
+	fromBlock := uint64(100)
+
+	address := &AddressLogs{
+		Name:      "Treasury USDC",
+		Address:   testHolder1Address,
+		Contract:  testContractAAddress,
+		Event:     LogsEventTransfer,
+		FromBlock: &fromBlock,
+		Labels:    map[string]string{},
+	}
+
+	mockClient := newTestLogsClient()
+
+	logs := NewLogs(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"logs_get",
+		map[string]string{},
+		[]*AddressLogs{address},
+		NewTokenMetadataCache(),
+		stateDir,
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, logs.cursors.load())
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 2100))
+
+	// Blocks 100 to 2100 are scanned in three ranges, each with an out and an in filter.
+	require.Len(t, mockClient.ethGetLogsCalls, 6)
+	assert.Equal(t, "0x64", mockClient.ethGetLogsCalls[0].FromBlock)
+	assert.Equal(t, "0x44b", mockClient.ethGetLogsCalls[0].ToBlock)
+
+	labels := logs.getLabelValues(address, "USDC", testMockNodeName)
+	in := append(append([]string{}, labels...), logsDirectionIn)
+	out := append(append([]string{}, labels...), logsDirectionOut)
+
+	assert.InDelta(t, 2, testutil.ToFloat64(logs.LogsEvents.WithLabelValues(in...)), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(logs.LogsAmount.WithLabelValues(in...)), 0.000001)
+	assert.InDelta(t, 1, testutil.ToFloat64(logs.LogsEvents.WithLabelValues(out...)), 0)
+	assert.InDelta(t, 0.25, testutil.ToFloat64(logs.LogsAmount.WithLabelValues(out...)), 0.000001)
+	assertMetricValue(t, logs.LogsCursor, labels, 2100)
+
+	data, err := os.ReadFile(filepath.Join(stateDir, logsCursorFile))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"`+testMockNodeName+`/Treasury USDC":2101}`, string(data))
+
+	// A restarted job resumes from the persisted cursor without counting again.
+	restarted := NewLogs(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"logs_restarted",
+		map[string]string{},
+		[]*AddressLogs{address},
+		NewTokenMetadataCache(),
+		stateDir,
+		nil,
+		testRegistry(),
+	)
+
+	mockClient.ethGetLogsCalls = nil
+
+	require.NoError(t, restarted.cursors.load())
+	require.NoError(t, restarted.getLogs(context.Background(), mockClient, address, 2100))
+
+	assert.Empty(t, mockClient.ethGetLogsCalls)
+	assert.Equal(t, 0, testutil.CollectAndCount(&restarted.LogsEvents))
+}
+
+func TestLogs_getLogs_StartsAtHead(t *testing.T) {
+	address := &AddressLogs{
+		Name:     "Raw events",
+		Contract: testContractAAddress,
+		Topics:   [][]string{{logsApprovalTopic}},
+		Labels:   map[string]string{},
+	}
+
+	mockClient := newTestLogsClient()
+
+	logs := NewLogs(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"logs_head",
+		map[string]string{},
+		[]*AddressLogs{address},
+		NewTokenMetadataCache(),
+		"",
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 2100))
+	assert.Empty(t, mockClient.ethGetLogsCalls)
+
+	mockClient.ethLogs = append(mockClient.ethLogs, &api.ETHLog{
+		Address:     testContractAAddress,
+		Topics:      []string{logsApprovalTopic},
+		BlockNumber: "0x835",
+	})
+
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 2101))
+	require.Len(t, mockClient.ethGetLogsCalls, 1)
+	assert.Equal(t, "0x835", mockClient.ethGetLogsCalls[0].FromBlock)
+
+	events := logs.LogsEvents.WithLabelValues(append(logs.getLabelValues(address, "", testMockNodeName), "")...)
+	assert.InDelta(t, 1, testutil.ToFloat64(events), 0)
+	assert.Empty(t, mockClient.callLog)
+}
+
+func TestLogs_getLogs_BoundedPerTick(t *testing.T) {
+	fromBlock := uint64(0)
+
+	address := &AddressLogs{
+		Name:          "Raw events",
+		Contract:      testContractAAddress,
+		Topics:        [][]string{{logsTransferTopic}},
+		FromBlock:     &fromBlock,
+		Confirmations: 12,
+		Labels:        map[string]string{},
+	}
+
+	mockClient := newTestLogsClient()
+
+	logs := NewLogs(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"logs_bounded",
+		map[string]string{},
+		[]*AddressLogs{address},
+		NewTokenMetadataCache(),
+		"",
+		nil,
+		testRegistry(),
+	)
+
+	labels := logs.getLabelValues(address, "", testMockNodeName)
+
+	// A far back start block is caught up over several ticks.
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 25000))
+	require.Len(t, mockClient.ethGetLogsCalls, logsMaxBlocksPerTick/logsMaxBlockRange)
+	assertMetricValue(t, logs.LogsCursor, labels, logsMaxBlocksPerTick-1)
+
+	mockClient.ethGetLogsCalls = nil
+
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 25000))
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 25000))
+
+	// Blocks within the confirmations of the head are left for later ticks.
+	assertMetricValue(t, logs.LogsCursor, labels, 25000-12)
+	assert.Equal(t, "0x619c", mockClient.ethGetLogsCalls[len(mockClient.ethGetLogsCalls)-1].ToBlock)
+
+	events := logs.LogsEvents.WithLabelValues(append(append([]string{}, labels...), "")...)
+	assert.InDelta(t, 4, testutil.ToFloat64(events), 0)
+
+	mockClient.ethGetLogsCalls = nil
+
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 25000))
+	assert.Empty(t, mockClient.ethGetLogsCalls)
+}
+
+func TestLogs_getLogs_RetriesFailedRange(t *testing.T) {
+	fromBlock := uint64(100)
+
+	address := &AddressLogs{
+		Name:      "Treasury USDC",
+		Address:   testHolder1Address,
+		Contract:  testContractAAddress,
+		Event:     LogsEventTransfer,
+		FromBlock: &fromBlock,
+		Labels:    map[string]string{},
+	}
+
+	mockClient := newTestLogsClient()
+	mockClient.ethGetLogsErr = errors.New("query returned more than 10000 results")
+
+	logs := NewLogs(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"logs_retry",
+		map[string]string{},
+		[]*AddressLogs{address},
+		NewTokenMetadataCache(),
+		"",
+		nil,
+		testRegistry(),
+	)
+
+	require.Error(t, logs.getLogs(context.Background(), mockClient, address, 200))
+
+	cursor, ok := logs.cursors.get(testMockNodeName + "/" + address.Name)
+	assert.False(t, ok)
+	assert.Zero(t, cursor)
+
+	mockClient.ethGetLogsErr = nil
+
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 200))
+
+	in := append(logs.getLabelValues(address, "USDC", testMockNodeName), logsDirectionIn)
+	assert.InDelta(t, 1, testutil.ToFloat64(logs.LogsEvents.WithLabelValues(in...)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(logs.LogsError.WithLabelValues(logs.getLabelValues(address, "USDC", testMockNodeName)...)), 0)
+}
+
+func TestLogs_getLogs_ERC721(t *testing.T) {
+	fromBlock := uint64(100)
+
+	address := &AddressLogs{
+		Name:      "Positions",
+		Address:   testHolder1Address,
+		Contract:  testContractAAddress,
+		Event:     LogsEventTransfer,
+		FromBlock: &fromBlock,
+		Labels:    map[string]string{},
+	}
+
+	fromTopic, err := encodeAddressArgument(testHolder2Address)
+	require.NoError(t, err)
+
+	toTopic, err := encodeAddressArgument(testHolder1Address)
+	require.NoError(t, err)
+
+	// ERC721 contracts revert on decimals() and index the token ID.
+	mockClient := &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			tokenSymbolSelector:   encodeABIStringReturn("POS"),
+			tokenDecimalsSelector: "0xzz",
+		},
+		ethLogs: []*api.ETHLog{{
+			Address:     testContractAAddress,
+			Topics:      []string{logsTransferTopic, "0x" + fromTopic, "0x" + toTopic, "0x" + formatABIWord(big.NewInt(42))},
+			Data:        "0x",
+			BlockNumber: "0x64",
+		}},
+	}
+
+	logs := NewLogs(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"logs_erc721",
+		map[string]string{},
+		[]*AddressLogs{address},
+		NewTokenMetadataCache(),
+		"",
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, logs.cursors.load())
+	require.NoError(t, logs.getLogs(context.Background(), mockClient, address, 200))
+
+	labels := logs.getLabelValues(address, "POS", testMockNodeName)
+	in := append(append([]string{}, labels...), logsDirectionIn)
+
+	assert.InDelta(t, 1, testutil.ToFloat64(logs.LogsEvents.WithLabelValues(in...)), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(logs.LogsAmount.WithLabelValues(in...)), 0)
+	assertCallSelectors(t, mockClient.callLog, []string{tokenSymbolSelector})
+}
+
+func TestAddressLogs_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		address *AddressLogs
+		wantErr string
+	}{
+		{
+			name:    "transfer",
+			address: &AddressLogs{Name: "logs", Contract: testContractAAddress, Address: testHolder1Address, Event: LogsEventTransfer},
+		},
+		{
+			name:    "raw topics",
+			address: &AddressLogs{Name: "logs", Contract: testContractAAddress, Topics: [][]string{{logsTransferTopic}}},
+		},
+		{
+			name:    "raw topics with address",
+			address: &AddressLogs{Name: "logs", Contract: testContractAAddress, Address: testHolder1Address, Topics: [][]string{{logsTransferTopic}}},
+			wantErr: "address can only be set with an event",
+		},
+		{
+			name:    "missing contract",
+			address: &AddressLogs{Name: "logs", Event: LogsEventTransfer},
+			wantErr: "invalid contract",
+		},
+		{
+			name:    "unknown event",
+			address: &AddressLogs{Name: "logs", Contract: testContractAAddress, Event: "deposit"},
+			wantErr: "unsupported event",
+		},
+		{
+			name:    "event with topics",
+			address: &AddressLogs{Name: "logs", Contract: testContractAAddress, Event: LogsEventApproval, Topics: [][]string{{logsTransferTopic}}},
+			wantErr: "topics can only be set without an event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.address.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLogs_Name(t *testing.T) {
+	logs := &Logs{}
+	if logs.Name() != NameLogs {
+		t.Errorf("Expected name %s, got %s", NameLogs, logs.Name())
+	}
+}
@@ -0,0 +1,327 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameLSTRate = "lst_rate"
+
+	// LSTRateAdapterStETHPerToken reads stEthPerToken(), e.g. Lido wstETH.
+	LSTRateAdapterStETHPerToken = "stEthPerToken"
+	// LSTRateAdapterGetExchangeRate reads getExchangeRate(), e.g. Rocket Pool rETH.
+	LSTRateAdapterGetExchangeRate = "getExchangeRate"
+	// LSTRateAdapterExchangeRate reads exchangeRate(), e.g. Coinbase cbETH.
+	LSTRateAdapterExchangeRate = "exchangeRate"
+	// LSTRateAdapterGetPooledEthByShares reads getPooledEthByShares(1e18), e.g. Lido stETH.
+	LSTRateAdapterGetPooledEthByShares = "getPooledEthByShares"
+
+	lstRateStETHPerTokenSelector        = "0x035faf82"
+	lstRateGetExchangeRateSelector      = "0xe6aa216c"
+	lstRateExchangeRateSelector         = "0x3ba0b9a9"
+	lstRateGetPooledEthBySharesSelector = "0x7a28fb88"
+	lstRateSharesOfSelector             = "0xf5eb42dc"
+	lstRateBalanceOfSelector            = "0x70a08231"
+
+	// lstRateDecimals is the fixed point precision of every supported rate.
+	lstRateDecimals = 18
+)
+
+// lstRateAdapters maps each adapter to the call data returning the ETH value of one whole token or share.
+var lstRateAdapters = map[string]string{
+	LSTRateAdapterStETHPerToken:        lstRateStETHPerTokenSelector,
+	LSTRateAdapterGetExchangeRate:      lstRateGetExchangeRateSelector,
+	LSTRateAdapterExchangeRate:         lstRateExchangeRateSelector,
+	LSTRateAdapterGetPooledEthByShares: lstRateGetPooledEthBySharesSelector + formatABIWord(new(big.Int).Exp(big.NewInt(10), big.NewInt(lstRateDecimals), nil)),
+}
+
+// LSTRate exposes metrics for liquid staking token exchange rates.
+type LSTRate struct {
+	*tickTracker
+
+	clients              []api.ExecutionClient
+	log                  logrus.FieldLogger
+	LSTRateRate          prometheus.GaugeVec
+	LSTRateUnderlyingETH prometheus.GaugeVec
+	LSTRateShares        prometheus.GaugeVec
+	LSTRateError         prometheus.CounterVec
+	checkInterval        time.Duration
+	addresses            []*AddressLSTRate
+	labelsMap            map[string]int
+	tokens               *TokenMetadataCache
+	snapshots            *snapshot.Store
+}
+
+type AddressLSTRate struct {
+	// Address is the token holder. When empty only the rate is exported.
+	Address  string            `yaml:"address"`
+	Contract string            `yaml:"contract"`
+	Adapter  string            `yaml:"adapter"`
+	Name     string            `yaml:"name"`
+	Labels   map[string]string `yaml:"labels"`
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressLSTRate) GetName() string { return a.Name }
+
+// Validate checks the adapter is supported.
+func (a *AddressLSTRate) Validate() error {
+	if _, ok := lstRateAdapters[a.Adapter]; !ok {
+		return fmt.Errorf("lst rate %s: unsupported adapter %q, expected one of %s", a.Name, a.Adapter, strings.Join(slices.Sorted(maps.Keys(lstRateAdapters)), ", "))
+	}
+
+	return nil
+}
+
+func (n *LSTRate) Name() string {
+	return NameLSTRate
+}
+
+// NewLSTRate returns a new LSTRate instance.
+func NewLSTRate(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressLSTRate, tokens *TokenMetadataCache, snapshots *snapshot.Store, registerer prometheus.Registerer) LSTRate {
+	namespace += "_" + NameLSTRate
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelAddress:   1,
+		LabelContract:  2,
+		LabelSymbol:    3,
+		LabelExecution: 4,
+	}
+
+	for address := range addresses {
+		for label := range addresses[address].Labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	newGaugeVec := func(name, help string) prometheus.GaugeVec {
+		return *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        name,
+				Help:        help,
+				ConstLabels: constLabels,
+			},
+			labels,
+		)
+	}
+
+	instance := LSTRate{
+		clients:       clients,
+		log:           log.WithField("module", NameLSTRate),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
+		addresses:     addresses,
+		checkInterval: checkInterval,
+		labelsMap:     labelsMap,
+		tokens:        tokens,
+		LSTRateRate: newGaugeVec(
+			"rate",
+			"The ETH value of one whole liquid staking token or share.",
+		),
+		LSTRateUnderlyingETH: newGaugeVec(
+			"underlying_eth",
+			"The ETH value of the liquid staking tokens held by an address.",
+		),
+		LSTRateShares: newGaugeVec(
+			"shares",
+			"The decimal adjusted Lido shares held by an address.",
+		),
+		LSTRateError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting the exchange rate of a liquid staking token.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.LSTRateRate)
+	registerer.MustRegister(instance.LSTRateUnderlyingETH)
+	registerer.MustRegister(instance.LSTRateShares)
+	registerer.MustRegister(instance.LSTRateError)
+
+	return instance
+}
+
+func (n *LSTRate) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *LSTRate) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		for _, address := range n.addresses {
+			err := n.getRate(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get liquid staking token rate")
+			}
+		}
+	}
+}
+
+func (n *LSTRate) getLabelValues(address *AddressLSTRate, symbol, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		if address.Labels != nil && address.Labels[label] != "" {
+			values[index] = address.Labels[label]
+		} else {
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelAddress:
+				values[index] = address.Address
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelSymbol:
+				values[index] = symbol
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+// getRate exports the exchange rate of the token, and the ETH value of the
+// holder's tokens when a holder address is configured. Lido stETH holders
+// are valued by their shares, which are exported as well.
+func (n *LSTRate) getRate(ctx context.Context, client api.ExecutionClient, address *AddressLSTRate) error {
+	var err error
+
+	symbol := ""
+
+	defer func() {
+		if err != nil {
+			n.LSTRateError.WithLabelValues(n.getLabelValues(address, symbol, client.Name())...).Inc()
+			n.snapshots.SetError(NameLSTRate, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	rateData, ok := lstRateAdapters[address.Adapter]
+	if !ok {
+		err = fmt.Errorf("unsupported adapter %q", address.Adapter)
+
+		return err
+	}
+
+	symbol, err = n.tokens.Symbol(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	rate, err := ethCallUint256(ctx, client, address.Contract, rateData)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", address.Adapter, err)
+	}
+
+	labels := n.getLabelValues(address, symbol, client.Name())
+
+	if address.Address != "" {
+		var holder string
+
+		holder, err = encodeAddressArgument(address.Address)
+		if err != nil {
+			return err
+		}
+
+		if address.Adapter == LSTRateAdapterGetPooledEthByShares {
+			err = n.setSharesValue(ctx, client, address.Contract, holder, labels)
+		} else {
+			err = n.setBalanceValue(ctx, client, address.Contract, holder, rate, labels)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	rateValue := tokenAmountToFloat64(rate, lstRateDecimals)
+	n.LSTRateRate.WithLabelValues(labels...).Set(rateValue)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameLSTRate,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Address:   address.Address,
+		Contract:  address.Contract,
+		Symbol:    symbol,
+		Value:     rateValue,
+	}, time.Now())
+
+	return nil
+}
+
+func (n *LSTRate) setSharesValue(ctx context.Context, client api.ExecutionClient, contract, holder string, labels []string) error {
+	shares, err := ethCallUint256(ctx, client, contract, lstRateSharesOfSelector+holder)
+	if err != nil {
+		return fmt.Errorf("failed to get shares: %w", err)
+	}
+
+	pooledEth, err := ethCallUint256(ctx, client, contract, lstRateGetPooledEthBySharesSelector+formatABIWord(shares))
+	if err != nil {
+		return fmt.Errorf("failed to get pooled eth by shares: %w", err)
+	}
+
+	n.LSTRateShares.WithLabelValues(labels...).Set(tokenAmountToFloat64(shares, lstRateDecimals))
+	n.LSTRateUnderlyingETH.WithLabelValues(labels...).Set(tokenAmountToFloat64(pooledEth, lstRateDecimals))
+
+	return nil
+}
+
+func (n *LSTRate) setBalanceValue(ctx context.Context, client api.ExecutionClient, contract, holder string, rate *big.Int, labels []string) error {
+	decimals, err := n.tokens.Decimals(ctx, client, contract)
+	if err != nil {
+		return err
+	}
+
+	balance, err := ethCallUint256(ctx, client, contract, lstRateBalanceOfSelector+holder)
+	if err != nil {
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	value := new(big.Int).Mul(balance, rate)
+	value.Quo(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+
+	n.LSTRateUnderlyingETH.WithLabelValues(labels...).Set(tokenAmountToFloat64(value, lstRateDecimals))
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLSTRate_getRate(t *testing.T) {
+	oneEther := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	ether := func(numerator, denominator int64) string {
+		value := new(big.Int).Mul(oneEther, big.NewInt(numerator))
+
+		return "0x" + formatABIWord(value.Quo(value, big.NewInt(denominator)))
+	}
+
+	holder, err := encodeAddressArgument(testHolder1Address)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		adapter       string
+		symbol        string
+		responses     map[string]string
+		wantRate      float64
+		wantValue     float64
+		wantShares    float64
+		wantSelectors []string
+	}{
+		{
+			name:    "rETH exchange rate",
+			adapter: LSTRateAdapterGetExchangeRate,
+			symbol:  "rETH",
+			responses: map[string]string{
+				tokenSymbolSelector:            encodeABIStringReturn("rETH"),
+				tokenDecimalsSelector:          encodeABIUintReturn(18),
+				lstRateGetExchangeRateSelector: ether(11, 10),
+				lstRateBalanceOfSelector:       ether(2, 1),
+			},
+			wantRate:  1.1,
+			wantValue: 2.2,
+			wantSelectors: []string{
+				tokenSymbolSelector,
+				lstRateGetExchangeRateSelector,
+				tokenDecimalsSelector,
+				lstRateBalanceOfSelector,
+			},
+		},
+		{
+			name:    "stETH pooled eth by shares",
+			adapter: LSTRateAdapterGetPooledEthByShares,
+			symbol:  "stETH",
+			responses: map[string]string{
+				tokenSymbolSelector: encodeABIStringReturn("stETH"),
+				lstRateAdapters[LSTRateAdapterGetPooledEthByShares]:                                            ether(12, 10),
+				lstRateSharesOfSelector + holder:                                                               ether(5, 1),
+				lstRateGetPooledEthBySharesSelector + formatABIWord(new(big.Int).Mul(oneEther, big.NewInt(5))): ether(6, 1),
+			},
+			wantRate:   1.2,
+			wantValue:  6,
+			wantShares: 5,
+			wantSelectors: []string{
+				tokenSymbolSelector,
+				lstRateGetPooledEthBySharesSelector,
+				lstRateSharesOfSelector,
+				lstRateGetPooledEthBySharesSelector,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := &AddressLSTRate{
+				Name:     tt.name,
+				Address:  testHolder1Address,
+				Contract: testContractAAddress,
+				Adapter:  tt.adapter,
+				Labels:   map[string]string{},
+			}
+
+			mockClient := &mockExecutionClient{ethCallResponses: tt.responses}
+
+			lstRate := NewLSTRate(
+				mockClients(mockClient),
+				testLogger(),
+				15*time.Second,
+				"lst_rate_get_"+strconv.Itoa(i),
+				map[string]string{},
+				[]*AddressLSTRate{address},
+				NewTokenMetadataCache(),
+				nil,
+				testRegistry(),
+			)
+
+			err := lstRate.getRate(context.Background(), mockClient, address)
+			require.NoError(t, err)
+
+			labels := lstRate.getLabelValues(address, tt.symbol, testMockNodeName)
+
+			assertMetricValue(t, lstRate.LSTRateRate, labels, tt.wantRate)
+			assertMetricValue(t, lstRate.LSTRateUnderlyingETH, labels, tt.wantValue)
+			assertMetricValue(t, lstRate.LSTRateShares, labels, tt.wantShares)
+			assertCallSelectors(t, mockClient.callLog, tt.wantSelectors)
+		})
+	}
+}
+
+func TestLSTRate_Name(t *testing.T) {
+	lstRate := &LSTRate{}
+	if lstRate.Name() != NameLSTRate {
+		t.Errorf("Expected name %s, got %s", NameLSTRate, lstRate.Name())
+	}
+}
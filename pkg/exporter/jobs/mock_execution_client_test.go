@@ -2,6 +2,10 @@ package jobs
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
 )
@@ -23,6 +27,8 @@ type mockExecutionClient struct {
 	decimalsResponse           string
 	withdrawalRequestsResponse string
 	withdrawalStatusResponse   string
+	unfinalizedStETHResponse   string
+	lastFinalizedResponse      string
 	balanceOfError             error
 	convertToAssetsError       error
 	symbolError                error
@@ -30,6 +36,34 @@ type mockExecutionClient struct {
 	ethGetBalanceResponse      string
 	ethGetBalanceError         error
 	ethGetBalanceCalls         int
+	// ethGetTransactionCountResponses maps a block tag to a transaction count response.
+	ethGetTransactionCountResponses map[string]string
+	ethGetTransactionCountCalls     int
+	ethGetCodeResponse              string
+	ethGetCodeError                 error
+	ethGetCodeCalls                 int
+	// ethGetStorageAtResponses maps a storage slot to a storage value response.
+	ethGetStorageAtResponses map[string]string
+	ethGetStorageAtCalls     []string
+	// ethCallResponses maps full call data, or a 4 byte selector, to a response.
+	ethCallResponses       map[string]string
+	ethBlockNumberResponse string
+	ethBlockNumberError    error
+	// ethLogs are filtered by block range, address and topics on each eth_getLogs call.
+	ethLogs         []*api.ETHLog
+	ethGetLogsErr   error
+	ethGetLogsCalls []*api.ETHGetLogsFilter
+	// ethBlocks maps a block number to an eth_getBlockByNumber response.
+	ethBlocks                 map[uint64]*api.ETHBlock
+	ethGetBlockByNumberCalls  []string
+	ethReceipts               map[string]*api.ETHTransactionReceipt
+	ethGetReceiptCalls        []string
+	ethChainIDResponse        string
+	ethGasPriceResponse       string
+	ethMaxPriorityFeeResponse string
+	ethMaxPriorityFeeError    error
+	ethFeeHistoryResponse     *api.ETHFeeHistory
+	ethFeeHistoryCalls        int
 }
 
 type mockCall struct {
@@ -56,6 +90,16 @@ func (m *mockExecutionClient) ETHCall(_ context.Context, transaction *api.ETHCal
 		data: *transaction.Data,
 	})
 
+	if rsp, ok := m.ethCallResponses[*transaction.Data]; ok {
+		return rsp, nil
+	}
+
+	if len(*transaction.Data) >= 10 {
+		if rsp, ok := m.ethCallResponses[(*transaction.Data)[:10]]; ok {
+			return rsp, nil
+		}
+	}
+
 	for _, handler := range m.ethCallHandlers() {
 		if len(*transaction.Data) >= 10 && (*transaction.Data)[:10] == handler.selector {
 			return handler.handle()
@@ -107,6 +151,14 @@ func (m *mockExecutionClient) ethCallHandlers() []mockETHCallHandler {
 			selector: "0x00fdd58e",
 			handle:   m.handleBalanceOf,
 		},
+		{
+			selector: "0xd0fb84e8",
+			handle:   m.handleUnfinalizedStETH,
+		},
+		{
+			selector: "0x4f069a13",
+			handle:   m.handleLastFinalizedRequestID,
+		},
 	}
 }
 
@@ -186,6 +238,22 @@ func (m *mockExecutionClient) handleWithdrawalStatus() (string, error) {
 	return "0x00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000000", nil
 }
 
+func (m *mockExecutionClient) handleUnfinalizedStETH() (string, error) {
+	if m.unfinalizedStETHResponse != "" {
+		return m.unfinalizedStETHResponse, nil
+	}
+
+	return "0x0000000000000000000000000000000000000000000000000000000000000000", nil
+}
+
+func (m *mockExecutionClient) handleLastFinalizedRequestID() (string, error) {
+	if m.lastFinalizedResponse != "" {
+		return m.lastFinalizedResponse, nil
+	}
+
+	return "0x0000000000000000000000000000000000000000000000000000000000000000", nil
+}
+
 func (m *mockExecutionClient) ETHGetBalance(_ context.Context, address string, block string) (string, error) {
 	m.ethGetBalanceCalls++
 
@@ -200,7 +268,164 @@ func (m *mockExecutionClient) ETHGetBalance(_ context.Context, address string, b
 	return "0x0", nil
 }
 
+func (m *mockExecutionClient) ETHGetTransactionCount(_ context.Context, address string, block string) (string, error) {
+	m.ethGetTransactionCountCalls++
+
+	if rsp, ok := m.ethGetTransactionCountResponses[block]; ok {
+		return rsp, nil
+	}
+
+	return "0x0", nil
+}
+
+func (m *mockExecutionClient) ETHGetCode(_ context.Context, address string, block string) (string, error) {
+	m.ethGetCodeCalls++
+
+	if m.ethGetCodeError != nil {
+		return "", m.ethGetCodeError
+	}
+
+	if m.ethGetCodeResponse != "" {
+		return m.ethGetCodeResponse, nil
+	}
+
+	return "0x", nil
+}
+
+func (m *mockExecutionClient) ETHGetStorageAt(_ context.Context, address string, slot string, block string) (string, error) {
+	m.ethGetStorageAtCalls = append(m.ethGetStorageAtCalls, slot)
+
+	if rsp, ok := m.ethGetStorageAtResponses[slot]; ok {
+		return rsp, nil
+	}
+
+	return "0x0000000000000000000000000000000000000000000000000000000000000000", nil
+}
+
+func (m *mockExecutionClient) ETHBlockNumber(_ context.Context) (string, error) {
+	if m.ethBlockNumberError != nil {
+		return "", m.ethBlockNumberError
+	}
+
+	if m.ethBlockNumberResponse != "" {
+		return m.ethBlockNumberResponse, nil
+	}
+
+	return "0x0", nil
+}
+
+func (m *mockExecutionClient) ETHGetLogs(_ context.Context, filter *api.ETHGetLogsFilter) ([]*api.ETHLog, error) {
+	m.ethGetLogsCalls = append(m.ethGetLogsCalls, filter)
+
+	if m.ethGetLogsErr != nil {
+		return nil, m.ethGetLogsErr
+	}
+
+	from, _ := strconv.ParseUint(strings.TrimPrefix(filter.FromBlock, "0x"), 16, 64)
+	to, _ := strconv.ParseUint(strings.TrimPrefix(filter.ToBlock, "0x"), 16, 64)
+
+	logs := make([]*api.ETHLog, 0)
+
+	for _, log := range m.ethLogs {
+		block, _ := strconv.ParseUint(strings.TrimPrefix(log.BlockNumber, "0x"), 16, 64)
+		if block < from || block > to {
+			continue
+		}
+
+		if len(filter.Address) > 0 && !slices.ContainsFunc(filter.Address, func(address string) bool {
+			return strings.EqualFold(address, log.Address)
+		}) {
+			continue
+		}
+
+		if mockLogMatchesTopics(log, filter.Topics) {
+			logs = append(logs, log)
+		}
+	}
+
+	return logs, nil
+}
+
+func mockLogMatchesTopics(log *api.ETHLog, topics [][]string) bool {
+	for i, options := range topics {
+		if options == nil {
+			continue
+		}
+
+		if i >= len(log.Topics) || !slices.ContainsFunc(options, func(topic string) bool {
+			return strings.EqualFold(topic, log.Topics[i])
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // mockClients wraps a single mock client in a slice for use with job constructors.
 func mockClients(m *mockExecutionClient) []api.ExecutionClient {
 	return []api.ExecutionClient{m}
 }
+
+func (m *mockExecutionClient) ETHGetBlockByNumber(_ context.Context, block string, _ bool) (*api.ETHBlock, error) {
+	m.ethGetBlockByNumberCalls = append(m.ethGetBlockByNumberCalls, block)
+
+	number, err := strconv.ParseUint(strings.TrimPrefix(block, "0x"), 16, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp, ok := m.ethBlocks[number]; ok {
+		return rsp, nil
+	}
+
+	return nil, fmt.Errorf("block %s not found", block)
+}
+
+func (m *mockExecutionClient) ETHGetTransactionReceipt(_ context.Context, hash string) (*api.ETHTransactionReceipt, error) {
+	m.ethGetReceiptCalls = append(m.ethGetReceiptCalls, hash)
+
+	if rsp, ok := m.ethReceipts[hash]; ok {
+		return rsp, nil
+	}
+
+	return nil, fmt.Errorf("receipt of transaction %s not found", hash)
+}
+
+func (m *mockExecutionClient) ETHChainID(_ context.Context) (string, error) {
+	if m.ethChainIDResponse != "" {
+		return m.ethChainIDResponse, nil
+	}
+
+	return "0x1", nil
+}
+
+func (m *mockExecutionClient) ETHGasPrice(_ context.Context) (string, error) {
+	if m.ethGasPriceResponse != "" {
+		return m.ethGasPriceResponse, nil
+	}
+
+	return "0x0", nil
+}
+
+func (m *mockExecutionClient) ETHMaxPriorityFeePerGas(_ context.Context) (string, error) {
+	if m.ethMaxPriorityFeeError != nil {
+		return "", m.ethMaxPriorityFeeError
+	}
+
+	if m.ethMaxPriorityFeeResponse != "" {
+		return m.ethMaxPriorityFeeResponse, nil
+	}
+
+	return "0x0", nil
+}
+
+func (m *mockExecutionClient) ETHFeeHistory(_ context.Context, _ uint64, _ string, _ []float64) (*api.ETHFeeHistory, error) {
+	m.ethFeeHistoryCalls++
+
+	if m.ethFeeHistoryResponse != nil {
+		return m.ethFeeHistoryResponse, nil
+	}
+
+	return &api.ETHFeeHistory{}, nil
+}
@@ -47,6 +47,9 @@ func TestAccount_MultiClient_QueriesAllNodes(t *testing.T) {
 		"multi_client_account",
 		map[string]string{},
 		addresses,
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
@@ -103,6 +106,9 @@ func TestAccount_MultiClient_OneNodeFails(t *testing.T) {
 		"multi_client_error",
 		map[string]string{},
 		addresses,
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
@@ -158,6 +164,9 @@ func TestAccount_MultiClient_DifferentBalances(t *testing.T) {
 		"multi_client_diverge",
 		map[string]string{},
 		addresses,
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
@@ -206,14 +215,18 @@ func TestERC20_MultiClient_QueriesAllNodes(t *testing.T) {
 		"multi_client_erc20",
 		map[string]string{},
 		addresses,
+		NewTokenMetadataCache(),
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
 	erc20.tick(ctx)
 
-	// Each client should make 2 calls per address (balanceOf + symbol)
-	assert.Len(t, client1.callLog, 2, "node-1 should make 2 calls")
-	assert.Len(t, client2.callLog, 2, "node-2 should make 2 calls")
+	// Each client should make 3 calls per address (balanceOf + symbol + totalSupply)
+	assert.Len(t, client1.callLog, 3, "node-1 should make 3 calls")
+	assert.Len(t, client2.callLog, 3, "node-2 should make 3 calls")
 }
 
 func TestAccount_Start_ContextCancellation(t *testing.T) {
@@ -236,6 +249,9 @@ func TestAccount_Start_ContextCancellation(t *testing.T) {
 		[]*AddressAccount{
 			{Name: "test", Address: testHolder1Address, Labels: map[string]string{}},
 		},
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -281,6 +297,9 @@ func TestAccount_ExecutionLabelInMetrics(t *testing.T) {
 		[]*AddressAccount{
 			{Name: "addr1", Address: testHolder1Address, Labels: map[string]string{}},
 		},
+		nil,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
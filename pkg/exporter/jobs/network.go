@@ -0,0 +1,325 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+)
+
+const (
+	NameNetwork = "network"
+
+	// networkMaxFeeHistoryBlocks is the most blocks execution clients serve in a single eth_feeHistory call.
+	networkMaxFeeHistoryBlocks = 1024
+)
+
+// Network exposes gas and fee market metrics per execution node.
+type Network struct {
+	*tickTracker
+
+	clients                []api.ExecutionClient
+	log                    logrus.FieldLogger
+	NetworkGasPrice        prometheus.GaugeVec
+	NetworkMaxPriorityFee  prometheus.GaugeVec
+	NetworkBaseFee         prometheus.GaugeVec
+	NetworkNextBaseFee     prometheus.GaugeVec
+	NetworkBlobBaseFee     prometheus.GaugeVec
+	NetworkNextBlobBaseFee prometheus.GaugeVec
+	NetworkGasUsedRatio    prometheus.GaugeVec
+	NetworkPriorityFee     prometheus.GaugeVec
+	NetworkBlockNumber     prometheus.GaugeVec
+	NetworkChainID         prometheus.GaugeVec
+	NetworkError           prometheus.CounterVec
+	checkInterval          time.Duration
+	config                 *NetworkConfig
+}
+
+// NetworkConfig configures the network job.
+type NetworkConfig struct {
+	Enabled           bool      `yaml:"enabled"`
+	FeeHistoryBlocks  uint64    `yaml:"feeHistoryBlocks" default:"20"`
+	RewardPercentiles []float64 `yaml:"rewardPercentiles" default:"[10,50,90]"`
+}
+
+// Validate checks the fee history window and reward percentiles accepted by eth_feeHistory.
+func (c *NetworkConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.FeeHistoryBlocks == 0 || c.FeeHistoryBlocks > networkMaxFeeHistoryBlocks {
+		return fmt.Errorf("network: feeHistoryBlocks must be between 1 and %d", networkMaxFeeHistoryBlocks)
+	}
+
+	for i, percentile := range c.RewardPercentiles {
+		if percentile < 0 || percentile > 100 {
+			return fmt.Errorf("network: reward percentile %v must be between 0 and 100", percentile)
+		}
+
+		if i > 0 && percentile <= c.RewardPercentiles[i-1] {
+			return errors.New("network: reward percentiles must be in ascending order")
+		}
+	}
+
+	return nil
+}
+
+func (n *Network) Name() string {
+	return NameNetwork
+}
+
+// NewNetwork returns a new Network instance.
+func NewNetwork(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, config *NetworkConfig, registerer prometheus.Registerer) Network {
+	namespace += "_" + NameNetwork
+
+	labels := []string{LabelExecution}
+
+	newGaugeVec := func(name, help string, labelNames []string) prometheus.GaugeVec {
+		return *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        name,
+				Help:        help,
+				ConstLabels: constLabels,
+			},
+			labelNames,
+		)
+	}
+
+	instance := Network{
+		clients:       clients,
+		log:           log.WithField("module", NameNetwork),
+		tickTracker:   newTickTracker(),
+		checkInterval: checkInterval,
+		config:        config,
+		NetworkGasPrice: newGaugeVec(
+			"gas_price",
+			"The gas price in wei suggested by an execution node.",
+			labels,
+		),
+		NetworkMaxPriorityFee: newGaugeVec(
+			"max_priority_fee_per_gas",
+			"The priority fee per gas in wei suggested by an execution node.",
+			labels,
+		),
+		NetworkBaseFee: newGaugeVec(
+			"base_fee_per_gas",
+			"The base fee per gas in wei of the latest block.",
+			labels,
+		),
+		NetworkNextBaseFee: newGaugeVec(
+			"next_base_fee_per_gas",
+			"The base fee per gas in wei of the next block.",
+			labels,
+		),
+		NetworkBlobBaseFee: newGaugeVec(
+			"blob_base_fee_per_gas",
+			"The base fee per blob gas in wei of the latest block.",
+			labels,
+		),
+		NetworkNextBlobBaseFee: newGaugeVec(
+			"next_blob_base_fee_per_gas",
+			"The base fee per blob gas in wei of the next block.",
+			labels,
+		),
+		NetworkGasUsedRatio: newGaugeVec(
+			"gas_used_ratio",
+			"The ratio of gas used to the gas limit of the latest block.",
+			labels,
+		),
+		NetworkPriorityFee: newGaugeVec(
+			"priority_fee_per_gas",
+			"The priority fee per gas in wei paid at a percentile of the block gas used, averaged over the fee history blocks.",
+			append(append([]string{}, labels...), LabelPercentile),
+		),
+		NetworkBlockNumber: newGaugeVec(
+			"block_number",
+			"The latest block number of an execution node.",
+			labels,
+		),
+		NetworkChainID: newGaugeVec(
+			"chain_id",
+			"The chain ID of an execution node.",
+			labels,
+		),
+		NetworkError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting the gas and fee market of an execution node.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.NetworkGasPrice)
+	registerer.MustRegister(instance.NetworkMaxPriorityFee)
+	registerer.MustRegister(instance.NetworkBaseFee)
+	registerer.MustRegister(instance.NetworkNextBaseFee)
+	registerer.MustRegister(instance.NetworkBlobBaseFee)
+	registerer.MustRegister(instance.NetworkNextBlobBaseFee)
+	registerer.MustRegister(instance.NetworkGasUsedRatio)
+	registerer.MustRegister(instance.NetworkPriorityFee)
+	registerer.MustRegister(instance.NetworkBlockNumber)
+	registerer.MustRegister(instance.NetworkChainID)
+	registerer.MustRegister(instance.NetworkError)
+
+	return instance
+}
+
+func (n *Network) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *Network) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		if err := n.getChain(ctx, client); err != nil {
+			n.log.WithError(err).WithField(LabelExecution, client.Name()).Error("Failed to get block number and chain ID")
+		}
+
+		if err := n.getGasPrices(ctx, client); err != nil {
+			n.log.WithError(err).WithField(LabelExecution, client.Name()).Error("Failed to get gas prices")
+		}
+
+		if err := n.getFeeHistory(ctx, client); err != nil {
+			n.log.WithError(err).WithField(LabelExecution, client.Name()).Error("Failed to get fee history")
+		}
+	}
+}
+
+func (n *Network) getChain(ctx context.Context, client api.ExecutionClient) error {
+	var err error
+
+	defer func() {
+		if err != nil {
+			n.NetworkError.WithLabelValues(client.Name()).Inc()
+		}
+	}()
+
+	blockNumber, err := client.ETHBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	chainID, err := client.ETHChainID(ctx)
+	if err != nil {
+		return err
+	}
+
+	n.NetworkBlockNumber.WithLabelValues(client.Name()).Set(hexStringToFloat64(blockNumber))
+	n.NetworkChainID.WithLabelValues(client.Name()).Set(hexStringToFloat64(chainID))
+
+	return nil
+}
+
+func (n *Network) getGasPrices(ctx context.Context, client api.ExecutionClient) error {
+	var err error
+
+	defer func() {
+		if err != nil {
+			n.NetworkError.WithLabelValues(client.Name()).Inc()
+		}
+	}()
+
+	gasPrice, err := client.ETHGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+
+	n.NetworkGasPrice.WithLabelValues(client.Name()).Set(hexStringToFloat64(gasPrice))
+
+	maxPriorityFee, err := client.ETHMaxPriorityFeePerGas(ctx)
+	if err != nil {
+		return err
+	}
+
+	n.NetworkMaxPriorityFee.WithLabelValues(client.Name()).Set(hexStringToFloat64(maxPriorityFee))
+
+	return nil
+}
+
+// getFeeHistory exports the base fees and gas used ratio of the latest block
+// and the average priority fee per percentile over the fee history blocks.
+func (n *Network) getFeeHistory(ctx context.Context, client api.ExecutionClient) error {
+	var err error
+
+	defer func() {
+		if err != nil {
+			n.NetworkError.WithLabelValues(client.Name()).Inc()
+		}
+	}()
+
+	history, err := client.ETHFeeHistory(ctx, n.config.FeeHistoryBlocks, "latest", n.config.RewardPercentiles)
+	if err != nil {
+		return err
+	}
+
+	blocks := len(history.GasUsedRatio)
+	if blocks == 0 || len(history.BaseFeePerGas) != blocks+1 {
+		err = fmt.Errorf("unexpected fee history of %d blocks with %d base fees", blocks, len(history.BaseFeePerGas))
+
+		return err
+	}
+
+	n.NetworkBaseFee.WithLabelValues(client.Name()).Set(hexStringToFloat64(history.BaseFeePerGas[blocks-1]))
+	n.NetworkNextBaseFee.WithLabelValues(client.Name()).Set(hexStringToFloat64(history.BaseFeePerGas[blocks]))
+	n.NetworkGasUsedRatio.WithLabelValues(client.Name()).Set(history.GasUsedRatio[blocks-1])
+
+	// Blob base fees are only returned by execution nodes since Cancun.
+	if len(history.BaseFeePerBlobGas) == blocks+1 {
+		n.NetworkBlobBaseFee.WithLabelValues(client.Name()).Set(hexStringToFloat64(history.BaseFeePerBlobGas[blocks-1]))
+		n.NetworkNextBlobBaseFee.WithLabelValues(client.Name()).Set(hexStringToFloat64(history.BaseFeePerBlobGas[blocks]))
+	}
+
+	for i, percentile := range n.config.RewardPercentiles {
+		total := new(big.Int)
+
+		for _, rewards := range history.Reward {
+			if i >= len(rewards) {
+				err = fmt.Errorf("missing reward for percentile %v", percentile)
+
+				return err
+			}
+
+			var reward *big.Int
+
+			reward, err = hexStringToBigInt(rewards[i])
+			if err != nil {
+				return err
+			}
+
+			total.Add(total, reward)
+		}
+
+		if len(history.Reward) == 0 {
+			continue
+		}
+
+		average := bigIntToFloat64(total) / float64(len(history.Reward))
+		labels := []string{client.Name(), strconv.FormatFloat(percentile, 'f', -1, 64)}
+
+		n.NetworkPriorityFee.WithLabelValues(labels...).Set(average)
+	}
+
+	return nil
+}
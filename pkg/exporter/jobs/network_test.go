@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+)
+
+func newTestNetworkConfig() *NetworkConfig {
+	return &NetworkConfig{
+		Enabled:           true,
+		FeeHistoryBlocks:  2,
+		RewardPercentiles: []float64{10, 50.5},
+	}
+}
+
+func TestNetwork_tick(t *testing.T) {
+	mockClient := &mockExecutionClient{
+		ethBlockNumberResponse:    "0x1312d00",
+		ethChainIDResponse:        "0x4268",
+		ethGasPriceResponse:       "0x3b9aca00",
+		ethMaxPriorityFeeResponse: "0x5f5e100",
+		ethFeeHistoryResponse: &api.ETHFeeHistory{
+			OldestBlock:       "0x1312cff",
+			BaseFeePerGas:     []string{"0x64", "0xc8", "0x12c"},
+			GasUsedRatio:      []float64{0.9, 0.25},
+			BaseFeePerBlobGas: []string{"0x1", "0x2", "0x3"},
+			Reward:            [][]string{{"0xa", "0x64"}, {"0x14", "0xc8"}},
+		},
+	}
+
+	network := NewNetwork(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"network_tick",
+		map[string]string{},
+		newTestNetworkConfig(),
+		testRegistry(),
+	)
+
+	network.tick(context.Background())
+
+	labels := []string{testMockNodeName}
+
+	assertMetricValue(t, network.NetworkBlockNumber, labels, 20000000)
+	assertMetricValue(t, network.NetworkChainID, labels, 17000)
+	assertMetricValue(t, network.NetworkGasPrice, labels, 1000000000)
+	assertMetricValue(t, network.NetworkMaxPriorityFee, labels, 100000000)
+	assertMetricValue(t, network.NetworkBaseFee, labels, 200)
+	assertMetricValue(t, network.NetworkNextBaseFee, labels, 300)
+	assertMetricValue(t, network.NetworkBlobBaseFee, labels, 2)
+	assertMetricValue(t, network.NetworkNextBlobBaseFee, labels, 3)
+	assertMetricValue(t, network.NetworkGasUsedRatio, labels, 0.25)
+	assertMetricValue(t, network.NetworkPriorityFee, []string{testMockNodeName, "10"}, 15)
+	assertMetricValue(t, network.NetworkPriorityFee, []string{testMockNodeName, "50.5"}, 150)
+	assert.InDelta(t, 0, testutil.ToFloat64(network.NetworkError.WithLabelValues(labels...)), 0)
+}
+
+func TestNetwork_tick_PreCancunAndUnsupportedPriorityFee(t *testing.T) {
+	mockClient := &mockExecutionClient{
+		ethGasPriceResponse:    "0x3b9aca00",
+		ethMaxPriorityFeeError: errors.New("the method eth_maxPriorityFeePerGas does not exist"),
+		ethFeeHistoryResponse: &api.ETHFeeHistory{
+			BaseFeePerGas: []string{"0x64", "0xc8"},
+			GasUsedRatio:  []float64{0.5},
+		},
+	}
+
+	network := NewNetwork(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"network_pre_cancun",
+		map[string]string{},
+		newTestNetworkConfig(),
+		testRegistry(),
+	)
+
+	network.tick(context.Background())
+
+	labels := []string{testMockNodeName}
+
+	assertMetricValue(t, network.NetworkGasPrice, labels, 1000000000)
+	assertMetricValue(t, network.NetworkBaseFee, labels, 100)
+	assert.Equal(t, 0, testutil.CollectAndCount(&network.NetworkMaxPriorityFee))
+	assert.Equal(t, 0, testutil.CollectAndCount(&network.NetworkBlobBaseFee))
+	assert.Equal(t, 0, testutil.CollectAndCount(&network.NetworkPriorityFee))
+	assert.InDelta(t, 1, testutil.ToFloat64(network.NetworkError.WithLabelValues(labels...)), 0)
+}
+
+func TestNetwork_getFeeHistory_Empty(t *testing.T) {
+	mockClient := &mockExecutionClient{}
+
+	network := NewNetwork(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"network_empty",
+		map[string]string{},
+		newTestNetworkConfig(),
+		testRegistry(),
+	)
+
+	require.Error(t, network.getFeeHistory(context.Background(), mockClient))
+	assert.Equal(t, 1, mockClient.ethFeeHistoryCalls)
+	assert.InDelta(t, 1, testutil.ToFloat64(network.NetworkError.WithLabelValues(testMockNodeName)), 0)
+}
+
+func TestNetworkConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  *NetworkConfig
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: newTestNetworkConfig(),
+		},
+		{
+			name:   "disabled",
+			config: &NetworkConfig{},
+		},
+		{
+			name:    "no fee history blocks",
+			config:  &NetworkConfig{Enabled: true},
+			wantErr: "feeHistoryBlocks must be between 1 and 1024",
+		},
+		{
+			name:    "percentile out of range",
+			config:  &NetworkConfig{Enabled: true, FeeHistoryBlocks: 10, RewardPercentiles: []float64{50, 101}},
+			wantErr: "must be between 0 and 100",
+		},
+		{
+			name:    "percentiles not ascending",
+			config:  &NetworkConfig{Enabled: true, FeeHistoryBlocks: 10, RewardPercentiles: []float64{50, 50}},
+			wantErr: "ascending order",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.config.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNetwork_Name(t *testing.T) {
+	network := &Network{}
+	if network.Name() != NameNetwork {
+		t.Errorf("Expected name %s, got %s", NameNetwork, network.Name())
+	}
+}
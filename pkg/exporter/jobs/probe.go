@@ -0,0 +1,318 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+)
+
+// ProbeConfig configures the on-demand probing of addresses that are not
+// listed in the config.
+type ProbeConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Timeout bounds a probe when its module does not set one.
+	Timeout time.Duration `yaml:"timeout" default:"10s"`
+	// Modules are named sets of defaults a probe can select with the module parameter.
+	Modules map[string]*ProbeModule `yaml:"modules"`
+}
+
+// ProbeModule holds the address type, timeout and default parameters of a probe.
+type ProbeModule struct {
+	Type    string        `yaml:"type"`
+	Timeout time.Duration `yaml:"timeout"`
+	// Params are the default fields of the probed address, overridden by the parameters of the request.
+	Params map[string]any `yaml:"params"`
+}
+
+// Validate checks every module probes a supported address type.
+func (c *ProbeConfig) Validate() error {
+	for name, module := range c.Modules {
+		if module == nil {
+			return fmt.Errorf("probe module %s must not be empty", name)
+		}
+
+		if _, ok := probers[module.Type]; !ok {
+			return fmt.Errorf("probe module %s: unsupported type %q, must be one of %v", name, module.Type, ProbeTypes())
+		}
+
+		if module.Timeout < 0 {
+			return fmt.Errorf("probe module %s: timeout must not be negative", name)
+		}
+	}
+
+	if c.Timeout < 0 {
+		return fmt.Errorf("probe timeout must not be negative")
+	}
+
+	return nil
+}
+
+// Prober runs a single tick of a job for one address on demand.
+type Prober struct {
+	clients     []api.ExecutionClient
+	log         logrus.FieldLogger
+	namespace   string
+	constLabels map[string]string
+	tokens      *TokenMetadataCache
+}
+
+// NewProber returns a new Prober. Token metadata is cached across probes.
+func NewProber(clients []api.ExecutionClient, log logrus.FieldLogger, namespace string, constLabels map[string]string) *Prober {
+	return &Prober{
+		clients:     clients,
+		log:         log.WithField("module", "probe"),
+		namespace:   namespace,
+		constLabels: constLabels,
+		tokens:      NewTokenMetadataCache(),
+	}
+}
+
+// probeFunc decodes the address of a probe and runs its job once.
+type probeFunc func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error
+
+// probers holds the address types that can be probed, keyed by their name in
+// the addresses config. Logs are not supported as they follow new blocks.
+var probers = map[string]probeFunc{
+	"account": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressAccount](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewAccount(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressAccount{address}, nil, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"erc20": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressERC20](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewERC20(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressERC20{address}, p.tokens, nil, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"erc20Allowance": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressERC20Allowance](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewERC20Allowance(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressERC20Allowance{address}, p.tokens, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"erc721": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressERC721](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewERC721(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressERC721{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"erc1155": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressERC1155](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewERC1155(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressERC1155{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"erc4626": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressERC4626](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewERC4626(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressERC4626{address}, p.tokens, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"lidoWithdrawalQueueERC721": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressLidoWithdrawalQueueERC721](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewLidoWithdrawalQueueERC721(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressLidoWithdrawalQueueERC721{address}, p.tokens, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"uniswapPair": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressUniswapPair](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewUniswapPair(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressUniswapPair{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"uniswapV3Pool": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressUniswapV3Pool](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewUniswapV3Pool(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressUniswapV3Pool{address}, p.tokens, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"chainlinkDataFeed": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressChainlinkDataFeed](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewChainlinkDataFeed(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressChainlinkDataFeed{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"erc4337": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressERC4337](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewERC4337(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressERC4337{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"contractCall": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressContractCall](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewContractCall(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressContractCall{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"storageSlot": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressStorageSlot](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewStorageSlot(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressStorageSlot{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"proxy": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressProxy](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewProxy(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressProxy{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"safe": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressSafe](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewSafe(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressSafe{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"lstRate": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressLSTRate](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewLSTRate(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressLSTRate{address}, p.tokens, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+	"lendingPosition": func(ctx context.Context, p *Prober, data []byte, registerer prometheus.Registerer) error {
+		address, err := decodeProbeAddress[AddressLendingPosition](data)
+		if err != nil {
+			return err
+		}
+
+		job := NewLendingPosition(p.clients, p.log, 0, p.namespace, p.constLabels, []*AddressLendingPosition{address}, nil, registerer)
+		job.tick(ctx)
+
+		return nil
+	},
+}
+
+// ProbeTypes returns the sorted address types that can be probed.
+func ProbeTypes() []string {
+	types := make([]string, 0, len(probers))
+
+	for name := range probers {
+		types = append(types, name)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
+
+// decodeProbeAddress decodes the YAML fields of an address and runs its type
+// specific validation. Unknown fields are rejected.
+func decodeProbeAddress[T any](data []byte) (*T, error) {
+	address := new(T)
+
+	if err := yaml.UnmarshalStrict(data, address); err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	if v, ok := any(address).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return address, nil
+}
+
+// Probe runs the job of the address type once for the address described by
+// params, registering its metrics with registerer. Errors of the job itself
+// are counted by its errors_total metric, only invalid probes return an error.
+func (p *Prober) Probe(ctx context.Context, addressType string, params map[string]any, registerer prometheus.Registerer) error {
+	probe, ok := probers[addressType]
+	if !ok {
+		return fmt.Errorf("unsupported type %q, must be one of %v", addressType, ProbeTypes())
+	}
+
+	data, err := yaml.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	return probe(ctx, p, data, registerer)
+}
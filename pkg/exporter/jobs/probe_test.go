@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProber_Probe(t *testing.T) {
+	mockClient := &mockExecutionClient{
+		balanceOfResponse: encodeABIUintReturn(250),
+		symbolResponse:    testABISymbolUSDCResponse,
+		ethCallResponses: map[string]string{
+			erc20TotalSupplySelector: encodeABIUintReturn(1000),
+		},
+	}
+
+	prober := NewProber(mockClients(mockClient), testLogger(), "probe", nil)
+
+	// Every probe registers into its own registry, so probing the same
+	// address twice must not collide.
+	for range 2 {
+		registry := prometheus.NewRegistry()
+
+		err := prober.Probe(context.Background(), "erc20", map[string]any{
+			"name":     "treasury",
+			"address":  testHolder1Address,
+			"contract": testUSDCContract,
+		}, registry)
+		require.NoError(t, err)
+
+		count, err := testutil.GatherAndCount(registry, "probe_erc20_balance", "probe_erc20_share_of_supply")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	}
+}
+
+func TestProber_Probe_Invalid(t *testing.T) {
+	prober := NewProber(mockClients(&mockExecutionClient{}), testLogger(), "probe_invalid", nil)
+
+	tests := []struct {
+		name        string
+		addressType string
+		params      map[string]any
+		wantErr     string
+	}{
+		{
+			name:        "unsupported type",
+			addressType: "logs",
+			params:      map[string]any{"name": "transfers"},
+			wantErr:     `unsupported type "logs"`,
+		},
+		{
+			name:        "unknown field",
+			addressType: "erc20",
+			params:      map[string]any{"name": "treasury", "holder": testHolder1Address},
+			wantErr:     "invalid address",
+		},
+		{
+			name:        "failed validation",
+			addressType: "contractCall",
+			params:      map[string]any{"name": "call", "contract": testContractAAddress, "signature": "totalSupply()(uint256)"},
+			wantErr:     "at least one output must be configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := prober.Probe(context.Background(), tt.addressType, tt.params, prometheus.NewRegistry())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestProbeConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ProbeConfig
+		wantErr string
+	}{
+		{
+			name: "valid modules",
+			config: ProbeConfig{
+				Enabled: true,
+				Modules: map[string]*ProbeModule{
+					"usdc": {Type: "erc20", Params: map[string]any{"contract": testUSDCContract}},
+				},
+			},
+		},
+		{
+			name: "unsupported module type",
+			config: ProbeConfig{
+				Modules: map[string]*ProbeModule{
+					"transfers": {Type: "logs"},
+				},
+			},
+			wantErr: `probe module transfers: unsupported type "logs"`,
+		},
+		{
+			name: "empty module",
+			config: ProbeConfig{
+				Modules: map[string]*ProbeModule{
+					"usdc": nil,
+				},
+			},
+			wantErr: "probe module usdc must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameProxy = "proxy"
+
+	// bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1).
+	proxyEIP1967ImplementationSlot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+	// bytes32(uint256(keccak256("eip1967.proxy.admin")) - 1).
+	proxyEIP1967AdminSlot = "0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"
+	// bytes32(uint256(keccak256("eip1967.proxy.beacon")) - 1).
+	proxyEIP1967BeaconSlot = "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"
+	// keccak256("org.zeppelinos.proxy.implementation").
+	proxyLegacyImplementationSlot = "0x7050c9e0f4ca769c69bd3a8ef740bc37934f8e2c036e5a723fd8ee048ed3f8c3"
+	// keccak256("org.zeppelinos.proxy.admin").
+	proxyLegacyAdminSlot = "0x10d6a54a4754c8869d6886b5f5d7fbfa5b4522237ea5c60d11bc4e7a1ff9390b"
+
+	proxyBeaconImplementationSelector = "0x5c60da1b"
+
+	proxyStandardEIP1967 = "eip1967"
+	proxyStandardLegacy  = "legacy"
+
+	proxySlotImplementation = "implementation"
+	proxySlotAdmin          = "admin"
+	proxySlotBeacon         = "beacon"
+)
+
+// Proxy exposes metrics for upgradeable proxy contracts.
+type Proxy struct {
+	*tickTracker
+
+	clients       []api.ExecutionClient
+	log           logrus.FieldLogger
+	ProxyInfo     prometheus.GaugeVec
+	ProxyChanges  prometheus.CounterVec
+	ProxyError    prometheus.CounterVec
+	checkInterval time.Duration
+	addresses     []*AddressProxy
+	labelsMap     map[string]int
+	snapshots     *snapshot.Store
+	// states holds the last observed state per execution client and address name.
+	states map[proxyStateKey]*proxyState
+}
+
+type AddressProxy struct {
+	Contract string            `yaml:"contract"`
+	Name     string            `yaml:"name"`
+	Labels   map[string]string `yaml:"labels"`
+}
+
+type proxyStateKey struct {
+	execution string
+	name      string
+}
+
+type proxyState struct {
+	standard       string
+	implementation string
+	admin          string
+	beacon         string
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressProxy) GetName() string { return a.Name }
+
+func (n *Proxy) Name() string {
+	return NameProxy
+}
+
+// NewProxy returns a new Proxy instance.
+func NewProxy(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressProxy, snapshots *snapshot.Store, registerer prometheus.Registerer) Proxy {
+	namespace += "_" + NameProxy
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelContract:  1,
+		LabelExecution: 2,
+	}
+
+	for address := range addresses {
+		for label := range addresses[address].Labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	infoLabels := append(append([]string{}, labels...), LabelStandard, LabelImplementation, LabelAdmin, LabelBeacon)
+	changesLabels := append(append([]string{}, labels...), LabelSlot)
+
+	instance := Proxy{
+		clients:       clients,
+		log:           log.WithField("module", NameProxy),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
+		addresses:     addresses,
+		checkInterval: checkInterval,
+		labelsMap:     labelsMap,
+		states:        make(map[proxyStateKey]*proxyState),
+		ProxyInfo: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "info",
+				Help:        "The implementation, admin and beacon addresses of a ethereum proxy contract.",
+				ConstLabels: constLabels,
+			},
+			infoLabels,
+		),
+		ProxyChanges: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "changes_total",
+				Help:        "The total changes observed in the implementation, admin or beacon slot of a ethereum proxy contract.",
+				ConstLabels: constLabels,
+			},
+			changesLabels,
+		),
+		ProxyError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting the slots of a ethereum proxy contract.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.ProxyInfo)
+	registerer.MustRegister(instance.ProxyChanges)
+	registerer.MustRegister(instance.ProxyError)
+
+	return instance
+}
+
+func (n *Proxy) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *Proxy) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		for _, address := range n.addresses {
+			err := n.getProxy(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get proxy slots")
+			}
+		}
+	}
+}
+
+func (n *Proxy) getLabelValues(address *AddressProxy, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		if address.Labels != nil && address.Labels[label] != "" {
+			values[index] = address.Labels[label]
+		} else {
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+func (n *Proxy) getProxy(ctx context.Context, client api.ExecutionClient, address *AddressProxy) error {
+	var err error
+
+	labels := n.getLabelValues(address, client.Name())
+
+	defer func() {
+		if err != nil {
+			n.ProxyError.WithLabelValues(labels...).Inc()
+			n.snapshots.SetError(NameProxy, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	state, err := n.getProxyState(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	key := proxyStateKey{execution: client.Name(), name: address.Name}
+
+	if previous, ok := n.states[key]; ok {
+		n.ProxyInfo.DeleteLabelValues(previous.infoLabelValues(labels)...)
+
+		for slot, changed := range map[string]bool{
+			proxySlotImplementation: previous.implementation != state.implementation,
+			proxySlotAdmin:          previous.admin != state.admin,
+			proxySlotBeacon:         previous.beacon != state.beacon,
+		} {
+			if changed {
+				n.ProxyChanges.WithLabelValues(append(append([]string{}, labels...), slot)...).Inc()
+			}
+		}
+	}
+
+	n.states[key] = state
+
+	n.ProxyInfo.WithLabelValues(state.infoLabelValues(labels)...).Set(1)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameProxy,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Contract:  address.Contract,
+		Info: map[string]string{
+			LabelStandard:       state.standard,
+			LabelImplementation: state.implementation,
+			LabelAdmin:          state.admin,
+			LabelBeacon:         state.beacon,
+		},
+	}, time.Now())
+
+	return nil
+}
+
+// getProxyState reads the EIP-1967 slots, falling back to the legacy
+// OpenZeppelin slots when none of them are set.
+func (n *Proxy) getProxyState(ctx context.Context, client api.ExecutionClient, contract string) (*proxyState, error) {
+	state := &proxyState{standard: proxyStandardEIP1967}
+
+	slots := []struct {
+		slot   string
+		target *string
+	}{
+		{slot: proxyEIP1967ImplementationSlot, target: &state.implementation},
+		{slot: proxyEIP1967AdminSlot, target: &state.admin},
+		{slot: proxyEIP1967BeaconSlot, target: &state.beacon},
+	}
+
+	for _, s := range slots {
+		value, err := getStorageAddress(ctx, client, contract, s.slot)
+		if err != nil {
+			return nil, err
+		}
+
+		*s.target = value
+	}
+
+	if state.beacon != "" && state.implementation == "" {
+		implementation, err := ethCallAddress(ctx, client, state.beacon, proxyBeaconImplementationSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get beacon implementation: %w", err)
+		}
+
+		state.implementation = implementation
+	}
+
+	if state.implementation != "" || state.admin != "" || state.beacon != "" {
+		return state, nil
+	}
+
+	legacy := &proxyState{standard: proxyStandardLegacy}
+
+	implementation, err := getStorageAddress(ctx, client, contract, proxyLegacyImplementationSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	admin, err := getStorageAddress(ctx, client, contract, proxyLegacyAdminSlot)
+	if err != nil {
+		return nil, err
+	}
+
+	if implementation == "" && admin == "" {
+		return state, nil
+	}
+
+	legacy.implementation = implementation
+	legacy.admin = admin
+
+	return legacy, nil
+}
+
+func (s *proxyState) infoLabelValues(labels []string) []string {
+	return append(append([]string{}, labels...), s.standard, s.implementation, s.admin, s.beacon)
+}
+
+// getStorageAddress reads an address stored in the low 160 bits of a storage
+// slot, returning an empty string when the slot is unset.
+func getStorageAddress(ctx context.Context, client api.ExecutionClient, contract, slot string) (string, error) {
+	slotValue, ok := new(big.Int).SetString(slot, 0)
+	if !ok {
+		return "", fmt.Errorf("invalid storage slot %q", slot)
+	}
+
+	word, err := ethGetStorageWord(ctx, client, contract, slotValue)
+	if err != nil {
+		return "", err
+	}
+
+	address := extractStorageBits(word, 0, 160, false)
+	if address.Sign() == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("0x%040x", address), nil
+}
@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	testProxyImplementationA = "0x1111111111111111111111111111111111111111"
+	testProxyImplementationB = "0x2222222222222222222222222222222222222222"
+	testProxyAdmin           = "0x3333333333333333333333333333333333333333"
+	testProxyBeacon          = "0x4444444444444444444444444444444444444444"
+)
+
+func encodeStorageAddress(address string) string {
+	return "0x000000000000000000000000" + strings.TrimPrefix(address, "0x")
+}
+
+func TestProxy_getProxy_DetectsUpgrade(t *testing.T) {
+	address := &AddressProxy{
+		Name:     "Vault",
+		Contract: testContractAAddress,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethGetStorageAtResponses: map[string]string{
+			proxyEIP1967ImplementationSlot: encodeStorageAddress(testProxyImplementationA),
+			proxyEIP1967AdminSlot:          encodeStorageAddress(testProxyAdmin),
+		},
+	}
+
+	proxy := NewProxy(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"proxy_upgrade",
+		map[string]string{},
+		[]*AddressProxy{address},
+		nil,
+		testRegistry(),
+	)
+
+	err := proxy.getProxy(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	labels := proxy.getLabelValues(address, testMockNodeName)
+	infoA := append(append([]string{}, labels...), proxyStandardEIP1967, testProxyImplementationA, testProxyAdmin, "")
+
+	assertMetricValue(t, proxy.ProxyInfo, infoA, 1)
+	assert.Equal(t, 0, testutil.CollectAndCount(&proxy.ProxyChanges))
+
+	mockClient.ethGetStorageAtResponses[proxyEIP1967ImplementationSlot] = encodeStorageAddress(testProxyImplementationB)
+
+	err = proxy.getProxy(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	infoB := append(append([]string{}, labels...), proxyStandardEIP1967, testProxyImplementationB, testProxyAdmin, "")
+
+	assertMetricValue(t, proxy.ProxyInfo, infoB, 1)
+	assert.Equal(t, 1, testutil.CollectAndCount(&proxy.ProxyInfo))
+
+	implementationChanges := proxy.ProxyChanges.WithLabelValues(append(append([]string{}, labels...), proxySlotImplementation)...)
+	assert.InDelta(t, 1, testutil.ToFloat64(implementationChanges), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(&proxy.ProxyChanges))
+}
+
+func TestProxy_getProxy_Snapshot(t *testing.T) {
+	address := &AddressProxy{
+		Name:     "Vault",
+		Contract: testContractAAddress,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethGetStorageAtResponses: map[string]string{
+			proxyEIP1967ImplementationSlot: encodeStorageAddress(testProxyImplementationA),
+			proxyEIP1967AdminSlot:          encodeStorageAddress(testProxyAdmin),
+		},
+	}
+
+	snapshots := snapshot.NewStore(testLogger())
+
+	proxy := NewProxy(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"proxy_snapshot",
+		map[string]string{},
+		[]*AddressProxy{address},
+		snapshots,
+		testRegistry(),
+	)
+
+	require.NoError(t, proxy.getProxy(context.Background(), mockClient, address))
+
+	mockClient.ethGetStorageAtResponses[proxyEIP1967AdminSlot] = "0xzz"
+
+	require.Error(t, proxy.getProxy(context.Background(), mockClient, address))
+
+	addresses := snapshots.Snapshot()[NameProxy]
+	require.Len(t, addresses, 1)
+	assert.Equal(t, testContractAAddress, addresses[0].Contract)
+
+	node := addresses[0].Nodes[testMockNodeName]
+	require.NotNil(t, node)
+
+	assert.Equal(t, map[string]string{
+		LabelStandard:       proxyStandardEIP1967,
+		LabelImplementation: testProxyImplementationA,
+		LabelAdmin:          testProxyAdmin,
+		LabelBeacon:         "",
+	}, node.Info)
+	assert.NotEmpty(t, node.LastError)
+	assert.NotNil(t, node.LastErrorTimestamp)
+}
+
+func TestProxy_getProxyState(t *testing.T) {
+	tests := []struct {
+		name          string
+		client        *mockExecutionClient
+		expected      proxyState
+		expectedCalls int
+	}{
+		{
+			name: "beacon proxy",
+			client: &mockExecutionClient{
+				ethGetStorageAtResponses: map[string]string{
+					proxyEIP1967BeaconSlot: encodeStorageAddress(testProxyBeacon),
+				},
+				ethCallResponses: map[string]string{
+					proxyBeaconImplementationSelector: encodeABIAddressReturn(testProxyImplementationA),
+				},
+			},
+			expected: proxyState{
+				standard:       proxyStandardEIP1967,
+				implementation: testProxyImplementationA,
+				beacon:         testProxyBeacon,
+			},
+			expectedCalls: 3,
+		},
+		{
+			name: "legacy proxy",
+			client: &mockExecutionClient{
+				ethGetStorageAtResponses: map[string]string{
+					proxyLegacyImplementationSlot: encodeStorageAddress(testProxyImplementationA),
+					proxyLegacyAdminSlot:          encodeStorageAddress(testProxyAdmin),
+				},
+			},
+			expected: proxyState{
+				standard:       proxyStandardLegacy,
+				implementation: testProxyImplementationA,
+				admin:          testProxyAdmin,
+			},
+			expectedCalls: 5,
+		},
+		{
+			name:   "not a proxy",
+			client: &mockExecutionClient{},
+			expected: proxyState{
+				standard: proxyStandardEIP1967,
+			},
+			expectedCalls: 5,
+		},
+	}
+
+	proxy := &Proxy{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := proxy.getProxyState(context.Background(), tt.client, testContractAAddress)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *state)
+			assert.Len(t, tt.client.ethGetStorageAtCalls, tt.expectedCalls)
+		})
+	}
+}
+
+func TestProxy_Name(t *testing.T) {
+	proxy := &Proxy{}
+	if proxy.Name() != NameProxy {
+		t.Errorf("Expected name %s, got %s", NameProxy, proxy.Name())
+	}
+}
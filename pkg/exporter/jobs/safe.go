@@ -0,0 +1,388 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameSafe = "safe"
+
+	safeGetThresholdSelector        = "0xe75235b8"
+	safeGetOwnersSelector           = "0xa0e67e2b"
+	safeNonceSelector               = "0xaffed0e0"
+	safeVersionSelector             = "0xffa1ad74"
+	safeGetModulesPaginatedSelector = "0xcc2f8452"
+
+	// keccak256("guard_manager.guard.address").
+	safeGuardSlot = "0x4a204f620c8c5ccdca3fd54d003badd85ba500436a431f0cbda4f558c93c34c8"
+	// safeModulesSentinel is the start of the Safe modules linked list.
+	safeModulesSentinel = "0x0000000000000000000000000000000000000001"
+	safeModulesPageSize = 100
+
+	safeChangeThreshold = "threshold"
+	safeChangeOwners    = "owners"
+	safeChangeModules   = "modules"
+	safeChangeGuard     = "guard"
+)
+
+// Safe exposes metrics for Safe (formerly Gnosis Safe) multisig wallets.
+type Safe struct {
+	*tickTracker
+
+	clients       []api.ExecutionClient
+	log           logrus.FieldLogger
+	SafeThreshold prometheus.GaugeVec
+	SafeOwners    prometheus.GaugeVec
+	SafeNonce     prometheus.GaugeVec
+	SafeModules   prometheus.GaugeVec
+	SafeOwnerInfo prometheus.GaugeVec
+	SafeInfo      prometheus.GaugeVec
+	SafeChanges   prometheus.CounterVec
+	SafeError     prometheus.CounterVec
+	checkInterval time.Duration
+	addresses     []*AddressSafe
+	labelsMap     map[string]int
+	// states holds the last observed state per execution client and address name.
+	states    map[safeStateKey]*safeState
+	snapshots *snapshot.Store
+}
+
+type AddressSafe struct {
+	Contract string            `yaml:"contract"`
+	Name     string            `yaml:"name"`
+	Labels   map[string]string `yaml:"labels"`
+}
+
+type safeStateKey struct {
+	execution string
+	name      string
+}
+
+type safeState struct {
+	threshold *big.Int
+	owners    []string
+	nonce     *big.Int
+	version   string
+	modules   []string
+	guard     string
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressSafe) GetName() string { return a.Name }
+
+func (n *Safe) Name() string {
+	return NameSafe
+}
+
+// NewSafe returns a new Safe instance.
+func NewSafe(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressSafe, snapshots *snapshot.Store, registerer prometheus.Registerer) Safe {
+	namespace += "_" + NameSafe
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelContract:  1,
+		LabelExecution: 2,
+	}
+
+	for address := range addresses {
+		for label := range addresses[address].Labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	newGaugeVec := func(name, help string, labelNames []string) prometheus.GaugeVec {
+		return *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        name,
+				Help:        help,
+				ConstLabels: constLabels,
+			},
+			labelNames,
+		)
+	}
+
+	instance := Safe{
+		clients:       clients,
+		log:           log.WithField("module", NameSafe),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
+		addresses:     addresses,
+		checkInterval: checkInterval,
+		labelsMap:     labelsMap,
+		states:        make(map[safeStateKey]*safeState),
+		SafeThreshold: newGaugeVec(
+			"threshold",
+			"The number of owner confirmations required by a safe multisig.",
+			labels,
+		),
+		SafeOwners: newGaugeVec(
+			"owners",
+			"The number of owners of a safe multisig.",
+			labels,
+		),
+		SafeNonce: newGaugeVec(
+			"nonce",
+			"The transaction nonce of a safe multisig.",
+			labels,
+		),
+		SafeModules: newGaugeVec(
+			"modules",
+			"The number of modules enabled on a safe multisig.",
+			labels,
+		),
+		SafeOwnerInfo: newGaugeVec(
+			"owner_info",
+			"An owner of a safe multisig.",
+			append(append([]string{}, labels...), LabelOwner),
+		),
+		SafeInfo: newGaugeVec(
+			"info",
+			"The version and guard of a safe multisig.",
+			append(append([]string{}, labels...), LabelVersion, LabelGuard),
+		),
+		SafeChanges: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "changes_total",
+				Help:        "The total changes observed in the threshold, owners, modules or guard of a safe multisig.",
+				ConstLabels: constLabels,
+			},
+			append(append([]string{}, labels...), LabelKind),
+		),
+		SafeError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting the state of a safe multisig.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.SafeThreshold)
+	registerer.MustRegister(instance.SafeOwners)
+	registerer.MustRegister(instance.SafeNonce)
+	registerer.MustRegister(instance.SafeModules)
+	registerer.MustRegister(instance.SafeOwnerInfo)
+	registerer.MustRegister(instance.SafeInfo)
+	registerer.MustRegister(instance.SafeChanges)
+	registerer.MustRegister(instance.SafeError)
+
+	return instance
+}
+
+func (n *Safe) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *Safe) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		for _, address := range n.addresses {
+			err := n.getSafe(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get safe state")
+			}
+		}
+	}
+}
+
+func (n *Safe) getLabelValues(address *AddressSafe, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		if address.Labels != nil && address.Labels[label] != "" {
+			values[index] = address.Labels[label]
+		} else {
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+func (n *Safe) getSafe(ctx context.Context, client api.ExecutionClient, address *AddressSafe) error {
+	var err error
+
+	labels := n.getLabelValues(address, client.Name())
+
+	defer func() {
+		if err != nil {
+			n.SafeError.WithLabelValues(labels...).Inc()
+			n.snapshots.SetError(NameSafe, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	state, err := n.getSafeState(ctx, client, address.Contract)
+	if err != nil {
+		return err
+	}
+
+	key := safeStateKey{execution: client.Name(), name: address.Name}
+
+	if previous, ok := n.states[key]; ok {
+		n.recordChanges(labels, previous, state)
+	}
+
+	n.states[key] = state
+
+	n.SafeThreshold.WithLabelValues(labels...).Set(bigIntToFloat64(state.threshold))
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameSafe,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Contract:  address.Contract,
+		Value:     bigIntToFloat64(state.threshold),
+	}, time.Now())
+	n.SafeOwners.WithLabelValues(labels...).Set(float64(len(state.owners)))
+	n.SafeNonce.WithLabelValues(labels...).Set(bigIntToFloat64(state.nonce))
+	n.SafeModules.WithLabelValues(labels...).Set(float64(len(state.modules)))
+	n.SafeInfo.WithLabelValues(append(append([]string{}, labels...), state.version, state.guard)...).Set(1)
+
+	for _, owner := range state.owners {
+		n.SafeOwnerInfo.WithLabelValues(append(append([]string{}, labels...), owner)...).Set(1)
+	}
+
+	return nil
+}
+
+// recordChanges increments the change counters and removes series for owners,
+// version and guard values that are no longer current.
+func (n *Safe) recordChanges(labels []string, previous, current *safeState) {
+	changes := map[string]bool{
+		safeChangeThreshold: previous.threshold.Cmp(current.threshold) != 0,
+		safeChangeOwners:    !slices.Equal(previous.owners, current.owners),
+		safeChangeModules:   !slices.Equal(previous.modules, current.modules),
+		safeChangeGuard:     previous.guard != current.guard,
+	}
+
+	for kind, changed := range changes {
+		if changed {
+			n.SafeChanges.WithLabelValues(append(append([]string{}, labels...), kind)...).Inc()
+		}
+	}
+
+	for _, owner := range previous.owners {
+		if !slices.Contains(current.owners, owner) {
+			n.SafeOwnerInfo.DeleteLabelValues(append(append([]string{}, labels...), owner)...)
+		}
+	}
+
+	if previous.version != current.version || previous.guard != current.guard {
+		n.SafeInfo.DeleteLabelValues(append(append([]string{}, labels...), previous.version, previous.guard)...)
+	}
+}
+
+func (n *Safe) getSafeState(ctx context.Context, client api.ExecutionClient, contract string) (*safeState, error) {
+	state := &safeState{}
+
+	var err error
+
+	state.threshold, err = ethCallUint256(ctx, client, contract, safeGetThresholdSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get threshold: %w", err)
+	}
+
+	state.nonce, err = ethCallUint256(ctx, client, contract, safeNonceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	ownersData, err := n.call(ctx, client, contract, safeGetOwnersSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owners: %w", err)
+	}
+
+	state.owners, err = decodeABIAddressArray(ownersData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode owners: %w", err)
+	}
+
+	versionData, err := n.call(ctx, client, contract, safeVersionSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version: %w", err)
+	}
+
+	state.version, err = hexStringToString(versionData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode version: %w", err)
+	}
+
+	state.modules, err = n.getModules(ctx, client, contract)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modules: %w", err)
+	}
+
+	state.guard, err = getStorageAddress(ctx, client, contract, safeGuardSlot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guard: %w", err)
+	}
+
+	return state, nil
+}
+
+// getModules returns the first page of enabled modules, which covers any
+// realistic safe configuration.
+func (n *Safe) getModules(ctx context.Context, client api.ExecutionClient, contract string) ([]string, error) {
+	start, err := encodeAddressArgument(safeModulesSentinel)
+	if err != nil {
+		return nil, err
+	}
+
+	data := safeGetModulesPaginatedSelector + start + formatABIWord(big.NewInt(safeModulesPageSize))
+
+	rsp, err := n.call(ctx, client, contract, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeABIAddressArray(rsp)
+}
+
+func (n *Safe) call(ctx context.Context, client api.ExecutionClient, to, data string) (string, error) {
+	return client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   to,
+		Data: &data,
+	}, "latest")
+}
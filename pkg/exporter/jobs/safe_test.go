@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSafeOwnerA = "0x1111111111111111111111111111111111111111"
+	testSafeOwnerB = "0x2222222222222222222222222222222222222222"
+	testSafeOwnerC = "0x3333333333333333333333333333333333333333"
+	testSafeModule = "0x4444444444444444444444444444444444444444"
+	testSafeGuard  = "0x5555555555555555555555555555555555555555"
+)
+
+func encodeABIAddressArrayReturn(addresses ...string) string {
+	var builder strings.Builder
+
+	builder.WriteString("0x")
+	builder.WriteString(formatABIWord(big.NewInt(32)))
+	builder.WriteString(formatABIWord(big.NewInt(int64(len(addresses)))))
+
+	for _, address := range addresses {
+		builder.WriteString(strings.TrimPrefix(encodeABIAddressReturn(address), "0x"))
+	}
+
+	return builder.String()
+}
+
+// encodeSafeModulesReturn encodes the (address[] modules, address next) return of getModulesPaginated.
+func encodeSafeModulesReturn(modules ...string) string {
+	encoded := encodeABIAddressArrayReturn(modules...)
+
+	return "0x" + formatABIWord(big.NewInt(64)) + formatABIWord(big.NewInt(1)) + encoded[2+64:]
+}
+
+func newTestSafeClient(owners []string, threshold int64) *mockExecutionClient {
+	return &mockExecutionClient{
+		ethCallResponses: map[string]string{
+			safeGetThresholdSelector:        encodeABIUintReturn(threshold),
+			safeNonceSelector:               encodeABIUintReturn(42),
+			safeGetOwnersSelector:           encodeABIAddressArrayReturn(owners...),
+			safeVersionSelector:             encodeABIStringReturn("1.4.1"),
+			safeGetModulesPaginatedSelector: encodeSafeModulesReturn(testSafeModule),
+		},
+		ethGetStorageAtResponses: map[string]string{
+			safeGuardSlot: encodeStorageAddress(testSafeGuard),
+		},
+	}
+}
+
+func TestSafe_getSafe(t *testing.T) {
+	address := &AddressSafe{
+		Name:     "Treasury",
+		Contract: testContractAAddress,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := newTestSafeClient([]string{testSafeOwnerA, testSafeOwnerB}, 2)
+
+	safe := NewSafe(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"safe_get",
+		map[string]string{},
+		[]*AddressSafe{address},
+		nil,
+		testRegistry(),
+	)
+
+	err := safe.getSafe(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	labels := safe.getLabelValues(address, testMockNodeName)
+
+	assertMetricValue(t, safe.SafeThreshold, labels, 2)
+	assertMetricValue(t, safe.SafeOwners, labels, 2)
+	assertMetricValue(t, safe.SafeNonce, labels, 42)
+	assertMetricValue(t, safe.SafeModules, labels, 1)
+	assertMetricValue(t, safe.SafeInfo, append(append([]string{}, labels...), "1.4.1", testSafeGuard), 1)
+	assertMetricValue(t, safe.SafeOwnerInfo, append(append([]string{}, labels...), testSafeOwnerA), 1)
+	assertMetricValue(t, safe.SafeOwnerInfo, append(append([]string{}, labels...), testSafeOwnerB), 1)
+	assert.Equal(t, 0, testutil.CollectAndCount(&safe.SafeChanges))
+
+	assert.True(t, strings.HasSuffix(mockClient.callLog[4].data, formatABIWord(big.NewInt(safeModulesPageSize))))
+}
+
+func TestSafe_getSafe_DetectsOwnerAndThresholdChanges(t *testing.T) {
+	address := &AddressSafe{
+		Name:     "Treasury",
+		Contract: testContractAAddress,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := newTestSafeClient([]string{testSafeOwnerA, testSafeOwnerB}, 2)
+
+	safe := NewSafe(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"safe_changes",
+		map[string]string{},
+		[]*AddressSafe{address},
+		nil,
+		testRegistry(),
+	)
+
+	require.NoError(t, safe.getSafe(context.Background(), mockClient, address))
+
+	mockClient.ethCallResponses[safeGetOwnersSelector] = encodeABIAddressArrayReturn(testSafeOwnerC, testSafeOwnerA)
+	mockClient.ethCallResponses[safeGetThresholdSelector] = encodeABIUintReturn(1)
+
+	require.NoError(t, safe.getSafe(context.Background(), mockClient, address))
+
+	labels := safe.getLabelValues(address, testMockNodeName)
+
+	for kind, expected := range map[string]float64{
+		safeChangeThreshold: 1,
+		safeChangeOwners:    1,
+	} {
+		counter := safe.SafeChanges.WithLabelValues(append(append([]string{}, labels...), kind)...)
+		assert.InDelta(t, expected, testutil.ToFloat64(counter), 0, kind)
+	}
+
+	assert.Equal(t, 2, testutil.CollectAndCount(&safe.SafeChanges))
+	assert.Equal(t, 2, testutil.CollectAndCount(&safe.SafeOwnerInfo))
+	assertMetricValue(t, safe.SafeOwnerInfo, append(append([]string{}, labels...), testSafeOwnerC), 1)
+	assertMetricValue(t, safe.SafeThreshold, labels, 1)
+}
+
+func TestSafe_Name(t *testing.T) {
+	safe := &Safe{}
+	if safe.Name() != NameSafe {
+		t.Errorf("Expected name %s, got %s", NameSafe, safe.Name())
+	}
+}
@@ -0,0 +1,333 @@
+package jobs
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameStorageSlot = "storage_slot"
+
+	storageSlotWordBits = 256
+)
+
+var (
+	storageSlotUintKey = abiType{kind: abiKindUint, size: storageSlotWordBits}
+	storageSlotIntKey  = abiType{kind: abiKindInt, size: storageSlotWordBits}
+)
+
+// StorageSlot exposes metrics for raw contract storage slots.
+type StorageSlot struct {
+	*tickTracker
+
+	clients          []api.ExecutionClient
+	log              logrus.FieldLogger
+	StorageSlotValue prometheus.GaugeVec
+	StorageSlotError prometheus.CounterVec
+	checkInterval    time.Duration
+	addresses        []*AddressStorageSlot
+	labelsMap        map[string]int
+	snapshots        *snapshot.Store
+}
+
+type AddressStorageSlot struct {
+	Contract string `yaml:"contract"`
+	// Slot is the base storage slot, decimal or 0x prefixed hex.
+	Slot string `yaml:"slot"`
+	// MappingKeys derive the slot of a (nested) mapping value as
+	// keccak256(key . slot), applied in order.
+	MappingKeys []string `yaml:"mappingKeys"`
+	// ArrayIndex derives the slot of a dynamic array element as
+	// keccak256(slot) + index * elementSlots.
+	ArrayIndex   *big.Int `yaml:"arrayIndex"`
+	ElementSlots int      `yaml:"elementSlots"`
+	// Offset is added to the derived slot, e.g. to select a struct member.
+	Offset int `yaml:"offset"`
+	// BitOffset and BitLength select a packed value within the slot. A zero
+	// BitLength selects all bits above BitOffset.
+	BitOffset int  `yaml:"bitOffset"`
+	BitLength int  `yaml:"bitLength"`
+	Signed    bool `yaml:"signed"`
+	// Decimals divides the raw value by 10^decimals.
+	Decimals int `yaml:"decimals"`
+	// Scale multiplies the value after applying decimals. Defaults to 1.
+	Scale  float64           `yaml:"scale"`
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressStorageSlot) GetName() string { return a.Name }
+
+// Validate checks the slot derivation and bit range are consistent.
+func (a *AddressStorageSlot) Validate() error {
+	if _, err := a.derivedSlot(); err != nil {
+		return fmt.Errorf("storage slot %s: %w", a.Name, err)
+	}
+
+	if a.ElementSlots < 0 || a.Offset < 0 {
+		return fmt.Errorf("storage slot %s: elementSlots and offset must not be negative", a.Name)
+	}
+
+	if a.BitOffset < 0 || a.BitLength < 0 || a.BitOffset+a.BitLength > storageSlotWordBits || a.BitOffset >= storageSlotWordBits {
+		return fmt.Errorf("storage slot %s: bit range must be within a 256 bit word", a.Name)
+	}
+
+	if a.Decimals < 0 || a.Decimals > tokenMaxSupportedDecimals {
+		return fmt.Errorf("storage slot %s: invalid decimals %d", a.Name, a.Decimals)
+	}
+
+	return nil
+}
+
+// derivedSlot returns the storage slot after applying mapping keys, array index and offset.
+func (a *AddressStorageSlot) derivedSlot() (*big.Int, error) {
+	slot, ok := new(big.Int).SetString(a.Slot, 0)
+	if !ok || slot.Sign() < 0 || slot.BitLen() > storageSlotWordBits {
+		return nil, fmt.Errorf("invalid slot %q", a.Slot)
+	}
+
+	var err error
+
+	for _, key := range a.MappingKeys {
+		parsed, ok := new(big.Int).SetString(key, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid mapping key %q", key)
+		}
+
+		// Keys are encoded as a single word, either a uint256 or an int256.
+		if !storageSlotUintKey.fits(parsed) && !storageSlotIntKey.fits(parsed) {
+			return nil, fmt.Errorf("mapping key %q out of range", key)
+		}
+
+		slot, err = keccak256Words(formatABISignedWord(parsed), formatABIWord(slot))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if a.ArrayIndex != nil {
+		if a.ArrayIndex.Sign() < 0 || a.ArrayIndex.BitLen() > storageSlotWordBits {
+			return nil, fmt.Errorf("invalid array index %s", a.ArrayIndex)
+		}
+
+		elementSlots := int64(a.ElementSlots)
+		if elementSlots == 0 {
+			elementSlots = 1
+		}
+
+		slot, err = keccak256Words(formatABIWord(slot))
+		if err != nil {
+			return nil, err
+		}
+
+		slot.Add(slot, new(big.Int).Mul(a.ArrayIndex, big.NewInt(elementSlots)))
+	}
+
+	slot.Add(slot, big.NewInt(int64(a.Offset)))
+
+	return slot.Mod(slot, abiWordModulus), nil
+}
+
+// keccak256Words hashes the concatenation of hex encoded 32 byte words.
+func keccak256Words(words ...string) (*big.Int, error) {
+	data := make([][]byte, len(words))
+
+	for i, word := range words {
+		decoded, err := hex.DecodeString(word)
+		if err != nil || len(decoded) != 32 {
+			return nil, fmt.Errorf("invalid storage word %q", word)
+		}
+
+		data[i] = decoded
+	}
+
+	return new(big.Int).SetBytes(keccak256(data...)), nil
+}
+
+func (n *StorageSlot) Name() string {
+	return NameStorageSlot
+}
+
+// NewStorageSlot returns a new StorageSlot instance.
+func NewStorageSlot(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressStorageSlot, snapshots *snapshot.Store, registerer prometheus.Registerer) StorageSlot {
+	namespace += "_" + NameStorageSlot
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelContract:  1,
+		LabelSlot:      2,
+		LabelExecution: 3,
+	}
+
+	for address := range addresses {
+		for label := range addresses[address].Labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	instance := StorageSlot{
+		clients:       clients,
+		log:           log.WithField("module", NameStorageSlot),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
+		addresses:     addresses,
+		checkInterval: checkInterval,
+		labelsMap:     labelsMap,
+		StorageSlotValue: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "value",
+				Help:        "The scaled value of a bit range of a ethereum contract storage slot.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+		StorageSlotError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting a ethereum contract storage slot.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.StorageSlotValue)
+	registerer.MustRegister(instance.StorageSlotError)
+
+	return instance
+}
+
+func (n *StorageSlot) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *StorageSlot) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		for _, address := range n.addresses {
+			err := n.getValue(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get contract storage slot")
+			}
+		}
+	}
+}
+
+func (n *StorageSlot) getLabelValues(address *AddressStorageSlot, slot, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		if address.Labels != nil && address.Labels[label] != "" {
+			values[index] = address.Labels[label]
+		} else {
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelSlot:
+				values[index] = slot
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+func (n *StorageSlot) getValue(ctx context.Context, client api.ExecutionClient, address *AddressStorageSlot) error {
+	var err error
+
+	slotLabel := address.Slot
+
+	defer func() {
+		if err != nil {
+			n.StorageSlotError.WithLabelValues(n.getLabelValues(address, slotLabel, client.Name())...).Inc()
+			n.snapshots.SetError(NameStorageSlot, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	slot, err := address.derivedSlot()
+	if err != nil {
+		return err
+	}
+
+	slotLabel = formatStorageSlot(slot)
+
+	word, err := ethGetStorageWord(ctx, client, address.Contract, slot)
+	if err != nil {
+		return err
+	}
+
+	raw := extractStorageBits(word, address.BitOffset, address.BitLength, address.Signed)
+
+	value := tokenAmountToFloat64(raw, address.Decimals)
+	if address.Scale != 0 {
+		value *= address.Scale
+	}
+
+	n.StorageSlotValue.WithLabelValues(n.getLabelValues(address, slotLabel, client.Name())...).Set(value)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameStorageSlot,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Contract:  address.Contract,
+		Value:     value,
+	}, time.Now())
+
+	return nil
+}
+
+// extractStorageBits returns bitLength bits of word starting at bitOffset,
+// counted from the least significant bit. A zero bitLength selects all
+// remaining bits. Signed values are interpreted as two's complement.
+func extractStorageBits(word *big.Int, bitOffset, bitLength int, signed bool) *big.Int {
+	if bitLength == 0 {
+		bitLength = storageSlotWordBits - bitOffset
+	}
+
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bitLength)), big.NewInt(1))
+	value := new(big.Int).Rsh(word, uint(bitOffset))
+	value.And(value, mask)
+
+	if signed && value.Bit(bitLength-1) == 1 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(bitLength)))
+	}
+
+	return value
+}
@@ -0,0 +1,209 @@
+package jobs
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// keccak256(abi.encode(uint256(0), uint256(0))), the slot of mapping key 0 at slot 0.
+	testStorageMappingSlot = "0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5"
+	// keccak256(abi.encode(uint256(0))), the first element slot of a dynamic array at slot 0.
+	testStorageArraySlot = "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563"
+)
+
+func TestAddressStorageSlot_derivedSlot(t *testing.T) {
+	t.Parallel()
+
+	arraySlot, _ := new(big.Int).SetString(testStorageArraySlot, 0)
+
+	tests := []struct {
+		name     string
+		address  *AddressStorageSlot
+		expected string
+	}{
+		{
+			name:     "literal decimal slot",
+			address:  &AddressStorageSlot{Slot: "5"},
+			expected: formatStorageSlot(big.NewInt(5)),
+		},
+		{
+			name:     "literal hex slot with offset",
+			address:  &AddressStorageSlot{Slot: "0x10", Offset: 2},
+			expected: formatStorageSlot(big.NewInt(18)),
+		},
+		{
+			name:     "mapping key",
+			address:  &AddressStorageSlot{Slot: "0", MappingKeys: []string{nativeTokenAddress}},
+			expected: testStorageMappingSlot,
+		},
+		{
+			name:     "array element",
+			address:  &AddressStorageSlot{Slot: "0", ArrayIndex: big.NewInt(3), ElementSlots: 2, Offset: 1},
+			expected: formatStorageSlot(new(big.Int).Add(arraySlot, big.NewInt(7))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			slot, err := tt.address.derivedSlot()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, formatStorageSlot(slot))
+		})
+	}
+}
+
+func TestExtractStorageBits(t *testing.T) {
+	t.Parallel()
+
+	// Packed slot: uint8 0x01 (paused flag) at bit 160, address in the low 160 bits.
+	word, _ := new(big.Int).SetString("0x01"+testContractAAddress[2:], 0)
+
+	assert.Equal(t, int64(1), extractStorageBits(word, 160, 8, false).Int64())
+	assert.Equal(t, 0, extractStorageBits(word, 0, 160, false).Cmp(mustParseBigInt(t, testContractAAddress)))
+	assert.Equal(t, 0, extractStorageBits(word, 0, 0, false).Cmp(word))
+
+	negative := big.NewInt(0xff80)
+	assert.Equal(t, int64(-128), extractStorageBits(negative, 0, 8, true).Int64())
+	assert.Equal(t, int64(255), extractStorageBits(negative, 8, 8, false).Int64())
+}
+
+func TestStorageSlot_getValue(t *testing.T) {
+	address := &AddressStorageSlot{
+		Name:        "Paused flag",
+		Contract:    testContractAAddress,
+		Slot:        "0",
+		MappingKeys: []string{nativeTokenAddress},
+		BitOffset:   8,
+		BitLength:   16,
+		Decimals:    2,
+		Scale:       10,
+		Labels:      map[string]string{"kind": "flag"},
+	}
+
+	mockClient := &mockExecutionClient{
+		ethGetStorageAtResponses: map[string]string{
+			testStorageMappingSlot: "0x" + formatABIWord(big.NewInt(0x0400ff)),
+		},
+	}
+
+	storageSlot := NewStorageSlot(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"storage_slot_get",
+		map[string]string{},
+		[]*AddressStorageSlot{address},
+		nil,
+		testRegistry(),
+	)
+
+	err := storageSlot.getValue(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	labels := storageSlot.getLabelValues(address, testStorageMappingSlot, testMockNodeName)
+	assertMetricValue(t, storageSlot.StorageSlotValue, labels, 102.4)
+	assert.Equal(t, []string{testStorageMappingSlot}, mockClient.ethGetStorageAtCalls)
+}
+
+func TestAddressStorageSlot_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		address *AddressStorageSlot
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0x1", BitOffset: 248, BitLength: 8},
+		},
+		{
+			name:    "invalid slot",
+			address: &AddressStorageSlot{Name: "slot", Slot: "zero"},
+			wantErr: "invalid slot",
+		},
+		{
+			name:    "invalid mapping key",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0", MappingKeys: []string{"0xzz"}},
+			wantErr: "invalid mapping key",
+		},
+		{
+			name:    "slot above uint256",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0x1" + strings.Repeat("0", 64)},
+			wantErr: "invalid slot",
+		},
+		{
+			name:    "largest uint256 mapping key",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0", MappingKeys: []string{"0x" + strings.Repeat("f", 64)}},
+		},
+		{
+			name:    "smallest int256 mapping key",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0", MappingKeys: []string{"-0x8" + strings.Repeat("0", 63)}},
+		},
+		{
+			name:    "mapping key above uint256",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0", MappingKeys: []string{"0x1" + strings.Repeat("0", 64)}},
+			wantErr: "mapping key \"0x1" + strings.Repeat("0", 64) + "\" out of range",
+		},
+		{
+			name:    "mapping key below int256",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0", MappingKeys: []string{"-0x8" + strings.Repeat("0", 62) + "1"}},
+			wantErr: "out of range",
+		},
+		{
+			name:    "negative array index",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0", ArrayIndex: big.NewInt(-1)},
+			wantErr: "invalid array index -1",
+		},
+		{
+			name:    "array index above uint256",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0", ArrayIndex: new(big.Int).Lsh(big.NewInt(1), 256)},
+			wantErr: "invalid array index",
+		},
+		{
+			name:    "bit range overflow",
+			address: &AddressStorageSlot{Name: "slot", Slot: "0", BitOffset: 200, BitLength: 64},
+			wantErr: "bit range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.address.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStorageSlot_Name(t *testing.T) {
+	storageSlot := &StorageSlot{}
+	if storageSlot.Name() != NameStorageSlot {
+		t.Errorf("Expected name %s, got %s", NameStorageSlot, storageSlot.Name())
+	}
+}
+
+func mustParseBigInt(t *testing.T, value string) *big.Int {
+	t.Helper()
+
+	parsed, ok := new(big.Int).SetString(value, 0)
+	require.True(t, ok)
+
+	return parsed
+}
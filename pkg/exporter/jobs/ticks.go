@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+)
+
+// tickTracker records when a job last completed a tick.
+type tickTracker struct {
+	mu   sync.RWMutex
+	last time.Time
+}
+
+func newTickTracker() *tickTracker {
+	return &tickTracker{}
+}
+
+func (t *tickTracker) ticked(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.last = now
+}
+
+// LastTick returns when the job last completed a tick, or the zero time
+// before its first tick completed.
+func (t *tickTracker) LastTick() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.last
+}
@@ -0,0 +1,183 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+)
+
+const (
+	tokenSymbolSelector   = "0x95d89b41"
+	tokenDecimalsSelector = "0x313ce567"
+
+	tokenMaxSupportedDecimals = 255
+
+	nativeTokenAddress  = "0x0000000000000000000000000000000000000000"
+	nativeTokenSymbol   = "ETH"
+	nativeTokenDecimals = 18
+)
+
+// isNativeToken returns true when the token address is the zero address, which
+// Uniswap V4 and several other protocols use to represent native ether.
+func isNativeToken(tokenAddress string) bool {
+	return strings.EqualFold(tokenAddress, nativeTokenAddress)
+}
+
+// getTokenSymbol calls symbol() on an ERC20 token contract.
+func getTokenSymbol(ctx context.Context, client api.ExecutionClient, tokenAddress string) (string, error) {
+	if isNativeToken(tokenAddress) {
+		return nativeTokenSymbol, nil
+	}
+
+	callData := tokenSymbolSelector
+
+	symbolHex, err := client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   tokenAddress,
+		Data: &callData,
+	}, "latest")
+	if err != nil {
+		return "", err
+	}
+
+	return hexStringToString(symbolHex)
+}
+
+// getTokenDecimals calls decimals() on an ERC20 token contract.
+func getTokenDecimals(ctx context.Context, client api.ExecutionClient, tokenAddress string) (int, error) {
+	if isNativeToken(tokenAddress) {
+		return nativeTokenDecimals, nil
+	}
+
+	callData := tokenDecimalsSelector
+
+	decimalsHex, err := client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   tokenAddress,
+		Data: &callData,
+	}, "latest")
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := decodeHexBytes(decimalsHex)
+	if err != nil {
+		return 0, err
+	}
+
+	decimals, err := decodeABIWordAsInt(data, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if decimals > tokenMaxSupportedDecimals {
+		return 0, fmt.Errorf("unsupported token decimals: %d", decimals)
+	}
+
+	return decimals, nil
+}
+
+// TokenMetadataCache caches ERC20 token symbols and decimals and the
+// underlying asset of ERC4626 vaults, which are immutable for a deployed
+// contract, so jobs only fetch them once per execution node instead of on every
+// tick. A single cache is shared by all jobs reading ERC20 tokens.
+type TokenMetadataCache struct {
+	mu          sync.Mutex
+	symbols     map[tokenMetadataKey]string
+	decimals    map[tokenMetadataKey]int
+	vaultAssets map[tokenMetadataKey]string
+}
+
+type tokenMetadataKey struct {
+	execution string
+	token     string
+}
+
+// NewTokenMetadataCache returns a new, empty TokenMetadataCache.
+func NewTokenMetadataCache() *TokenMetadataCache {
+	return &TokenMetadataCache{
+		symbols:     make(map[tokenMetadataKey]string),
+		decimals:    make(map[tokenMetadataKey]int),
+		vaultAssets: make(map[tokenMetadataKey]string),
+	}
+}
+
+func newTokenMetadataKey(client api.ExecutionClient, tokenAddress string) tokenMetadataKey {
+	return tokenMetadataKey{
+		execution: client.Name(),
+		token:     strings.ToLower(tokenAddress),
+	}
+}
+
+// Symbol returns the cached symbol of a token, fetching it on first use.
+func (c *TokenMetadataCache) Symbol(ctx context.Context, client api.ExecutionClient, tokenAddress string) (string, error) {
+	key := newTokenMetadataKey(client, tokenAddress)
+
+	c.mu.Lock()
+	symbol, ok := c.symbols[key]
+	c.mu.Unlock()
+
+	if ok {
+		return symbol, nil
+	}
+
+	symbol, err := getTokenSymbol(ctx, client, tokenAddress)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.symbols[key] = symbol
+	c.mu.Unlock()
+
+	return symbol, nil
+}
+
+// Decimals returns the cached decimals of a token, fetching them on first use.
+func (c *TokenMetadataCache) Decimals(ctx context.Context, client api.ExecutionClient, tokenAddress string) (int, error) {
+	key := newTokenMetadataKey(client, tokenAddress)
+
+	c.mu.Lock()
+	decimals, ok := c.decimals[key]
+	c.mu.Unlock()
+
+	if ok {
+		return decimals, nil
+	}
+
+	decimals, err := getTokenDecimals(ctx, client, tokenAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.decimals[key] = decimals
+	c.mu.Unlock()
+
+	return decimals, nil
+}
+
+// VaultAsset returns the cached asset() of an ERC4626 vault, fetching it on first use.
+func (c *TokenMetadataCache) VaultAsset(ctx context.Context, client api.ExecutionClient, vault string) (string, error) {
+	key := newTokenMetadataKey(client, vault)
+
+	c.mu.Lock()
+	asset, ok := c.vaultAssets[key]
+	c.mu.Unlock()
+
+	if ok {
+		return asset, nil
+	}
+
+	asset, err := ethCallAddress(ctx, client, vault, erc4626AssetSelector)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.vaultAssets[key] = asset
+	c.mu.Unlock()
+
+	return asset, nil
+}
@@ -8,10 +8,13 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // UniswapPair exposes metrics for ethereum uniswap pair contract.
 type UniswapPair struct {
+	*tickTracker
+
 	clients            []api.ExecutionClient
 	log                logrus.FieldLogger
 	UniswapPairBalance prometheus.GaugeVec
@@ -19,6 +22,7 @@ type UniswapPair struct {
 	checkInterval      time.Duration
 	addresses          []*AddressUniswapPair
 	labelsMap          map[string]int
+	snapshots          *snapshot.Store
 }
 
 type AddressUniswapPair struct {
@@ -41,7 +45,7 @@ func (n *UniswapPair) Name() string {
 }
 
 // NewUniswapPair returns a new UniswapPair instance.
-func NewUniswapPair(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressUniswapPair) UniswapPair {
+func NewUniswapPair(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressUniswapPair, snapshots *snapshot.Store, registerer prometheus.Registerer) UniswapPair {
 	namespace += "_" + NameUniswapPair
 
 	labelsMap := map[string]int{
@@ -68,6 +72,8 @@ func NewUniswapPair(clients []api.ExecutionClient, log logrus.FieldLogger, check
 	instance := UniswapPair{
 		clients:       clients,
 		log:           log.WithField("module", NameUniswapPair),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
 		addresses:     addresses,
 		checkInterval: checkInterval,
 		labelsMap:     labelsMap,
@@ -91,14 +97,15 @@ func NewUniswapPair(clients []api.ExecutionClient, log logrus.FieldLogger, check
 		),
 	}
 
-	prometheus.MustRegister(instance.UniswapPairBalance)
-	prometheus.MustRegister(instance.UniswapPairError)
+	registerer.MustRegister(instance.UniswapPairBalance)
+	registerer.MustRegister(instance.UniswapPairError)
 
 	return instance
 }
 
 func (n *UniswapPair) Start(ctx context.Context) {
 	n.tick(ctx)
+	n.ticked(time.Now())
 
 	for {
 		select {
@@ -106,6 +113,7 @@ func (n *UniswapPair) Start(ctx context.Context) {
 			return
 		case <-time.After(n.checkInterval):
 			n.tick(ctx)
+			n.ticked(time.Now())
 		}
 	}
 }
@@ -157,6 +165,7 @@ func (n *UniswapPair) getBalance(ctx context.Context, client api.ExecutionClient
 	defer func() {
 		if err != nil {
 			n.UniswapPairError.WithLabelValues(n.getLabelValues(address, client.Name())...).Inc()
+			n.snapshots.SetError(NameUniswapPair, address.Name, client.Name(), err, time.Now())
 		}
 	}()
 
@@ -185,6 +194,13 @@ func (n *UniswapPair) getBalance(ctx context.Context, client api.ExecutionClient
 
 	balance := toBalance / fromBalance
 	n.UniswapPairBalance.WithLabelValues(n.getLabelValues(address, client.Name())...).Set(balance)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameUniswapPair,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Contract:  address.Contract,
+		Value:     balance,
+	}, time.Now())
 
 	return nil
 }
@@ -60,6 +60,8 @@ func TestUniswapPair_getBalance(t *testing.T) {
 				namespace,
 				map[string]string{},
 				[]*AddressUniswapPair{tt.address},
+				nil,
+				testRegistry(),
 			)
 
 			err := uniswap.getBalance(context.Background(), mockClient, tt.address)
@@ -113,6 +115,8 @@ func TestUniswapPair_tick(t *testing.T) {
 		"test_uniswap_tick",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	ctx := context.Background()
@@ -148,6 +152,8 @@ func TestUniswapPair_getLabelValues(t *testing.T) {
 		"test_uniswap_labels",
 		map[string]string{},
 		addresses,
+		nil,
+		testRegistry(),
 	)
 
 	labels := uniswap.getLabelValues(addresses[0], "mock-node")
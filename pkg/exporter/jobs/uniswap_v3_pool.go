@@ -0,0 +1,592 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const (
+	NameUniswapV3Pool = "uniswap_v3_pool"
+
+	uniswapV3PoolSlot0Selector                = "0x3850c7bd"
+	uniswapV3PoolLiquiditySelector            = "0x1a686502"
+	uniswapV3PoolFeeSelector                  = "0xddca3f43"
+	uniswapV3PoolToken0Selector               = "0x0dfe1681"
+	uniswapV3PoolToken1Selector               = "0xd21220a7"
+	uniswapV3PoolFeeGrowthGlobal0Selector     = "0xf3058399"
+	uniswapV3PoolFeeGrowthGlobal1Selector     = "0x46141319"
+	uniswapV3PoolTicksSelector                = "0xf30dba93"
+	uniswapV3PositionManagerPositionsSelector = "0x99fbab88"
+	uniswapV4StateViewGetSlot0Selector        = "0xc815641c"
+	uniswapV4StateViewGetLiquiditySelector    = "0xfa6793d5"
+
+	uniswapV3PositionTickLowerWord        = 5
+	uniswapV3PositionTickUpperWord        = 6
+	uniswapV3PositionLiquidityWord        = 7
+	uniswapV3PositionFeeGrowthInside0Word = 8
+	uniswapV3PositionFeeGrowthInside1Word = 9
+	uniswapV3PositionTokensOwed0Word      = 10
+	uniswapV3PositionTokensOwed1Word      = 11
+	uniswapV3TickFeeGrowthOutside0Word    = 2
+	uniswapV3TickFeeGrowthOutside1Word    = 3
+	uniswapV4Slot0LPFeeWord               = 3
+	uniswapV3FeeDenominator               = 1e6
+	uniswapV3TickBase                     = 1.0001
+	uniswapV3Token0                       = "token0"
+	uniswapV3Token1                       = "token1"
+)
+
+var (
+	uniswapV3Q96  = new(big.Int).Lsh(big.NewInt(1), 96)
+	uniswapV3Q128 = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+// UniswapV3Pool exposes metrics for Uniswap V3 and V4 concentrated liquidity pools.
+type UniswapV3Pool struct {
+	*tickTracker
+
+	clients       []api.ExecutionClient
+	log           logrus.FieldLogger
+	checkInterval time.Duration
+	addresses     []*AddressUniswapV3Pool
+	labelsMap     map[string]int
+	tokens        *TokenMetadataCache
+
+	UniswapV3PoolPrice                   prometheus.GaugeVec
+	UniswapV3PoolTick                    prometheus.GaugeVec
+	UniswapV3PoolLiquidity               prometheus.GaugeVec
+	UniswapV3PoolFee                     prometheus.GaugeVec
+	UniswapV3PoolPositionAmount          prometheus.GaugeVec
+	UniswapV3PoolPositionUncollectedFees prometheus.GaugeVec
+	UniswapV3PoolError                   prometheus.CounterVec
+	snapshots                            *snapshot.Store
+}
+
+type AddressUniswapV3Pool struct {
+	// Contract is the V3 pool address, or the V4 StateView address when PoolID is set.
+	Contract string `yaml:"contract"`
+	// PoolID is the V4 pool id. When set, Token0 and Token1 must also be set.
+	PoolID string `yaml:"poolId"`
+	Token0 string `yaml:"token0"`
+	Token1 string `yaml:"token1"`
+	// PositionManager is the V3 NonfungiblePositionManager holding PositionID.
+	PositionManager string            `yaml:"positionManager"`
+	PositionID      *big.Int          `yaml:"positionId"`
+	Name            string            `yaml:"name"`
+	Labels          map[string]string `yaml:"labels"`
+}
+
+// GetName returns the configured name of this address.
+func (a *AddressUniswapV3Pool) GetName() string { return a.Name }
+
+// Validate checks the pool configuration is consistent.
+func (a *AddressUniswapV3Pool) Validate() error {
+	if a.PoolID != "" {
+		if a.Token0 == "" || a.Token1 == "" {
+			return fmt.Errorf("uniswap v3 pool %s: token0 and token1 must be set when poolId is configured", a.Name)
+		}
+
+		if a.PositionID != nil {
+			return fmt.Errorf("uniswap v3 pool %s: position valuation is only supported for v3 pools", a.Name)
+		}
+	}
+
+	if a.PositionID != nil && a.PositionManager == "" {
+		return fmt.Errorf("uniswap v3 pool %s: positionManager must be set when positionId is configured", a.Name)
+	}
+
+	return nil
+}
+
+func (n *UniswapV3Pool) Name() string {
+	return NameUniswapV3Pool
+}
+
+// NewUniswapV3Pool returns a new UniswapV3Pool instance.
+func NewUniswapV3Pool(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses []*AddressUniswapV3Pool, tokens *TokenMetadataCache, snapshots *snapshot.Store, registerer prometheus.Registerer) UniswapV3Pool {
+	namespace += "_" + NameUniswapV3Pool
+
+	labelsMap := map[string]int{
+		LabelName:      0,
+		LabelContract:  1,
+		LabelFrom:      2,
+		LabelTo:        3,
+		LabelTokenID:   4,
+		LabelExecution: 5,
+	}
+
+	for address := range addresses {
+		for label := range addresses[address].Labels {
+			if _, ok := labelsMap[label]; !ok {
+				labelsMap[label] = len(labelsMap)
+			}
+		}
+	}
+
+	labels := make([]string, len(labelsMap))
+	for label, index := range labelsMap {
+		labels[index] = label
+	}
+
+	tokenLabels := append(append([]string{}, labels...), LabelToken)
+
+	newGaugeVec := func(name, help string, labelNames []string) prometheus.GaugeVec {
+		return *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        name,
+				Help:        help,
+				ConstLabels: constLabels,
+			},
+			labelNames,
+		)
+	}
+
+	instance := UniswapV3Pool{
+		clients:       clients,
+		log:           log.WithField("module", NameUniswapV3Pool),
+		tickTracker:   newTickTracker(),
+		snapshots:     snapshots,
+		addresses:     addresses,
+		checkInterval: checkInterval,
+		labelsMap:     labelsMap,
+		tokens:        tokens,
+		UniswapV3PoolPrice: newGaugeVec(
+			"price",
+			"The decimal adjusted price of token0 denominated in token1 of a uniswap v3 pool.",
+			labels,
+		),
+		UniswapV3PoolTick: newGaugeVec(
+			"tick",
+			"The current tick of a uniswap v3 pool.",
+			labels,
+		),
+		UniswapV3PoolLiquidity: newGaugeVec(
+			"liquidity",
+			"The in-range liquidity of a uniswap v3 pool.",
+			labels,
+		),
+		UniswapV3PoolFee: newGaugeVec(
+			"fee",
+			"The swap fee of a uniswap v3 pool as a fraction.",
+			labels,
+		),
+		UniswapV3PoolPositionAmount: newGaugeVec(
+			"position_amount",
+			"The decimal adjusted token amount held by a uniswap v3 liquidity position.",
+			tokenLabels,
+		),
+		UniswapV3PoolPositionUncollectedFees: newGaugeVec(
+			"position_uncollected_fees",
+			"The decimal adjusted uncollected fees owed to a uniswap v3 liquidity position.",
+			tokenLabels,
+		),
+		UniswapV3PoolError: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        metricNameErrorsTotal,
+				Help:        "The total errors when getting the state of a uniswap v3 pool.",
+				ConstLabels: constLabels,
+			},
+			labels,
+		),
+	}
+
+	registerer.MustRegister(instance.UniswapV3PoolPrice)
+	registerer.MustRegister(instance.UniswapV3PoolTick)
+	registerer.MustRegister(instance.UniswapV3PoolLiquidity)
+	registerer.MustRegister(instance.UniswapV3PoolFee)
+	registerer.MustRegister(instance.UniswapV3PoolPositionAmount)
+	registerer.MustRegister(instance.UniswapV3PoolPositionUncollectedFees)
+	registerer.MustRegister(instance.UniswapV3PoolError)
+
+	return instance
+}
+
+func (n *UniswapV3Pool) Start(ctx context.Context) {
+	n.tick(ctx)
+	n.ticked(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.checkInterval):
+			n.tick(ctx)
+			n.ticked(time.Now())
+		}
+	}
+}
+
+func (n *UniswapV3Pool) tick(ctx context.Context) {
+	for _, client := range n.clients {
+		for _, address := range n.addresses {
+			err := n.getPool(ctx, client, address)
+			if err != nil {
+				n.log.WithError(err).WithFields(logrus.Fields{
+					LabelAddress:   address,
+					LabelExecution: client.Name(),
+				}).Error("Failed to get uniswap v3 pool state")
+			}
+		}
+	}
+}
+
+func (n *UniswapV3Pool) getLabelValues(address *AddressUniswapV3Pool, from, to, executionName string) []string {
+	values := make([]string, len(n.labelsMap))
+
+	for label, index := range n.labelsMap {
+		if address.Labels != nil && address.Labels[label] != "" {
+			values[index] = address.Labels[label]
+		} else {
+			switch label {
+			case LabelName:
+				values[index] = address.Name
+			case LabelContract:
+				values[index] = address.Contract
+			case LabelFrom:
+				values[index] = from
+			case LabelTo:
+				values[index] = to
+			case LabelTokenID:
+				if address.PositionID != nil {
+					values[index] = address.PositionID.String()
+				} else {
+					values[index] = LabelDefaultValue
+				}
+			case LabelExecution:
+				values[index] = executionName
+			default:
+				values[index] = LabelDefaultValue
+			}
+		}
+	}
+
+	return values
+}
+
+type uniswapV3PoolState struct {
+	sqrtPriceX96 *big.Int
+	tick         *big.Int
+	liquidity    *big.Int
+	fee          *big.Int
+	token0       string
+	token1       string
+}
+
+type uniswapV3PoolToken struct {
+	symbol   string
+	decimals int
+}
+
+func (n *UniswapV3Pool) getPool(ctx context.Context, client api.ExecutionClient, address *AddressUniswapV3Pool) error {
+	var err error
+
+	from, to := "", ""
+
+	defer func() {
+		if err != nil {
+			n.UniswapV3PoolError.WithLabelValues(n.getLabelValues(address, from, to, client.Name())...).Inc()
+			n.snapshots.SetError(NameUniswapV3Pool, address.Name, client.Name(), err, time.Now())
+		}
+	}()
+
+	var state *uniswapV3PoolState
+
+	if address.PoolID != "" {
+		state, err = n.getV4PoolState(ctx, client, address)
+	} else {
+		state, err = n.getV3PoolState(ctx, client, address)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	token0, err := n.getToken(ctx, client, state.token0)
+	if err != nil {
+		return err
+	}
+
+	from = token0.symbol
+
+	token1, err := n.getToken(ctx, client, state.token1)
+	if err != nil {
+		return err
+	}
+
+	to = token1.symbol
+
+	labels := n.getLabelValues(address, from, to, client.Name())
+
+	price := uniswapV3SqrtPriceToPrice(state.sqrtPriceX96, token0.decimals, token1.decimals)
+	n.UniswapV3PoolPrice.WithLabelValues(labels...).Set(price)
+	n.snapshots.Set(&snapshot.Update{
+		Job:       NameUniswapV3Pool,
+		Name:      address.Name,
+		Execution: client.Name(),
+		Contract:  address.Contract,
+		Value:     price,
+	}, time.Now())
+	n.UniswapV3PoolTick.WithLabelValues(labels...).Set(bigIntToFloat64(state.tick))
+	n.UniswapV3PoolLiquidity.WithLabelValues(labels...).Set(bigIntToFloat64(state.liquidity))
+	n.UniswapV3PoolFee.WithLabelValues(labels...).Set(bigIntToFloat64(state.fee) / uniswapV3FeeDenominator)
+
+	if address.PositionID == nil {
+		return nil
+	}
+
+	err = n.getPosition(ctx, client, address, state, labels, token0, token1)
+
+	return err
+}
+
+func (n *UniswapV3Pool) getToken(ctx context.Context, client api.ExecutionClient, tokenAddress string) (uniswapV3PoolToken, error) {
+	symbol, err := n.tokens.Symbol(ctx, client, tokenAddress)
+	if err != nil {
+		return uniswapV3PoolToken{}, err
+	}
+
+	decimals, err := n.tokens.Decimals(ctx, client, tokenAddress)
+	if err != nil {
+		return uniswapV3PoolToken{}, err
+	}
+
+	return uniswapV3PoolToken{symbol: symbol, decimals: decimals}, nil
+}
+
+func (n *UniswapV3Pool) getV3PoolState(ctx context.Context, client api.ExecutionClient, address *AddressUniswapV3Pool) (*uniswapV3PoolState, error) {
+	slot0, err := ethCallABI(ctx, client, address.Contract, uniswapV3PoolSlot0Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	sqrtPriceX96, err := decodeABIWordAsBigInt(slot0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tick, err := decodeABIWordAsSignedBigInt(slot0, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	liquidity, err := ethCallUint256(ctx, client, address.Contract, uniswapV3PoolLiquiditySelector)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := ethCallUint256(ctx, client, address.Contract, uniswapV3PoolFeeSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	token0, err := ethCallAddress(ctx, client, address.Contract, uniswapV3PoolToken0Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	token1, err := ethCallAddress(ctx, client, address.Contract, uniswapV3PoolToken1Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uniswapV3PoolState{
+		sqrtPriceX96: sqrtPriceX96,
+		tick:         tick,
+		liquidity:    liquidity,
+		fee:          fee,
+		token0:       token0,
+		token1:       token1,
+	}, nil
+}
+
+func (n *UniswapV3Pool) getV4PoolState(ctx context.Context, client api.ExecutionClient, address *AddressUniswapV3Pool) (*uniswapV3PoolState, error) {
+	poolID, err := encodeBytes32Argument(address.PoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	slot0, err := ethCallABI(ctx, client, address.Contract, uniswapV4StateViewGetSlot0Selector+poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	sqrtPriceX96, err := decodeABIWordAsBigInt(slot0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tick, err := decodeABIWordAsSignedBigInt(slot0, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := decodeABIWordAsBigInt(slot0, uniswapV4Slot0LPFeeWord*32)
+	if err != nil {
+		return nil, err
+	}
+
+	liquidity, err := ethCallUint256(ctx, client, address.Contract, uniswapV4StateViewGetLiquiditySelector+poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uniswapV3PoolState{
+		sqrtPriceX96: sqrtPriceX96,
+		tick:         tick,
+		liquidity:    liquidity,
+		fee:          fee,
+		token0:       address.Token0,
+		token1:       address.Token1,
+	}, nil
+}
+
+func (n *UniswapV3Pool) getPosition(ctx context.Context, client api.ExecutionClient, address *AddressUniswapV3Pool, state *uniswapV3PoolState, labels []string, token0, token1 uniswapV3PoolToken) error {
+	position, err := ethCallABI(ctx, client, address.PositionManager, uniswapV3PositionManagerPositionsSelector+formatABIWord(address.PositionID))
+	if err != nil {
+		return err
+	}
+
+	words := make([]*big.Int, uniswapV3PositionTokensOwed1Word+1)
+
+	for i := range words {
+		if i == uniswapV3PositionTickLowerWord || i == uniswapV3PositionTickUpperWord {
+			words[i], err = decodeABIWordAsSignedBigInt(position, i*32)
+		} else {
+			words[i], err = decodeABIWordAsBigInt(position, i*32)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	tickLower := words[uniswapV3PositionTickLowerWord]
+	tickUpper := words[uniswapV3PositionTickUpperWord]
+	liquidity := words[uniswapV3PositionLiquidityWord]
+
+	amount0, amount1 := uniswapV3PositionAmounts(liquidity, state.sqrtPriceX96, tickLower, tickUpper)
+
+	fees0, err := n.getUncollectedFees(ctx, client, address.Contract, uniswapV3PoolFeeGrowthGlobal0Selector, uniswapV3TickFeeGrowthOutside0Word,
+		state.tick, tickLower, tickUpper, liquidity, words[uniswapV3PositionFeeGrowthInside0Word], words[uniswapV3PositionTokensOwed0Word])
+	if err != nil {
+		return err
+	}
+
+	fees1, err := n.getUncollectedFees(ctx, client, address.Contract, uniswapV3PoolFeeGrowthGlobal1Selector, uniswapV3TickFeeGrowthOutside1Word,
+		state.tick, tickLower, tickUpper, liquidity, words[uniswapV3PositionFeeGrowthInside1Word], words[uniswapV3PositionTokensOwed1Word])
+	if err != nil {
+		return err
+	}
+
+	token0Labels := append(append([]string{}, labels...), uniswapV3Token0)
+	token1Labels := append(append([]string{}, labels...), uniswapV3Token1)
+
+	n.UniswapV3PoolPositionAmount.WithLabelValues(token0Labels...).Set(amount0 / math.Pow10(token0.decimals))
+	n.UniswapV3PoolPositionAmount.WithLabelValues(token1Labels...).Set(amount1 / math.Pow10(token1.decimals))
+	n.UniswapV3PoolPositionUncollectedFees.WithLabelValues(token0Labels...).Set(tokenAmountToFloat64(fees0, token0.decimals))
+	n.UniswapV3PoolPositionUncollectedFees.WithLabelValues(token1Labels...).Set(tokenAmountToFloat64(fees1, token1.decimals))
+
+	return nil
+}
+
+// getUncollectedFees mirrors the pool's Tick.getFeeGrowthInside and Position.update
+// accounting to compute fees owed to a position that have not been checkpointed yet.
+func (n *UniswapV3Pool) getUncollectedFees(ctx context.Context, client api.ExecutionClient, pool, globalSelector string, outsideWord int, currentTick, tickLower, tickUpper, liquidity, insideLast, tokensOwed *big.Int) (*big.Int, error) {
+	global, err := ethCallUint256(ctx, client, pool, globalSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	lower, err := ethCallABI(ctx, client, pool, uniswapV3PoolTicksSelector+formatABISignedWord(tickLower))
+	if err != nil {
+		return nil, err
+	}
+
+	lowerOutside, err := decodeABIWordAsBigInt(lower, outsideWord*32)
+	if err != nil {
+		return nil, err
+	}
+
+	upper, err := ethCallABI(ctx, client, pool, uniswapV3PoolTicksSelector+formatABISignedWord(tickUpper))
+	if err != nil {
+		return nil, err
+	}
+
+	upperOutside, err := decodeABIWordAsBigInt(upper, outsideWord*32)
+	if err != nil {
+		return nil, err
+	}
+
+	below := lowerOutside
+	if currentTick.Cmp(tickLower) < 0 {
+		below = subABIWords(global, lowerOutside)
+	}
+
+	above := upperOutside
+	if currentTick.Cmp(tickUpper) >= 0 {
+		above = subABIWords(global, upperOutside)
+	}
+
+	inside := subABIWords(subABIWords(global, below), above)
+
+	fees := new(big.Int).Mul(subABIWords(inside, insideLast), liquidity)
+	fees.Quo(fees, uniswapV3Q128)
+
+	return fees.Add(fees, tokensOwed), nil
+}
+
+// uniswapV3SqrtPriceToPrice converts a Q64.96 square root price into the decimal
+// adjusted price of token0 denominated in token1.
+func uniswapV3SqrtPriceToPrice(sqrtPriceX96 *big.Int, decimals0, decimals1 int) float64 {
+	sqrtPrice := new(big.Float).SetPrec(256).SetInt(sqrtPriceX96)
+	sqrtPrice.Quo(sqrtPrice, new(big.Float).SetPrec(256).SetInt(uniswapV3Q96))
+
+	price := new(big.Float).SetPrec(256).Mul(sqrtPrice, sqrtPrice)
+
+	result, _ := price.Float64()
+
+	return result * math.Pow10(decimals0-decimals1)
+}
+
+// uniswapV3PositionAmounts returns the raw token0 and token1 amounts backing a
+// position with the given liquidity and tick range at the current pool price.
+func uniswapV3PositionAmounts(liquidity, sqrtPriceX96, tickLower, tickUpper *big.Int) (float64, float64) {
+	l := bigIntToFloat64(liquidity)
+	sqrtPrice := bigIntToFloat64(sqrtPriceX96) / bigIntToFloat64(uniswapV3Q96)
+	sqrtLower := math.Pow(uniswapV3TickBase, bigIntToFloat64(tickLower)/2)
+	sqrtUpper := math.Pow(uniswapV3TickBase, bigIntToFloat64(tickUpper)/2)
+
+	switch {
+	case sqrtPrice <= sqrtLower:
+		return l * (sqrtUpper - sqrtLower) / (sqrtLower * sqrtUpper), 0
+	case sqrtPrice >= sqrtUpper:
+		return 0, l * (sqrtUpper - sqrtLower)
+	default:
+		return l * (sqrtUpper - sqrtPrice) / (sqrtPrice * sqrtUpper), l * (sqrtPrice - sqrtLower)
+	}
+}
+
+func encodeBytes32Argument(value string) (string, error) {
+	cleaned := strings.TrimPrefix(strings.ToLower(value), "0x")
+	if len(cleaned) != 64 {
+		return "", fmt.Errorf("invalid bytes32 length: %s", value)
+	}
+
+	if _, err := decodeHexBytes(cleaned); err != nil {
+		return "", fmt.Errorf("invalid bytes32: %w", err)
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,281 @@
+package jobs
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUniswapV3Pool            = "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"
+	testUniswapV3PositionManager = "0xC36442b4a4522E871399CD717aBDD847Ab11FE88"
+	testUniswapV4StateView       = "0x7fFE42C4a5DEeA5b0feC41C94C136Cf115597227"
+	testUniswapV4PoolID          = "0x21c67e77068de97969ba93d4aab21826d33ca12bb9f565d8496e8fda8a82ca27"
+)
+
+func encodeABIWordsReturn(words ...*big.Int) string {
+	var builder strings.Builder
+
+	builder.WriteString("0x")
+
+	for _, word := range words {
+		builder.WriteString(formatABISignedWord(word))
+	}
+
+	return builder.String()
+}
+
+func TestUniswapV3Pool_getPool(t *testing.T) {
+	address := &AddressUniswapV3Pool{
+		Name:     "USDC/ETH",
+		Contract: testUniswapV3Pool,
+		Labels:   map[string]string{},
+	}
+
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(2), 96)
+
+	mockClient := &mockExecutionClient{
+		symbolResponse:   encodeABIStringReturn("WETH"),
+		decimalsResponse: encodeABIUintReturn(18),
+		ethCallResponses: map[string]string{
+			uniswapV3PoolSlot0Selector:     encodeABIWordsReturn(sqrtPriceX96, big.NewInt(-100)),
+			uniswapV3PoolLiquiditySelector: encodeABIUintReturn(12345),
+			uniswapV3PoolFeeSelector:       encodeABIUintReturn(3000),
+			uniswapV3PoolToken0Selector:    encodeABIAddressReturn(testContractAAddress),
+			uniswapV3PoolToken1Selector:    encodeABIAddressReturn(testContractBAddress),
+		},
+	}
+
+	pool := NewUniswapV3Pool(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"uniswap_v3_pool_get",
+		map[string]string{},
+		[]*AddressUniswapV3Pool{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
+	)
+
+	err := pool.getPool(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	labels := []string{"USDC/ETH", testUniswapV3Pool, "WETH", "WETH", "", testMockNodeName}
+
+	assertMetricValue(t, pool.UniswapV3PoolPrice, labels, 4)
+	assertMetricValue(t, pool.UniswapV3PoolTick, labels, -100)
+	assertMetricValue(t, pool.UniswapV3PoolLiquidity, labels, 12345)
+	assertMetricValue(t, pool.UniswapV3PoolFee, labels, 0.003)
+
+	assertCallSelectors(t, mockClient.callLog, []string{
+		uniswapV3PoolSlot0Selector,
+		uniswapV3PoolLiquiditySelector,
+		uniswapV3PoolFeeSelector,
+		uniswapV3PoolToken0Selector,
+		uniswapV3PoolToken1Selector,
+		tokenSymbolSelector,
+		tokenDecimalsSelector,
+		tokenSymbolSelector,
+		tokenDecimalsSelector,
+	})
+
+	// Token symbols and decimals are read from the metadata cache afterwards.
+	mockClient.callLog = nil
+
+	require.NoError(t, pool.getPool(context.Background(), mockClient, address))
+
+	assertCallSelectors(t, mockClient.callLog, []string{
+		uniswapV3PoolSlot0Selector,
+		uniswapV3PoolLiquiditySelector,
+		uniswapV3PoolFeeSelector,
+		uniswapV3PoolToken0Selector,
+		uniswapV3PoolToken1Selector,
+	})
+}
+
+func TestUniswapV3Pool_getPool_Position(t *testing.T) {
+	address := &AddressUniswapV3Pool{
+		Name:            "Position",
+		Contract:        testUniswapV3Pool,
+		PositionManager: testUniswapV3PositionManager,
+		PositionID:      big.NewInt(42),
+		Labels:          map[string]string{},
+	}
+
+	liquidity := big.NewInt(1e18)
+	zero := big.NewInt(0)
+
+	mockClient := &mockExecutionClient{
+		symbolResponse:   encodeABIStringReturn("WETH"),
+		decimalsResponse: encodeABIUintReturn(18),
+		ethCallResponses: map[string]string{
+			uniswapV3PoolSlot0Selector:            encodeABIWordsReturn(new(big.Int).Set(uniswapV3Q96), zero),
+			uniswapV3PoolLiquiditySelector:        encodeABIUintReturn(1),
+			uniswapV3PoolFeeSelector:              encodeABIUintReturn(500),
+			uniswapV3PoolToken0Selector:           encodeABIAddressReturn(testContractAAddress),
+			uniswapV3PoolToken1Selector:           encodeABIAddressReturn(testContractBAddress),
+			uniswapV3PoolFeeGrowthGlobal0Selector: encodeABIWordsReturn(uniswapV3Q128),
+			uniswapV3PoolFeeGrowthGlobal1Selector: encodeABIWordsReturn(new(big.Int).Lsh(uniswapV3Q128, 1)),
+			uniswapV3PoolTicksSelector:            encodeABIWordsReturn(zero, zero, zero, zero),
+			uniswapV3PositionManagerPositionsSelector: encodeABIWordsReturn(
+				zero, zero, zero, zero, zero,
+				big.NewInt(-60), big.NewInt(60),
+				liquidity,
+				zero, zero,
+				big.NewInt(5e17), zero,
+			),
+		},
+	}
+
+	pool := NewUniswapV3Pool(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"uniswap_v3_pool_position",
+		map[string]string{},
+		[]*AddressUniswapV3Pool{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
+	)
+
+	err := pool.getPool(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	sqrtLower := math.Pow(uniswapV3TickBase, -30)
+	sqrtUpper := math.Pow(uniswapV3TickBase, 30)
+
+	token0Labels := []string{"Position", testUniswapV3Pool, "WETH", "WETH", "42", testMockNodeName, uniswapV3Token0}
+	token1Labels := []string{"Position", testUniswapV3Pool, "WETH", "WETH", "42", testMockNodeName, uniswapV3Token1}
+
+	assertMetricValue(t, pool.UniswapV3PoolPositionAmount, token0Labels, (sqrtUpper-1)/sqrtUpper)
+	assertMetricValue(t, pool.UniswapV3PoolPositionAmount, token1Labels, 1-sqrtLower)
+	assertMetricValue(t, pool.UniswapV3PoolPositionUncollectedFees, token0Labels, 1.5)
+	assertMetricValue(t, pool.UniswapV3PoolPositionUncollectedFees, token1Labels, 2)
+}
+
+func TestUniswapV3Pool_getPool_V4(t *testing.T) {
+	address := &AddressUniswapV3Pool{
+		Name:     "ETH/USDC v4",
+		Contract: testUniswapV4StateView,
+		PoolID:   testUniswapV4PoolID,
+		Token0:   nativeTokenAddress,
+		Token1:   testUSDCContract,
+		Labels:   map[string]string{},
+	}
+
+	mockClient := &mockExecutionClient{
+		symbolResponse:   testABISymbolUSDCResponse,
+		decimalsResponse: encodeABIUintReturn(18),
+		ethCallResponses: map[string]string{
+			uniswapV4StateViewGetSlot0Selector: encodeABIWordsReturn(
+				new(big.Int).Set(uniswapV3Q96), big.NewInt(-5), big.NewInt(0), big.NewInt(500),
+			),
+			uniswapV4StateViewGetLiquiditySelector: encodeABIUintReturn(777),
+		},
+	}
+
+	pool := NewUniswapV3Pool(
+		mockClients(mockClient),
+		testLogger(),
+		15*time.Second,
+		"uniswap_v3_pool_v4",
+		map[string]string{},
+		[]*AddressUniswapV3Pool{address},
+		NewTokenMetadataCache(),
+		nil,
+		testRegistry(),
+	)
+
+	err := pool.getPool(context.Background(), mockClient, address)
+	require.NoError(t, err)
+
+	labels := []string{"ETH/USDC v4", testUniswapV4StateView, nativeTokenSymbol, testNameUSDC, "", testMockNodeName}
+
+	assertMetricValue(t, pool.UniswapV3PoolPrice, labels, 1)
+	assertMetricValue(t, pool.UniswapV3PoolTick, labels, -5)
+	assertMetricValue(t, pool.UniswapV3PoolLiquidity, labels, 777)
+	assertMetricValue(t, pool.UniswapV3PoolFee, labels, 0.0005)
+
+	assertCallSelectors(t, mockClient.callLog, []string{
+		uniswapV4StateViewGetSlot0Selector,
+		uniswapV4StateViewGetLiquiditySelector,
+		tokenSymbolSelector,
+		tokenDecimalsSelector,
+	})
+	assert.True(t, strings.HasSuffix(mockClient.callLog[0].data, testUniswapV4PoolID[2:]))
+}
+
+func TestUniswapV3SqrtPriceToPrice(t *testing.T) {
+	t.Parallel()
+
+	// USDC (6 decimals) / WETH (18 decimals) pool at 1 ETH = 2000 USDC.
+	sqrtPrice := new(big.Float).SetFloat64(math.Sqrt(1 / 2000.0 * 1e12))
+	sqrtPrice.Mul(sqrtPrice, new(big.Float).SetInt(uniswapV3Q96))
+	sqrtPriceX96, _ := sqrtPrice.Int(nil)
+
+	price := uniswapV3SqrtPriceToPrice(sqrtPriceX96, 6, 18)
+	assert.InEpsilon(t, 1/2000.0, price, 1e-9)
+}
+
+func TestAddressUniswapV3Pool_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		address *AddressUniswapV3Pool
+		wantErr string
+	}{
+		{
+			name:    "v3 pool",
+			address: &AddressUniswapV3Pool{Name: "pool", Contract: testUniswapV3Pool},
+		},
+		{
+			name:    "v4 pool without tokens",
+			address: &AddressUniswapV3Pool{Name: "pool", Contract: testUniswapV4StateView, PoolID: testUniswapV4PoolID},
+			wantErr: "token0 and token1 must be set",
+		},
+		{
+			name: "v4 pool with position",
+			address: &AddressUniswapV3Pool{
+				Name: "pool", Contract: testUniswapV4StateView, PoolID: testUniswapV4PoolID,
+				Token0: testContractAAddress, Token1: testContractBAddress, PositionID: big.NewInt(1),
+			},
+			wantErr: "only supported for v3 pools",
+		},
+		{
+			name:    "position without manager",
+			address: &AddressUniswapV3Pool{Name: "pool", Contract: testUniswapV3Pool, PositionID: big.NewInt(1)},
+			wantErr: "positionManager must be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.address.Validate()
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUniswapV3Pool_Name(t *testing.T) {
+	pool := &UniswapV3Pool{}
+	if pool.Name() != NameUniswapV3Pool {
+		t.Errorf("Expected name %s, got %s", NameUniswapV3Pool, pool.Name())
+	}
+}
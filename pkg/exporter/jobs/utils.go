@@ -2,20 +2,43 @@ package jobs
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
+	"fmt"
 	"math/big"
+	"strings"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
 )
 
 const (
-	LabelAddress      string = "address"
-	LabelContract     string = "contract"
-	LabelDefaultValue string = ""
-	LabelExecution    string = "execution"
-	LabelFrom         string = "from"
-	LabelName         string = "name"
-	LabelSymbol       string = "symbol"
-	LabelTo           string = "to"
-	LabelTokenID      string = "token_id"
+	LabelAddress        string = "address"
+	LabelAdmin          string = "admin"
+	LabelBeacon         string = "beacon"
+	LabelContract       string = "contract"
+	LabelDefaultValue   string = ""
+	LabelDirection      string = "direction"
+	LabelEvent          string = "event"
+	LabelExecution      string = "execution"
+	LabelFrom           string = "from"
+	LabelGuard          string = "guard"
+	LabelImplementation string = "implementation"
+	LabelKey            string = "key"
+	LabelKind           string = "kind"
+	LabelName           string = "name"
+	LabelOwner          string = "owner"
+	LabelPercentile     string = "percentile"
+	LabelRequestID      string = "request_id"
+	LabelSlot           string = "slot"
+	LabelSpender        string = "spender"
+	LabelStandard       string = "standard"
+	LabelStatus         string = "status"
+	LabelSymbol         string = "symbol"
+	LabelTo             string = "to"
+	LabelToken          string = "token"
+	LabelTokenID        string = "token_id"
+	LabelURI            string = "uri"
+	LabelVersion        string = "version"
 )
 
 const (
@@ -67,3 +90,98 @@ func hexStringToString(hexStr string) (string, error) {
 
 	return string(stringData), nil
 }
+
+// ethCallABI executes an eth_call against the latest block and returns the raw ABI encoded response.
+func ethCallABI(ctx context.Context, client api.ExecutionClient, to, data string) ([]byte, error) {
+	rsp, err := client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   to,
+		Data: &data,
+	}, "latest")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeHexBytes(rsp)
+}
+
+// formatStorageSlot formats a storage slot as a 0x prefixed 32 byte hex string.
+func formatStorageSlot(slot *big.Int) string {
+	return "0x" + formatABIWord(slot)
+}
+
+// ethGetStorageWord reads a storage slot of a contract at the latest block.
+func ethGetStorageWord(ctx context.Context, client api.ExecutionClient, contract string, slot *big.Int) (*big.Int, error) {
+	rsp, err := client.ETHGetStorageAt(ctx, contract, formatStorageSlot(slot), "latest")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decodeHexBytes(rsp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > 32 {
+		return nil, fmt.Errorf("storage value exceeds 32 bytes: %d", len(data))
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// ethCallAddress executes an eth_call against the latest block and decodes an address response.
+func ethCallAddress(ctx context.Context, client api.ExecutionClient, to, data string) (string, error) {
+	rsp, err := client.ETHCall(ctx, &api.ETHCallTransaction{
+		To:   to,
+		Data: &data,
+	}, "latest")
+	if err != nil {
+		return "", err
+	}
+
+	return decodeABIAddress(rsp)
+}
+
+// ethCallUint256 executes an eth_call against the latest block and decodes a uint256 response.
+func ethCallUint256(ctx context.Context, client api.ExecutionClient, to, data string) (*big.Int, error) {
+	rsp, err := ethCallABI(ctx, client, to, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeABIWordAsBigInt(rsp, 0)
+}
+
+// hexStringToBigInt parses a hex quantity, treating an empty value as zero.
+func hexStringToBigInt(hexStr string) (*big.Int, error) {
+	trimmed := strings.TrimPrefix(hexStr, "0x")
+	if trimmed == "" {
+		return new(big.Int), nil
+	}
+
+	value, ok := new(big.Int).SetString(trimmed, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex quantity %q", hexStr)
+	}
+
+	return value, nil
+}
+
+func bigIntToFloat64(value *big.Int) float64 {
+	result, _ := new(big.Float).SetInt(value).Float64()
+
+	return result
+}
+
+func tokenAmountToFloat64(amount *big.Int, decimals int) float64 {
+	if amount.Sign() == 0 {
+		return 0
+	}
+
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value := new(big.Float).SetPrec(256).SetInt(amount)
+	value.Quo(value, new(big.Float).SetPrec(256).SetInt(denominator))
+
+	result, _ := value.Float64()
+
+	return result
+}
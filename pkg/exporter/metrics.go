@@ -2,50 +2,94 @@ package exporter
 
 import (
 	"context"
+	"sort"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
 	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
 )
 
 // Metrics exposes Execution layer metrics.
 type Metrics interface {
 	// StartAsync starts all the metrics jobs
 	StartAsync(ctx context.Context)
+	// Jobs returns the enabled jobs sorted by name
+	Jobs() []JobStatus
+}
+
+// JobStatus is the progress of an enabled job.
+type JobStatus struct {
+	Name string
+	// LastTick is when the job last completed a tick, zero before its first.
+	LastTick time.Time
+}
+
+// job is implemented by every metrics job.
+type job interface {
+	Name() string
+	LastTick() time.Time
 }
 
 type metrics struct {
 	log                              logrus.FieldLogger
 	accountMetrics                   jobs.Account
 	erc20Metrics                     jobs.ERC20
+	erc20AllowanceMetrics            jobs.ERC20Allowance
 	erc721Metrics                    jobs.ERC721
 	erc1155Metrics                   jobs.ERC1155
 	erc4626Metrics                   jobs.ERC4626
 	lidoWithdrawalQueueERC721Metrics jobs.LidoWithdrawalQueueERC721
 	uniswapPairMetrics               jobs.UniswapPair
+	uniswapV3PoolMetrics             jobs.UniswapV3Pool
 	chainlinkDataFeedMetrics         jobs.ChainlinkDataFeed
 	erc4337Metrics                   jobs.ERC4337
+	contractCallMetrics              jobs.ContractCall
+	storageSlotMetrics               jobs.StorageSlot
+	proxyMetrics                     jobs.Proxy
+	safeMetrics                      jobs.Safe
+	lstRateMetrics                   jobs.LSTRate
+	lendingPositionMetrics           jobs.LendingPosition
+	logsMetrics                      jobs.Logs
+	networkMetrics                   jobs.Network
 
 	enabledJobs map[string]bool
 }
 
-// NewMetrics creates a new execution Metrics instance.
-func NewMetrics(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses *Addresses) Metrics {
+// NewMetrics creates a new execution Metrics instance. Jobs persist state
+// across restarts in stateDir when it is set, evaluate the alerts of their
+// addresses with alerting and record the latest value of their addresses in
+// snapshots. The metrics of every job are registered with registerer.
+func NewMetrics(clients []api.ExecutionClient, log logrus.FieldLogger, checkInterval time.Duration, namespace string, constLabels map[string]string, addresses *Addresses, network *jobs.NetworkConfig, stateDir string, alerting *alerts.Manager, snapshots *snapshot.Store, registerer prometheus.Registerer) Metrics {
+	tokens := jobs.NewTokenMetadataCache()
+
 	m := &metrics{
 		log:                              log,
-		accountMetrics:                   jobs.NewAccount(clients, log, checkInterval, namespace, constLabels, addresses.Account),
-		erc20Metrics:                     jobs.NewERC20(clients, log, checkInterval, namespace, constLabels, addresses.ERC20),
-		erc721Metrics:                    jobs.NewERC721(clients, log, checkInterval, namespace, constLabels, addresses.ERC721),
-		erc1155Metrics:                   jobs.NewERC1155(clients, log, checkInterval, namespace, constLabels, addresses.ERC1155),
-		erc4626Metrics:                   jobs.NewERC4626(clients, log, checkInterval, namespace, constLabels, addresses.ERC4626),
-		lidoWithdrawalQueueERC721Metrics: jobs.NewLidoWithdrawalQueueERC721(clients, log, checkInterval, namespace, constLabels, addresses.LidoWithdrawalQueueERC721),
-		uniswapPairMetrics:               jobs.NewUniswapPair(clients, log, checkInterval, namespace, constLabels, addresses.UniswapPair),
-		chainlinkDataFeedMetrics:         jobs.NewChainlinkDataFeed(clients, log, checkInterval, namespace, constLabels, addresses.ChainlinkDataFeed),
-		erc4337Metrics:                   jobs.NewERC4337(clients, log, checkInterval, namespace, constLabels, addresses.ERC4337),
+		accountMetrics:                   jobs.NewAccount(clients, log, checkInterval, namespace, constLabels, addresses.Account, alerting, snapshots, registerer),
+		erc20Metrics:                     jobs.NewERC20(clients, log, checkInterval, namespace, constLabels, addresses.ERC20, tokens, alerting, snapshots, registerer),
+		erc20AllowanceMetrics:            jobs.NewERC20Allowance(clients, log, checkInterval, namespace, constLabels, addresses.ERC20Allowance, tokens, snapshots, registerer),
+		erc721Metrics:                    jobs.NewERC721(clients, log, checkInterval, namespace, constLabels, addresses.ERC721, snapshots, registerer),
+		erc1155Metrics:                   jobs.NewERC1155(clients, log, checkInterval, namespace, constLabels, addresses.ERC1155, snapshots, registerer),
+		erc4626Metrics:                   jobs.NewERC4626(clients, log, checkInterval, namespace, constLabels, addresses.ERC4626, tokens, snapshots, registerer),
+		lidoWithdrawalQueueERC721Metrics: jobs.NewLidoWithdrawalQueueERC721(clients, log, checkInterval, namespace, constLabels, addresses.LidoWithdrawalQueueERC721, tokens, snapshots, registerer),
+		uniswapPairMetrics:               jobs.NewUniswapPair(clients, log, checkInterval, namespace, constLabels, addresses.UniswapPair, snapshots, registerer),
+		uniswapV3PoolMetrics:             jobs.NewUniswapV3Pool(clients, log, checkInterval, namespace, constLabels, addresses.UniswapV3Pool, tokens, snapshots, registerer),
+		chainlinkDataFeedMetrics:         jobs.NewChainlinkDataFeed(clients, log, checkInterval, namespace, constLabels, addresses.ChainlinkDataFeed, snapshots, registerer),
+		erc4337Metrics:                   jobs.NewERC4337(clients, log, checkInterval, namespace, constLabels, addresses.ERC4337, snapshots, registerer),
+		contractCallMetrics:              jobs.NewContractCall(clients, log, checkInterval, namespace, constLabels, addresses.ContractCall, snapshots, registerer),
+		storageSlotMetrics:               jobs.NewStorageSlot(clients, log, checkInterval, namespace, constLabels, addresses.StorageSlot, snapshots, registerer),
+		proxyMetrics:                     jobs.NewProxy(clients, log, checkInterval, namespace, constLabels, addresses.Proxy, snapshots, registerer),
+		safeMetrics:                      jobs.NewSafe(clients, log, checkInterval, namespace, constLabels, addresses.Safe, snapshots, registerer),
+		lstRateMetrics:                   jobs.NewLSTRate(clients, log, checkInterval, namespace, constLabels, addresses.LSTRate, tokens, snapshots, registerer),
+		lendingPositionMetrics:           jobs.NewLendingPosition(clients, log, checkInterval, namespace, constLabels, addresses.LendingPosition, snapshots, registerer),
+		logsMetrics:                      jobs.NewLogs(clients, log, checkInterval, namespace, constLabels, addresses.Logs, tokens, stateDir, snapshots, registerer),
+		networkMetrics:                   jobs.NewNetwork(clients, log, checkInterval, namespace, constLabels, network, registerer),
 
-		enabledJobs: make(map[string]bool, 8),
+		enabledJobs: make(map[string]bool, 19),
 	}
 
 	m.log.Info("Enabling address metrics")
@@ -58,6 +102,10 @@ func NewMetrics(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 		m.enabledJobs[m.erc20Metrics.Name()] = true
 	}
 
+	if len(addresses.ERC20Allowance) > 0 {
+		m.enabledJobs[m.erc20AllowanceMetrics.Name()] = true
+	}
+
 	if len(addresses.ERC721) > 0 {
 		m.enabledJobs[m.erc721Metrics.Name()] = true
 	}
@@ -78,6 +126,10 @@ func NewMetrics(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 		m.enabledJobs[m.uniswapPairMetrics.Name()] = true
 	}
 
+	if len(addresses.UniswapV3Pool) > 0 {
+		m.enabledJobs[m.uniswapV3PoolMetrics.Name()] = true
+	}
+
 	if len(addresses.ChainlinkDataFeed) > 0 {
 		m.enabledJobs[m.chainlinkDataFeedMetrics.Name()] = true
 	}
@@ -86,6 +138,38 @@ func NewMetrics(clients []api.ExecutionClient, log logrus.FieldLogger, checkInte
 		m.enabledJobs[m.erc4337Metrics.Name()] = true
 	}
 
+	if len(addresses.ContractCall) > 0 {
+		m.enabledJobs[m.contractCallMetrics.Name()] = true
+	}
+
+	if len(addresses.StorageSlot) > 0 {
+		m.enabledJobs[m.storageSlotMetrics.Name()] = true
+	}
+
+	if len(addresses.Proxy) > 0 {
+		m.enabledJobs[m.proxyMetrics.Name()] = true
+	}
+
+	if len(addresses.Safe) > 0 {
+		m.enabledJobs[m.safeMetrics.Name()] = true
+	}
+
+	if len(addresses.LSTRate) > 0 {
+		m.enabledJobs[m.lstRateMetrics.Name()] = true
+	}
+
+	if len(addresses.LendingPosition) > 0 {
+		m.enabledJobs[m.lendingPositionMetrics.Name()] = true
+	}
+
+	if len(addresses.Logs) > 0 {
+		m.enabledJobs[m.logsMetrics.Name()] = true
+	}
+
+	if network.Enabled {
+		m.enabledJobs[m.networkMetrics.Name()] = true
+	}
+
 	return m
 }
 
@@ -98,6 +182,10 @@ func (m *metrics) StartAsync(ctx context.Context) {
 		go m.erc20Metrics.Start(ctx)
 	}
 
+	if m.enabledJobs[m.erc20AllowanceMetrics.Name()] {
+		go m.erc20AllowanceMetrics.Start(ctx)
+	}
+
 	if m.enabledJobs[m.erc721Metrics.Name()] {
 		go m.erc721Metrics.Start(ctx)
 	}
@@ -118,6 +206,10 @@ func (m *metrics) StartAsync(ctx context.Context) {
 		go m.uniswapPairMetrics.Start(ctx)
 	}
 
+	if m.enabledJobs[m.uniswapV3PoolMetrics.Name()] {
+		go m.uniswapV3PoolMetrics.Start(ctx)
+	}
+
 	if m.enabledJobs[m.chainlinkDataFeedMetrics.Name()] {
 		go m.chainlinkDataFeedMetrics.Start(ctx)
 	}
@@ -126,5 +218,82 @@ func (m *metrics) StartAsync(ctx context.Context) {
 		go m.erc4337Metrics.Start(ctx)
 	}
 
+	if m.enabledJobs[m.contractCallMetrics.Name()] {
+		go m.contractCallMetrics.Start(ctx)
+	}
+
+	if m.enabledJobs[m.storageSlotMetrics.Name()] {
+		go m.storageSlotMetrics.Start(ctx)
+	}
+
+	if m.enabledJobs[m.proxyMetrics.Name()] {
+		go m.proxyMetrics.Start(ctx)
+	}
+
+	if m.enabledJobs[m.safeMetrics.Name()] {
+		go m.safeMetrics.Start(ctx)
+	}
+
+	if m.enabledJobs[m.lstRateMetrics.Name()] {
+		go m.lstRateMetrics.Start(ctx)
+	}
+
+	if m.enabledJobs[m.lendingPositionMetrics.Name()] {
+		go m.lendingPositionMetrics.Start(ctx)
+	}
+
+	if m.enabledJobs[m.logsMetrics.Name()] {
+		go m.logsMetrics.Start(ctx)
+	}
+
+	if m.enabledJobs[m.networkMetrics.Name()] {
+		go m.networkMetrics.Start(ctx)
+	}
+
 	m.log.Info("Started metrics exporter jobs")
 }
+
+func (m *metrics) jobs() []job {
+	return []job{
+		&m.accountMetrics,
+		&m.erc20Metrics,
+		&m.erc20AllowanceMetrics,
+		&m.erc721Metrics,
+		&m.erc1155Metrics,
+		&m.erc4626Metrics,
+		&m.lidoWithdrawalQueueERC721Metrics,
+		&m.uniswapPairMetrics,
+		&m.uniswapV3PoolMetrics,
+		&m.chainlinkDataFeedMetrics,
+		&m.erc4337Metrics,
+		&m.contractCallMetrics,
+		&m.storageSlotMetrics,
+		&m.proxyMetrics,
+		&m.safeMetrics,
+		&m.lstRateMetrics,
+		&m.lendingPositionMetrics,
+		&m.logsMetrics,
+		&m.networkMetrics,
+	}
+}
+
+func (m *metrics) Jobs() []JobStatus {
+	result := make([]JobStatus, 0, len(m.enabledJobs))
+
+	for _, j := range m.jobs() {
+		if !m.enabledJobs[j.Name()] {
+			continue
+		}
+
+		result = append(result, JobStatus{
+			Name:     j.Name(),
+			LastTick: j.LastTick(),
+		})
+	}
+
+	sort.Slice(result, func(i, k int) bool {
+		return result[i].Name < result[k].Name
+	})
+
+	return result
+}
@@ -0,0 +1,70 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/alerts"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+)
+
+// TestNewMetrics_Registries checks the metrics of several exporters can live
+// in one process as long as each has a registry of its own.
+func TestNewMetrics_Registries(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	addresses := &Addresses{
+		Account: []*jobs.AddressAccount{{Name: "relayer", Address: testHolder1Address}},
+	}
+
+	registries := []*prometheus.Registry{prometheus.NewRegistry(), prometheus.NewRegistry()}
+
+	for _, registry := range registries {
+		require.NotPanics(t, func() {
+			httpMetrics := api.NewMetrics("registries_http", registry)
+			client := api.NewExecutionClient(log, httpMetrics, testNodeName1, testNodeURL, nil, time.Second)
+
+			alerting, err := alerts.NewManager(log, &alerts.Config{}, "registries", nil, registry)
+			require.NoError(t, err)
+
+			NewMetrics([]api.ExecutionClient{client}, log, time.Minute, "registries", nil, addresses, &jobs.NetworkConfig{}, "", alerting, nil, registry)
+		})
+	}
+
+	for _, registry := range registries {
+		// Registering a collector under a name the jobs already use fails.
+		balance := prometheus.NewGauge(prometheus.GaugeOpts{Name: "registries_account_balance", Help: "balance"})
+		assert.Error(t, registry.Register(balance))
+	}
+
+	count, err := testutil.GatherAndCount(prometheus.DefaultGatherer, "registries_account_balance")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestMetrics_Jobs(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	addresses := &Addresses{
+		Account: []*jobs.AddressAccount{{Name: "relayer", Address: testHolder1Address}},
+		ERC20:   []*jobs.AddressERC20{{Name: "treasury", Address: testHolder1Address, Contract: testHolder2Address}},
+	}
+
+	m := NewMetrics(nil, log, time.Minute, "jobs", nil, addresses, &jobs.NetworkConfig{Enabled: true}, "", nil, nil, prometheus.NewRegistry())
+
+	// Only enabled jobs are listed, none has completed a tick before StartAsync.
+	assert.Equal(t, []JobStatus{
+		{Name: jobs.NameAccount},
+		{Name: jobs.NameERC20},
+		{Name: jobs.NameNetwork},
+	}, m.Jobs())
+}
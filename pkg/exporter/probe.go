@@ -0,0 +1,233 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+)
+
+const (
+	probeParamType   = "type"
+	probeParamModule = "module"
+	probeParamName   = "name"
+
+	// probeTimeoutOffset leaves time to write the response before Prometheus gives up on the scrape.
+	probeTimeoutOffset = 500 * time.Millisecond
+)
+
+// probeHandler serves /probe, running the job of the address described by
+// the request parameters into a registry of its own.
+type probeHandler struct {
+	log    logrus.FieldLogger
+	config *jobs.ProbeConfig
+	prober *jobs.Prober
+}
+
+func newProbeHandler(log logrus.FieldLogger, config *jobs.ProbeConfig, prober *jobs.Prober) *probeHandler {
+	return &probeHandler{
+		log:    log.WithField("component", "probe"),
+		config: config,
+		prober: prober,
+	}
+}
+
+func (h *probeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	addressType, params, timeout, err := h.resolve(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	ctx := r.Context()
+
+	if timeout = scrapeTimeout(r, timeout); timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	registry := prometheus.NewRegistry()
+	start := time.Now()
+
+	if err := h.prober.Probe(ctx, addressType, params, registry); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	duration := time.Since(start)
+
+	success, err := probeSucceeded(registry)
+	if err != nil {
+		h.log.WithError(err).Debug("Failed to gather probe metrics")
+	}
+
+	probeSuccess := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_success",
+		Help: "Whether the probe read the address from every execution node without errors.",
+	})
+	probeDuration := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_duration_seconds",
+		Help: "How long the probe took to complete in seconds.",
+	})
+
+	if success {
+		probeSuccess.Set(1)
+	}
+
+	probeDuration.Set(duration.Seconds())
+
+	registry.MustRegister(probeSuccess, probeDuration)
+
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+// resolve merges the defaults of the selected module with the parameters of
+// the request, which take precedence.
+func (h *probeHandler) resolve(query map[string][]string) (string, map[string]any, time.Duration, error) {
+	addressType := first(query[probeParamType])
+	moduleName := first(query[probeParamModule])
+	timeout := h.config.Timeout
+	params := make(map[string]any)
+
+	if moduleName != "" {
+		module, ok := h.config.Modules[moduleName]
+		if !ok {
+			return "", nil, 0, fmt.Errorf("unknown module %q", moduleName)
+		}
+
+		if addressType != "" && addressType != module.Type {
+			return "", nil, 0, fmt.Errorf("type %q does not match the type %q of module %s", addressType, module.Type, moduleName)
+		}
+
+		addressType = module.Type
+
+		if module.Timeout > 0 {
+			timeout = module.Timeout
+		}
+
+		maps.Copy(params, module.Params)
+	}
+
+	if addressType == "" {
+		return "", nil, 0, fmt.Errorf("the %s or %s parameter is required", probeParamType, probeParamModule)
+	}
+
+	for key, values := range query {
+		if key == probeParamType || key == probeParamModule || len(values) == 0 {
+			continue
+		}
+
+		if len(values) == 1 {
+			params[key] = probeParamValue(values[0])
+
+			continue
+		}
+
+		list := make([]any, 0, len(values))
+		for _, value := range values {
+			list = append(list, probeParamValue(value))
+		}
+
+		params[key] = list
+	}
+
+	if _, ok := params[probeParamName]; !ok {
+		params[probeParamName] = addressType
+		if moduleName != "" {
+			params[probeParamName] = moduleName
+		}
+	}
+
+	return addressType, params, timeout, nil
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// probeParamValue types a request parameter the way YAML would, except that
+// hex strings such as addresses are kept as strings.
+func probeParamValue(value string) any {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+
+	if !strings.HasPrefix(value, "0x") {
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+
+		if f, err := strconv.ParseFloat(value, 64); err == nil && strings.ContainsAny(value, ".eE") {
+			return f
+		}
+	}
+
+	return value
+}
+
+// scrapeTimeout bounds the timeout by the scrape timeout Prometheus sends
+// with the request.
+func scrapeTimeout(r *http.Request, timeout time.Duration) time.Duration {
+	header := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds")
+	if header == "" {
+		return timeout
+	}
+
+	seconds, err := strconv.ParseFloat(header, 64)
+	if err != nil || seconds <= 0 {
+		return timeout
+	}
+
+	scrape := time.Duration(seconds*float64(time.Second)) - probeTimeoutOffset
+	if scrape <= 0 {
+		scrape = time.Duration(seconds * float64(time.Second))
+	}
+
+	if timeout <= 0 || scrape < timeout {
+		return scrape
+	}
+
+	return timeout
+}
+
+// probeSucceeded reports whether no errors_total counter of the probed job
+// was incremented.
+func probeSucceeded(gatherer prometheus.Gatherer) (bool, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return false, err
+	}
+
+	for _, family := range families {
+		if !strings.HasSuffix(family.GetName(), "_errors_total") {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			if metric.GetCounter().GetValue() > 0 {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
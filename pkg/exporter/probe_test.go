@@ -0,0 +1,196 @@
+package exporter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+)
+
+// newTestNode starts a JSON-RPC server answering every call with the given
+// result, or failing every request when result is empty.
+func newTestNode(t *testing.T, result string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if result == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		request := struct {
+			ID json.RawMessage `json:"id"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(request.ID) + `,"result":"` + result + `"}`))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestProbeHandler(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	metrics := api.NewMetrics("probe_handler_http", prometheus.NewRegistry())
+
+	healthy := newTestNode(t, "0x10")
+	failing := newTestNode(t, "")
+
+	config := &jobs.ProbeConfig{
+		Enabled: true,
+		Timeout: 5 * time.Second,
+		Modules: map[string]*jobs.ProbeModule{
+			"hot_wallet": {
+				Type:   "account",
+				Params: map[string]any{"address": testHolder1Address},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		target      string
+		wantCode    int
+		wantContent []string
+	}{
+		{
+			name:     "address from parameters",
+			url:      healthy.URL,
+			target:   "/probe?type=account&address=" + testHolder2Address + "&name=relayer",
+			wantCode: http.StatusOK,
+			wantContent: []string{
+				`probe_handler_account_balance{address="` + testHolder2Address + `",execution="node",name="relayer"} 16`,
+				"probe_success 1",
+			},
+		},
+		{
+			name:     "address from module",
+			url:      healthy.URL,
+			target:   "/probe?module=hot_wallet",
+			wantCode: http.StatusOK,
+			wantContent: []string{
+				`probe_handler_account_balance{address="` + testHolder1Address + `",execution="node",name="hot_wallet"} 16`,
+				"probe_success 1",
+			},
+		},
+		{
+			name:     "failing node",
+			url:      failing.URL,
+			target:   "/probe?type=account&address=" + testHolder1Address,
+			wantCode: http.StatusOK,
+			wantContent: []string{
+				"probe_handler_account_errors_total",
+				"probe_success 0",
+			},
+		},
+		{
+			name:        "missing type",
+			url:         healthy.URL,
+			target:      "/probe?address=" + testHolder1Address,
+			wantCode:    http.StatusBadRequest,
+			wantContent: []string{"the type or module parameter is required"},
+		},
+		{
+			name:        "unknown module",
+			url:         healthy.URL,
+			target:      "/probe?module=cold_wallet",
+			wantCode:    http.StatusBadRequest,
+			wantContent: []string{`unknown module "cold_wallet"`},
+		},
+		{
+			name:        "type mismatching module",
+			url:         healthy.URL,
+			target:      "/probe?module=hot_wallet&type=erc20",
+			wantCode:    http.StatusBadRequest,
+			wantContent: []string{`type "erc20" does not match the type "account" of module hot_wallet`},
+		},
+		{
+			name:        "unknown field",
+			url:         healthy.URL,
+			target:      "/probe?type=account&holder=" + testHolder1Address,
+			wantCode:    http.StatusBadRequest,
+			wantContent: []string{"invalid address"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := api.NewExecutionClient(log, metrics, "node", tt.url, nil, time.Second)
+			handler := newProbeHandler(log, config, jobs.NewProber([]api.ExecutionClient{client}, log, "probe_handler", nil))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			require.Equal(t, tt.wantCode, rec.Code, rec.Body.String())
+
+			for _, content := range tt.wantContent {
+				assert.Contains(t, rec.Body.String(), content)
+			}
+		})
+	}
+}
+
+func TestProbeParamValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  any
+	}{
+		{value: testHolder1Address, want: testHolder1Address},
+		{value: "0x10", want: "0x10"},
+		{value: "true", want: true},
+		{value: "42", want: int64(42)},
+		{value: "0.5", want: 0.5},
+		{value: "115792089237316195423570985008687907853269984665640564039457584007913129639935", want: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{value: "1h", want: "1h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, probeParamValue(tt.value))
+		})
+	}
+}
+
+func TestScrapeTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{name: "no header", timeout: 10 * time.Second, want: 10 * time.Second},
+		{name: "shorter scrape timeout", header: "5", timeout: 10 * time.Second, want: 4500 * time.Millisecond},
+		{name: "longer scrape timeout", header: "30", timeout: 10 * time.Second, want: 10 * time.Second},
+		{name: "invalid header", header: "soon", timeout: 10 * time.Second, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/probe", nil)
+			if tt.header != "" {
+				r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", tt.header)
+			}
+
+			assert.Equal(t, tt.want, scrapeTimeout(r, tt.timeout))
+		})
+	}
+}
@@ -0,0 +1,395 @@
+// Package snapshot keeps the latest value of every tracked address per
+// execution node and serves it as JSON.
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+)
+
+// Update is a value read for an address from an execution node.
+type Update struct {
+	Job       string
+	Name      string
+	Execution string
+	Address   string
+	Contract  string
+	Symbol    string
+	// Key distinguishes the values of addresses that export more than one,
+	// such as token IDs, storage slots or contract call outputs.
+	Key   string
+	Value float64
+	// Info replaces the non-numeric values of the address, such as the
+	// implementation of a proxy, when set.
+	Info map[string]string
+}
+
+// Address is the snapshot of a tracked address.
+type Address struct {
+	Name     string           `json:"name"`
+	Address  string           `json:"address,omitempty"`
+	Contract string           `json:"contract,omitempty"`
+	Symbol   string           `json:"symbol,omitempty"`
+	Nodes    map[string]*Node `json:"nodes"`
+}
+
+// Node is the latest value of an address read from an execution node.
+// Non-finite values are encoded as strings.
+type Node struct {
+	Value              any               `json:"value,omitempty"`
+	Values             map[string]any    `json:"values,omitempty"`
+	Info               map[string]string `json:"info,omitempty"`
+	BlockNumber        uint64            `json:"blockNumber,omitempty"`
+	Timestamp          *time.Time        `json:"timestamp,omitempty"`
+	LastError          string            `json:"lastError,omitempty"`
+	LastErrorTimestamp *time.Time        `json:"lastErrorTimestamp,omitempty"`
+}
+
+// Head is the latest block number of an execution node and when it was last
+// read successfully or failed.
+type Head struct {
+	BlockNumber uint64
+	UpdatedAt   time.Time
+	LastError   string
+	LastErrorAt time.Time
+}
+
+// Reachable reports whether the last attempt to read the block number of the
+// execution node succeeded.
+func (h Head) Reachable() bool {
+	return !h.UpdatedAt.IsZero() && !h.UpdatedAt.Before(h.LastErrorAt)
+}
+
+// Store holds the latest value of every tracked address per execution node.
+// A nil Store ignores every call.
+type Store struct {
+	log logrus.FieldLogger
+
+	mu        sync.RWMutex
+	addresses map[addressKey]*address
+	heads     map[string]*Head
+}
+
+type addressKey struct {
+	job  string
+	name string
+}
+
+type address struct {
+	address  string
+	contract string
+	symbol   string
+	nodes    map[string]*node
+}
+
+type node struct {
+	value       *float64
+	values      map[string]float64
+	info        map[string]string
+	blockNumber uint64
+	updatedAt   time.Time
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// NewStore returns a new empty Store.
+func NewStore(log logrus.FieldLogger) *Store {
+	return &Store{
+		log:       log.WithField("component", "snapshot"),
+		addresses: make(map[addressKey]*address),
+		heads:     make(map[string]*Head),
+	}
+}
+
+func (s *Store) node(job, name, execution string) (*address, *node) {
+	key := addressKey{job: job, name: name}
+
+	a, ok := s.addresses[key]
+	if !ok {
+		a = &address{nodes: make(map[string]*node)}
+		s.addresses[key] = a
+	}
+
+	n, ok := a.nodes[execution]
+	if !ok {
+		n = &node{}
+		a.nodes[execution] = n
+	}
+
+	return a, n
+}
+
+// Set records a value read for an address, together with the block number the
+// execution node was last seen at.
+func (s *Store) Set(update *Update, now time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, n := s.node(update.Job, update.Name, update.Execution)
+
+	a.address = update.Address
+	a.contract = update.Contract
+
+	if update.Symbol != "" {
+		a.symbol = update.Symbol
+	}
+
+	switch {
+	case update.Info != nil:
+		n.info = maps.Clone(update.Info)
+	case update.Key == "":
+		value := update.Value
+		n.value = &value
+	default:
+		if n.values == nil {
+			n.values = make(map[string]float64)
+		}
+
+		n.values[update.Key] = update.Value
+	}
+
+	if head, ok := s.heads[update.Execution]; ok {
+		n.blockNumber = head.BlockNumber
+	}
+
+	n.updatedAt = now
+}
+
+// SetError records the last error reading an address from an execution node.
+func (s *Store) SetError(job, name, execution string, err error, now time.Time) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, n := s.node(job, name, execution)
+
+	n.lastError = err.Error()
+	n.lastErrorAt = now
+}
+
+// SetHead records the latest block number of an execution node.
+func (s *Store) SetHead(execution string, blockNumber uint64, now time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	head := s.head(execution)
+
+	head.BlockNumber = blockNumber
+	head.UpdatedAt = now
+}
+
+// SetHeadError records the last error reading the block number of an
+// execution node.
+func (s *Store) SetHeadError(execution string, err error, now time.Time) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	head := s.head(execution)
+
+	head.LastError = err.Error()
+	head.LastErrorAt = now
+}
+
+func (s *Store) head(execution string) *Head {
+	head, ok := s.heads[execution]
+	if !ok {
+		head = &Head{}
+		s.heads[execution] = head
+	}
+
+	return head
+}
+
+// Heads returns the head of every execution node polled at least once.
+func (s *Store) Heads() map[string]Head {
+	result := make(map[string]Head)
+
+	if s == nil {
+		return result
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for execution, head := range s.heads {
+		result[execution] = *head
+	}
+
+	return result
+}
+
+// Start follows the block number of the execution nodes every interval until
+// the context is cancelled.
+func (s *Store) Start(ctx context.Context, clients []api.ExecutionClient, interval time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.tick(ctx, clients)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx, clients)
+		}
+	}
+}
+
+func (s *Store) tick(ctx context.Context, clients []api.ExecutionClient) {
+	for _, client := range clients {
+		blockNumberStr, err := client.ETHBlockNumber(ctx)
+		if err != nil {
+			s.log.WithError(err).WithField("execution", client.Name()).Debug("Failed to get block number")
+			s.SetHeadError(client.Name(), err, time.Now())
+
+			continue
+		}
+
+		blockNumber, err := strconv.ParseUint(blockNumberStr, 0, 64)
+		if err != nil {
+			s.log.WithError(err).WithField("execution", client.Name()).Debug("Failed to parse block number")
+			s.SetHeadError(client.Name(), err, time.Now())
+
+			continue
+		}
+
+		s.SetHead(client.Name(), blockNumber, time.Now())
+	}
+}
+
+// Snapshot returns the addresses of every job type, sorted by name.
+func (s *Store) Snapshot() map[string][]*Address {
+	result := make(map[string][]*Address)
+
+	if s == nil {
+		return result
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for key, a := range s.addresses {
+		snapshot := &Address{
+			Name:     key.name,
+			Address:  a.address,
+			Contract: a.contract,
+			Symbol:   a.symbol,
+			Nodes:    make(map[string]*Node, len(a.nodes)),
+		}
+
+		for execution, n := range a.nodes {
+			snapshot.Nodes[execution] = n.snapshot()
+		}
+
+		result[key.job] = append(result[key.job], snapshot)
+	}
+
+	for _, addresses := range result {
+		sort.Slice(addresses, func(i, j int) bool {
+			return addresses[i].Name < addresses[j].Name
+		})
+	}
+
+	return result
+}
+
+func (n *node) snapshot() *Node {
+	result := &Node{
+		BlockNumber: n.blockNumber,
+		LastError:   n.lastError,
+	}
+
+	if n.value != nil {
+		result.Value = jsonValue(*n.value)
+	}
+
+	if len(n.values) > 0 {
+		result.Values = make(map[string]any, len(n.values))
+
+		for key, value := range n.values {
+			result.Values[key] = jsonValue(value)
+		}
+	}
+
+	if len(n.info) > 0 {
+		result.Info = maps.Clone(n.info)
+	}
+
+	if !n.updatedAt.IsZero() {
+		updatedAt := n.updatedAt
+		result.Timestamp = &updatedAt
+	}
+
+	if !n.lastErrorAt.IsZero() {
+		lastErrorAt := n.lastErrorAt
+		result.LastErrorTimestamp = &lastErrorAt
+	}
+
+	return result
+}
+
+// jsonValue returns the value as is, or as a string when JSON cannot encode it.
+func jsonValue(value float64) any {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	return value
+}
+
+// ServeHTTP writes the snapshot as JSON, limited to a single job type when
+// the type query parameter is set.
+func (s *Store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	snapshot := s.Snapshot()
+
+	if job := r.URL.Query().Get("type"); job != "" {
+		snapshot = map[string][]*Address{job: snapshot[job]}
+		if snapshot[job] == nil {
+			snapshot[job] = []*Address{}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		s.log.WithError(err).Debug("Failed to write snapshot")
+	}
+}
@@ -0,0 +1,197 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/api"
+)
+
+const (
+	testExecution = "geth"
+	testAddress   = "0x1111111111111111111111111111111111111111"
+	testContract  = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+)
+
+func testLogger() logrus.FieldLogger {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	return log
+}
+
+// blockNumberClient answers eth_blockNumber and panics on any other call.
+type blockNumberClient struct {
+	api.ExecutionClient
+
+	name        string
+	blockNumber string
+	err         error
+}
+
+func (c *blockNumberClient) Name() string {
+	return c.name
+}
+
+func (c *blockNumberClient) ETHBlockNumber(_ context.Context) (string, error) {
+	return c.blockNumber, c.err
+}
+
+func TestStore_Set(t *testing.T) {
+	store := NewStore(testLogger())
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	store.SetHead(testExecution, 1234, now)
+	store.Set(&Update{Job: "erc20", Name: "treasury", Execution: testExecution, Address: testAddress, Contract: testContract, Symbol: "USDC", Value: 250}, now)
+	store.Set(&Update{Job: "erc20", Name: "supply", Execution: testExecution, Contract: testContract, Symbol: "USDC", Value: 1000}, now)
+	store.Set(&Update{Job: "erc1155", Name: "items", Execution: testExecution, Key: "1", Value: 3}, now)
+	store.Set(&Update{Job: "erc1155", Name: "items", Execution: testExecution, Key: "2", Value: 5}, now)
+	store.SetError("erc1155", "items", "besu", errors.New("connection refused"), now)
+	store.Set(&Update{Job: "proxy", Name: "bridge", Execution: testExecution, Contract: testContract, Info: map[string]string{"implementation": testAddress}}, now)
+	store.Set(&Update{Job: "proxy", Name: "bridge", Execution: testExecution, Contract: testContract, Info: map[string]string{"implementation": testContract, "admin": testAddress}}, now)
+
+	snapshot := store.Snapshot()
+
+	require.Len(t, snapshot["erc20"], 2)
+	assert.Equal(t, "supply", snapshot["erc20"][0].Name)
+	assert.Equal(t, "treasury", snapshot["erc20"][1].Name)
+
+	treasury := snapshot["erc20"][1]
+	assert.Equal(t, testAddress, treasury.Address)
+	assert.Equal(t, testContract, treasury.Contract)
+	assert.Equal(t, "USDC", treasury.Symbol)
+	require.Contains(t, treasury.Nodes, testExecution)
+	assert.Equal(t, 250.0, treasury.Nodes[testExecution].Value)
+	assert.Equal(t, uint64(1234), treasury.Nodes[testExecution].BlockNumber)
+	assert.Equal(t, now, *treasury.Nodes[testExecution].Timestamp)
+
+	require.Len(t, snapshot["erc1155"], 1)
+	items := snapshot["erc1155"][0]
+	assert.Nil(t, items.Nodes[testExecution].Value)
+	assert.Equal(t, map[string]any{"1": 3.0, "2": 5.0}, items.Nodes[testExecution].Values)
+	assert.Equal(t, "connection refused", items.Nodes["besu"].LastError)
+	assert.Nil(t, items.Nodes["besu"].Timestamp)
+
+	// Info replaces the previous info instead of merging into it.
+	require.Len(t, snapshot["proxy"], 1)
+	bridge := snapshot["proxy"][0]
+	assert.Nil(t, bridge.Nodes[testExecution].Value)
+	assert.Equal(t, map[string]string{"implementation": testContract, "admin": testAddress}, bridge.Nodes[testExecution].Info)
+}
+
+func TestStore_Nil(t *testing.T) {
+	var store *Store
+
+	store.Set(&Update{Job: "erc20", Name: "treasury", Execution: testExecution, Value: 1}, time.Now())
+	store.SetError("erc20", "treasury", testExecution, errors.New("failed"), time.Now())
+	store.SetHead(testExecution, 1, time.Now())
+	store.SetHeadError(testExecution, errors.New("failed"), time.Now())
+	store.Start(context.Background(), nil, time.Second)
+
+	assert.Empty(t, store.Snapshot())
+	assert.Empty(t, store.Heads())
+}
+
+func TestStore_tick(t *testing.T) {
+	store := NewStore(testLogger())
+
+	store.tick(context.Background(), []api.ExecutionClient{
+		&blockNumberClient{name: "geth", blockNumber: "0x10"},
+		&blockNumberClient{name: "besu", err: errors.New("connection refused")},
+		&blockNumberClient{name: "nethermind", blockNumber: "0xzz"},
+	})
+
+	heads := store.Heads()
+	require.Len(t, heads, 3)
+
+	assert.Equal(t, uint64(16), heads["geth"].BlockNumber)
+	assert.True(t, heads["geth"].Reachable())
+	assert.Equal(t, "connection refused", heads["besu"].LastError)
+	assert.False(t, heads["besu"].Reachable())
+	assert.False(t, heads["nethermind"].Reachable())
+}
+
+func TestStore_ServeHTTP(t *testing.T) {
+	store := NewStore(testLogger())
+	now := time.Now()
+
+	store.Set(&Update{Job: "account", Name: "relayer", Execution: testExecution, Address: testAddress, Value: 1e18}, now)
+	store.Set(&Update{Job: "lending_position", Name: "position", Execution: testExecution, Value: math.Inf(1)}, now)
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		wantCode int
+		wantJobs []string
+	}{
+		{
+			name:     "all job types",
+			method:   http.MethodGet,
+			target:   "/api/v1/addresses",
+			wantCode: http.StatusOK,
+			wantJobs: []string{"account", "lending_position"},
+		},
+		{
+			name:     "filtered by type",
+			method:   http.MethodGet,
+			target:   "/api/v1/addresses?type=account",
+			wantCode: http.StatusOK,
+			wantJobs: []string{"account"},
+		},
+		{
+			name:     "unknown type",
+			method:   http.MethodGet,
+			target:   "/api/v1/addresses?type=erc20",
+			wantCode: http.StatusOK,
+			wantJobs: []string{"erc20"},
+		},
+		{
+			name:     "method not allowed",
+			method:   http.MethodPost,
+			target:   "/api/v1/addresses",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			store.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			require.Equal(t, tt.wantCode, rec.Code)
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			body := map[string][]map[string]any{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+			jobs := make([]string, 0, len(body))
+			for job := range body {
+				jobs = append(jobs, job)
+			}
+
+			assert.ElementsMatch(t, tt.wantJobs, jobs)
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	store.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/addresses?type=lending_position", nil))
+
+	// Non-finite values cannot be encoded as JSON numbers.
+	assert.Contains(t, rec.Body.String(), `"value":"+Inf"`)
+}
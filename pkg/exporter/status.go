@@ -0,0 +1,265 @@
+package exporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+var statusTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"since": func(now, t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+
+		return now.Sub(t).Truncate(time.Second).String() + " ago"
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>Ethereum Address Metrics Exporter</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Ethereum Address Metrics Exporter</h1>
+<p>Ready: {{ if .Problems }}no{{ else }}yes{{ end }}</p>
+{{- if .Problems }}
+<ul>
+{{- range .Problems }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+<p>Started: {{ .StartedAt.Format "2006-01-02T15:04:05Z07:00" }} ({{ since .Now .StartedAt }})</p>
+<p>Check interval: {{ .CheckInterval }}</p>
+<p>Config hash: <code>{{ .ConfigHash }}</code></p>
+<h2>Execution nodes</h2>
+<table>
+<tr><th>Name</th><th>Reachable</th><th>Block number</th><th>Last seen</th><th>Last error</th><th>Errors</th></tr>
+{{- range .Nodes }}
+<tr><td>{{ .Name }}</td><td>{{ if .Head.Reachable }}yes{{ else }}no{{ end }}</td><td>{{ if .Head.BlockNumber }}{{ .Head.BlockNumber }}{{ end }}</td><td>{{ since $.Now .Head.UpdatedAt }}</td><td>{{ .Head.LastError }}</td><td>{{ .Errors }}</td></tr>
+{{- end }}
+</table>
+<h2>Jobs</h2>
+<table>
+<tr><th>Name</th><th>Last tick</th><th>Errors</th></tr>
+{{- range .Jobs }}
+<tr><td>{{ .Name }}</td><td>{{ since $.Now .LastTick }}</td><td>{{ .Errors }}</td></tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+// statusHandler serves the liveness, readiness and status endpoints.
+type statusHandler struct {
+	log           logrus.FieldLogger
+	namespace     string
+	checkInterval time.Duration
+	configHash    string
+	startedAt     time.Time
+	nodes         []string
+	metrics       Metrics
+	snapshots     *snapshot.Store
+	gatherer      prometheus.Gatherer
+}
+
+type statusNode struct {
+	Name   string
+	Head   snapshot.Head
+	Errors float64
+}
+
+type statusJob struct {
+	JobStatus
+
+	Errors float64
+}
+
+type statusPage struct {
+	Now           time.Time
+	StartedAt     time.Time
+	CheckInterval time.Duration
+	ConfigHash    string
+	Problems      []string
+	Nodes         []statusNode
+	Jobs          []statusJob
+}
+
+func newStatusHandler(log logrus.FieldLogger, conf *Config, metrics Metrics, snapshots *snapshot.Store, gatherer prometheus.Gatherer) *statusHandler {
+	nodes := make([]string, 0, len(conf.Execution))
+	for _, node := range conf.Execution {
+		nodes = append(nodes, node.Name)
+	}
+
+	hash, err := configHash(conf)
+	if err != nil {
+		log.WithError(err).Warn("Failed to hash config")
+	}
+
+	return &statusHandler{
+		log:           log.WithField("component", "status"),
+		namespace:     conf.GlobalConfig.Namespace,
+		checkInterval: conf.GlobalConfig.CheckInterval,
+		configHash:    hash,
+		startedAt:     time.Now(),
+		nodes:         nodes,
+		metrics:       metrics,
+		snapshots:     snapshots,
+		gatherer:      gatherer,
+	}
+}
+
+// configHash returns the SHA-256 of the config, to tell which config a
+// running exporter has loaded.
+func configHash(conf *Config) (string, error) {
+	data, err := yaml.Marshal(conf)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// problems returns why the exporter is not ready, empty once at least one
+// execution node is reachable and every enabled job completed a tick.
+func (h *statusHandler) problems(heads map[string]snapshot.Head, statuses []JobStatus) []string {
+	var problems []string
+
+	reachable := false
+
+	for _, name := range h.nodes {
+		if heads[name].Reachable() {
+			reachable = true
+
+			break
+		}
+	}
+
+	if !reachable {
+		problems = append(problems, "no execution node is reachable")
+	}
+
+	for _, status := range statuses {
+		if status.LastTick.IsZero() {
+			problems = append(problems, fmt.Sprintf("job %s has not completed its first tick", status.Name))
+		}
+	}
+
+	return problems
+}
+
+// serveHealthz reports the process is up.
+func (h *statusHandler) serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// serveReadyz reports whether the exporter serves complete metrics.
+func (h *statusHandler) serveReadyz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	problems := h.problems(h.snapshots.Heads(), h.metrics.Jobs())
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(strings.Join(problems, "\n") + "\n"))
+
+		return
+	}
+
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// serveStatus renders the execution nodes and jobs as a HTML page.
+func (h *statusHandler) serveStatus(w http.ResponseWriter, _ *http.Request) {
+	heads := h.snapshots.Heads()
+	statuses := h.metrics.Jobs()
+
+	jobErrors, nodeErrors := h.errors(statuses)
+
+	page := &statusPage{
+		Now:           time.Now(),
+		StartedAt:     h.startedAt,
+		CheckInterval: h.checkInterval,
+		ConfigHash:    h.configHash,
+		Problems:      h.problems(heads, statuses),
+		Nodes:         make([]statusNode, 0, len(h.nodes)),
+		Jobs:          make([]statusJob, 0, len(statuses)),
+	}
+
+	for _, name := range h.nodes {
+		page.Nodes = append(page.Nodes, statusNode{
+			Name:   name,
+			Head:   heads[name],
+			Errors: nodeErrors[name],
+		})
+	}
+
+	for _, status := range statuses {
+		page.Jobs = append(page.Jobs, statusJob{
+			JobStatus: status,
+			Errors:    jobErrors[status.Name],
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := statusTemplate.Execute(w, page); err != nil {
+		h.log.WithError(err).Debug("Failed to render status page")
+	}
+}
+
+// errors sums the errors_total counters of the jobs, per job and per
+// execution node.
+func (h *statusHandler) errors(statuses []JobStatus) (map[string]float64, map[string]float64) {
+	jobErrors := make(map[string]float64, len(statuses))
+	nodeErrors := make(map[string]float64, len(h.nodes))
+
+	families, err := h.gatherer.Gather()
+	if err != nil {
+		h.log.WithError(err).Debug("Failed to gather metrics")
+	}
+
+	names := make(map[string]string, len(statuses))
+	for _, status := range statuses {
+		names[prometheus.BuildFQName(h.namespace, status.Name, "errors_total")] = status.Name
+	}
+
+	for _, family := range families {
+		job, ok := names[family.GetName()]
+		if !ok {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			value := metric.GetCounter().GetValue()
+
+			jobErrors[job] += value
+
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == jobs.LabelExecution {
+					nodeErrors[label.GetValue()] += value
+				}
+			}
+		}
+	}
+
+	return jobErrors, nodeErrors
+}
@@ -0,0 +1,152 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/jobs"
+	"github.com/ethpandaops/ethereum-address-metrics-exporter/pkg/exporter/snapshot"
+)
+
+const testNodeName2 = "node-2"
+
+type testMetrics struct {
+	jobs []JobStatus
+}
+
+func (m *testMetrics) StartAsync(_ context.Context) {}
+
+func (m *testMetrics) Jobs() []JobStatus {
+	return m.jobs
+}
+
+func newTestStatusHandler(t *testing.T, metrics Metrics, snapshots *snapshot.Store) *statusHandler {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	registry := prometheus.NewRegistry()
+
+	errorsTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "status_" + jobs.NameAccount,
+		Name:      "errors_total",
+		Help:      "errors",
+	}, []string{jobs.LabelName, jobs.LabelExecution})
+	registry.MustRegister(errorsTotal)
+
+	errorsTotal.WithLabelValues("relayer", testNodeName1).Add(2)
+	errorsTotal.WithLabelValues("treasury", testNodeName1).Inc()
+	errorsTotal.WithLabelValues("relayer", testNodeName2).Inc()
+
+	conf := &Config{
+		GlobalConfig: GlobalConfig{Namespace: "status", CheckInterval: time.Minute},
+		Execution: []*ExecutionNode{
+			{Name: testNodeName1, URL: testNodeURL},
+			{Name: testNodeName2, URL: testNodeURL},
+		},
+	}
+
+	return newStatusHandler(log, conf, metrics, snapshots, registry)
+}
+
+func TestStatusHandler_Readyz(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		setup        func(store *snapshot.Store)
+		jobs         []JobStatus
+		wantCode     int
+		wantProblems []string
+	}{
+		{
+			name:     "node reachable and jobs ticked",
+			setup:    func(store *snapshot.Store) { store.SetHead(testNodeName2, 100, now) },
+			jobs:     []JobStatus{{Name: jobs.NameAccount, LastTick: now}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:         "no node polled yet",
+			setup:        func(_ *snapshot.Store) {},
+			jobs:         []JobStatus{{Name: jobs.NameAccount, LastTick: now}},
+			wantCode:     http.StatusServiceUnavailable,
+			wantProblems: []string{"no execution node is reachable"},
+		},
+		{
+			name: "node no longer reachable",
+			setup: func(store *snapshot.Store) {
+				store.SetHead(testNodeName1, 100, now.Add(-time.Minute))
+				store.SetHeadError(testNodeName1, errors.New("connection refused"), now)
+			},
+			jobs:         []JobStatus{{Name: jobs.NameAccount, LastTick: now}},
+			wantCode:     http.StatusServiceUnavailable,
+			wantProblems: []string{"no execution node is reachable"},
+		},
+		{
+			name:         "job without a tick",
+			setup:        func(store *snapshot.Store) { store.SetHead(testNodeName1, 100, now) },
+			jobs:         []JobStatus{{Name: jobs.NameAccount, LastTick: now}, {Name: jobs.NameERC20}},
+			wantCode:     http.StatusServiceUnavailable,
+			wantProblems: []string{"job erc20 has not completed its first tick"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := snapshot.NewStore(logrus.New())
+			tt.setup(store)
+
+			handler := newTestStatusHandler(t, &testMetrics{jobs: tt.jobs}, store)
+
+			rec := httptest.NewRecorder()
+			handler.serveReadyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tt.wantCode, rec.Code)
+
+			for _, problem := range tt.wantProblems {
+				assert.Contains(t, rec.Body.String(), problem)
+			}
+
+			rec = httptest.NewRecorder()
+			handler.serveHealthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
+
+func TestStatusHandler_Status(t *testing.T) {
+	store := snapshot.NewStore(logrus.New())
+	store.SetHead(testNodeName1, 21000000, time.Now())
+	store.SetHeadError(testNodeName2, errors.New("connection refused"), time.Now())
+
+	handler := newTestStatusHandler(t, &testMetrics{jobs: []JobStatus{{Name: jobs.NameAccount, LastTick: time.Now()}}}, store)
+
+	errorsByJob, errorsByNode := handler.errors(handler.metrics.Jobs())
+	assert.Equal(t, map[string]float64{jobs.NameAccount: 4}, errorsByJob)
+	assert.Equal(t, map[string]float64{testNodeName1: 3, testNodeName2: 1}, errorsByNode)
+
+	rec := httptest.NewRecorder()
+	handler.serveStatus(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "Ready: yes")
+	assert.Contains(t, body, "<td>node-1</td><td>yes</td><td>21000000</td>")
+	assert.Contains(t, body, "<td>node-2</td><td>no</td><td></td><td>never</td><td>connection refused</td><td>1</td>")
+	assert.Contains(t, body, "<td>account</td>")
+	assert.Contains(t, body, handler.configHash)
+	assert.Len(t, handler.configHash, 64)
+}